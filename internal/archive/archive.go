@@ -0,0 +1,173 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Separator joins an archive's path with the path of an entry inside it,
+// e.g. "vendor/lib.jar!META-INF/config.properties"
+const Separator = "!"
+
+// ErrLimit is returned when an archive exceeds the configured bounds; entries
+// visited before the limit was hit have already been passed to the callback
+var ErrLimit = errors.New("archive exceeds scan limits")
+
+// Limits bound how much of an archive is unpacked in memory
+type Limits struct {
+	// MaxBytes caps the total uncompressed size read from one top-level archive
+	MaxBytes int64
+
+	// MaxDepth caps how many archives deep nested archives are opened
+	MaxDepth int
+}
+
+// IsArchive reports whether a file name has a supported archive extension
+func IsArchive(name string) bool {
+	return kind(name) != ""
+}
+
+// Outer returns the path of the top-level file for an archive entry path
+func Outer(path string) string {
+	if i := strings.Index(path, Separator); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// kind classifies a file name by archive format
+func kind(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		return "tgz"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	case strings.HasSuffix(lower, ".gz"):
+		return "gz"
+	}
+	switch filepath.Ext(lower) {
+	case ".zip", ".jar", ".war", ".ear", ".apk", ".whl", ".nupkg":
+		return "zip"
+	}
+	return ""
+}
+
+// Walk calls fn with the path and content of every regular file in an
+// archive, opening nested archives up to limits.MaxDepth
+func Walk(name string, data []byte, limits Limits, fn func(path string, content []byte)) error {
+	w := &walker{limits: limits, remaining: limits.MaxBytes, fn: fn}
+	return w.walk(name, data, 1)
+}
+
+type walker struct {
+	limits    Limits
+	remaining int64
+	fn        func(path string, content []byte)
+}
+
+func (w *walker) walk(name string, data []byte, depth int) error {
+	switch kind(name) {
+	case "zip":
+		return w.walkZip(name, data, depth)
+	case "tar":
+		return w.walkTar(name, bytes.NewReader(data), depth)
+	case "tgz":
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		defer gz.Close()
+		return w.walkTar(name, gz, depth)
+	case "gz":
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		defer gz.Close()
+		inner := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		if gz.Name != "" {
+			inner = gz.Name
+		}
+		return w.entry(name, inner, gz, depth)
+	}
+	return nil
+}
+
+func (w *walker) walkZip(name string, data []byte, depth int) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		// Refuse entries that claim more than the budget before inflating them
+		if int64(file.UncompressedSize64) > w.remaining {
+			return ErrLimit
+		}
+		entry, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s%s%s: %w", name, Separator, file.Name, err)
+		}
+		err = w.entry(name, file.Name, entry, depth)
+		entry.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) walkTar(name string, r io.Reader, depth int) error {
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > w.remaining {
+			return ErrLimit
+		}
+		if err := w.entry(name, header.Name, reader, depth); err != nil {
+			return err
+		}
+	}
+}
+
+// entry reads one file within the byte budget and either descends into it or
+// hands it to the callback
+func (w *walker) entry(archiveName, entryName string, r io.Reader, depth int) error {
+	content, err := io.ReadAll(io.LimitReader(r, w.remaining+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s%s%s: %w", archiveName, Separator, entryName, err)
+	}
+	if int64(len(content)) > w.remaining {
+		return ErrLimit
+	}
+	w.remaining -= int64(len(content))
+
+	path := archiveName + Separator + strings.TrimPrefix(entryName, "./")
+	if IsArchive(entryName) {
+		if depth < w.limits.MaxDepth {
+			return w.walk(path, content, depth+1)
+		}
+		return nil
+	}
+	w.fn(path, content)
+	return nil
+}
@@ -0,0 +1,171 @@
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileName is the append-only audit log kept under .git/secretlint/
+const FileName = "audit.log"
+
+// shippedFile records how many bytes of the log were already shipped
+const shippedFile = "audit.shipped"
+
+// TokenEnv holds the bearer token sent when shipping the log
+const TokenEnv = "SECRETLINT_AUDIT_TOKEN"
+
+// Hook decisions
+const (
+	DecisionPassed  = "passed"
+	DecisionBlocked = "blocked"
+	DecisionPartial = "partial"
+	DecisionError   = "error"
+)
+
+// Entry records the outcome of one hook run. Secrets are identified by
+// fingerprint only.
+type Entry struct {
+	Time         time.Time `json:"time"`
+	Hook         string    `json:"hook"`
+	Repo         string    `json:"repo"`
+	User         string    `json:"user,omitempty"`
+	Host         string    `json:"host,omitempty"`
+	Branch       string    `json:"branch,omitempty"`
+	Head         string    `json:"head,omitempty"`
+	Ref          string    `json:"ref,omitempty"`
+	Files        int       `json:"files"`
+	Findings     int       `json:"findings"`
+	Fingerprints []string  `json:"fingerprints,omitempty"`
+	Decision     string    `json:"decision"`
+
+	// Bypassed is set when pushed commits contain secrets, meaning they were
+	// committed with --no-verify or without the pre-commit hook installed
+	Bypassed      bool     `json:"bypassed,omitempty"`
+	BypassCommits []string `json:"bypass_commits,omitempty"`
+}
+
+// Path returns the audit log location inside the given git directory
+func Path(gitDir string) string {
+	return filepath.Join(gitDir, "secretlint", FileName)
+}
+
+// Append writes an entry as one JSON line
+func Append(logPath string, entry Entry) error {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(logPath), err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Read returns every entry in the log
+func Read(logPath string) ([]Entry, error) {
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return parse(data)
+}
+
+func parse(data []byte) ([]Entry, error) {
+	var entries []Entry
+	lineScanner := bufio.NewScanner(bytes.NewReader(data))
+	lineScanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNum := 1; lineScanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(lineScanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("invalid audit log entry on line %d: %w", lineNum, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := lineScanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Ship posts entries not yet shipped to endpoint as newline-delimited JSON
+// and returns how many were sent
+func Ship(logPath, endpoint, token string) (int, error) {
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	offsetPath := filepath.Join(filepath.Dir(logPath), shippedFile)
+	offset := 0
+	if raw, err := os.ReadFile(offsetPath); err == nil {
+		offset, _ = strconv.Atoi(strings.TrimSpace(string(raw)))
+	}
+	if offset < 0 || offset > len(data) {
+		// The log was truncated or replaced; ship it all again
+		offset = 0
+	}
+
+	pending := data[offset:]
+	entries, err := parse(pending)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(pending))
+	if err != nil {
+		return 0, fmt.Errorf("invalid audit endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to ship audit log: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, fmt.Errorf("audit endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := os.WriteFile(offsetPath, []byte(strconv.Itoa(len(data))+"\n"), 0600); err != nil {
+		return 0, fmt.Errorf("failed to record shipped position: %w", err)
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+)
+
+// runReportAnnotate records triage outcomes in a JSON report, from --resolve
+// or edited into the report by a web UI or spreadsheet, and carries them
+// into the baseline and findings database so later scans honor them
+func runReportAnnotate(args []string) error {
+	flags := flag.NewFlagSet("report annotate", flag.ContinueOnError)
+	resolve := flags.String("resolve", "", "Comma-separated <fingerprint>=<status> pairs; status is rotated, false_positive, accepted or open")
+	note := flags.String("note", "", "Note stored with the resolutions given by --resolve")
+	output := flags.String("output", "", "Write the annotated report to this file instead of updating the input")
+	reportOnly := flags.Bool("report-only", false, "Only annotate the report; leave the baseline and findings database alone")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: secretlint report annotate [--resolve <fingerprint>=<status>,...] [--note text] [--output file] <findings.json>")
+	}
+	reportPath := flags.Arg(0)
+
+	r, err := report.Load(reportPath)
+	if err != nil {
+		return err
+	}
+	byFingerprint := make(map[string][]int)
+	for i, finding := range r.Findings {
+		byFingerprint[finding.Fingerprint] = append(byFingerprint[finding.Fingerprint], i)
+	}
+
+	now := time.Now().UTC()
+	by := ""
+	if name, email := scanner.NewGitDiffer().AuthorIdent(); email != "" {
+		by = fmt.Sprintf("%s <%s>", name, email)
+	}
+	for _, pair := range splitList(*resolve) {
+		eq := strings.Index(pair, "=")
+		if eq <= 0 {
+			return fmt.Errorf("invalid --resolve %q (use <fingerprint>=<status>)", pair)
+		}
+		fingerprint, status := pair[:eq], pair[eq+1:]
+		if !report.ValidResolution(status) {
+			return fmt.Errorf("invalid status %q for %s (use rotated, false_positive, accepted or open)", status, fingerprint)
+		}
+		indexes, ok := byFingerprint[fingerprint]
+		if !ok {
+			return fmt.Errorf("fingerprint %s is not in %s", fingerprint, reportPath)
+		}
+		for _, i := range indexes {
+			r.Findings[i].Resolution = &report.Resolution{Status: status, Note: *note, By: by, At: &now}
+		}
+	}
+	for _, finding := range r.Findings {
+		if resolution := finding.Resolution; resolution != nil && !report.ValidResolution(resolution.Status) {
+			return fmt.Errorf("finding %s in %s has resolution status %q (use rotated, false_positive, accepted or open)", finding.Fingerprint, reportPath, resolution.Status)
+		}
+	}
+
+	if *output == "" {
+		*output = reportPath
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *output, err)
+	}
+	if err := r.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	fmt.Printf("📝 Wrote %s\n", *output)
+
+	if *reportOnly {
+		return nil
+	}
+	return applyResolutions(r, now)
+}
+
+// applyResolutions accepts every finding resolved as rotated, false_positive
+// or accepted into the baseline, removes reopened ones, and records the
+// outcomes in the findings database when settings.store.enabled is on
+func applyResolutions(r *report.Report, now time.Time) error {
+	baseline, err := scanner.LoadBaseline(scanner.DefaultBaselineFile)
+	if err != nil {
+		return err
+	}
+	var resolved []report.Finding
+	seen := make(map[string]bool)
+	for _, finding := range r.Findings {
+		if finding.Resolution != nil && !seen[finding.Fingerprint] {
+			seen[finding.Fingerprint] = true
+			resolved = append(resolved, finding)
+		}
+	}
+	if len(resolved) == 0 {
+		fmt.Println("📭 No resolutions in the report - baseline unchanged")
+		return nil
+	}
+
+	added, removed := 0, 0
+	for _, finding := range resolved {
+		resolution := finding.Resolution
+		if resolution.Status == report.ResolutionOpen {
+			if baseline.Remove(finding.Fingerprint) {
+				removed++
+			}
+			continue
+		}
+		reason := resolution.Status
+		if resolution.Note != "" {
+			reason += ": " + resolution.Note
+		}
+		entry := scanner.BaselineEntry{
+			Fingerprint: finding.Fingerprint,
+			RuleID:      finding.RuleID,
+			FilePath:    finding.File,
+			LineNum:     finding.Line,
+			Reason:      reason,
+			AddedAt:     now,
+		}
+		if baseline.AddEntry(entry) {
+			added++
+		}
+	}
+	if added > 0 || removed > 0 {
+		if err := baseline.Save(scanner.DefaultBaselineFile); err != nil {
+			return err
+		}
+		fmt.Printf("📒 Updated %s: %d accepted, %d reopened (commit it to share the triage)\n", scanner.DefaultBaselineFile, added, removed)
+	} else {
+		fmt.Printf("📒 %s already reflects the resolutions\n", scanner.DefaultBaselineFile)
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if !cfg.Settings.Store.Enabled {
+		return nil
+	}
+	db, err := openStore(cfg, scanner.NewGitDiffer())
+	if err != nil {
+		return err
+	}
+	unknown := 0
+	for _, finding := range resolved {
+		if !db.SetResolution(finding.Fingerprint, finding.Resolution.Status, finding.Resolution.Note, now) {
+			unknown++
+		}
+	}
+	if err := db.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("🗄️  Recorded %d resolution(s) in the findings database", len(resolved)-unknown)
+	if unknown > 0 {
+		fmt.Printf(" (%d finding(s) it hasn't seen)", unknown)
+	}
+	fmt.Println()
+	return nil
+}
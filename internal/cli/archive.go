@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"secretlint/internal/archive"
+	"secretlint/internal/config"
+	"secretlint/internal/scanner"
+)
+
+// Archive scanning defaults when the config leaves the limits unset
+const (
+	defaultArchiveMaxSizeMB = 50
+	defaultArchiveMaxDepth  = 2
+)
+
+// archiveLimits converts the config block into unpacking bounds
+func archiveLimits(settings config.ArchiveSettings) archive.Limits {
+	limits := archive.Limits{
+		MaxBytes: int64(settings.MaxSizeMB) << 20,
+		MaxDepth: settings.MaxDepth,
+	}
+	if limits.MaxBytes <= 0 {
+		limits.MaxBytes = defaultArchiveMaxSizeMB << 20
+	}
+	if limits.MaxDepth <= 0 {
+		limits.MaxDepth = defaultArchiveMaxDepth
+	}
+	return limits
+}
+
+// scanArchive scans the text entries of an archive, reporting findings at
+// paths like archive.zip!path/inside. Exceeding the limits or a corrupt
+// archive is a warning; entries read up to that point are still scanned.
+func scanArchive(secretScanner *scanner.SecretScanner, settings config.ArchiveSettings, filePath string, data []byte) []scanner.Finding {
+	var findings []scanner.Finding
+	err := archive.Walk(filePath, data, archiveLimits(settings), func(path string, content []byte) {
+		text, _, ok := scanner.DecodeText(content)
+		if !ok || secretScanner.GetIgnoreChecker().ShouldIgnore(path) {
+			return
+		}
+		findings = append(findings, secretScanner.ScanStructured(scanner.ContentLines(path, text), text)...)
+	})
+	if errors.Is(err, archive.ErrLimit) {
+		fmt.Fprintf(os.Stderr, "⚠️  %s exceeds the archive scan limits; only part of it was scanned\n", filePath)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not scan archive %s: %v\n", filePath, err)
+	}
+	return findings
+}
+
+// scanStagedArchives scans staged archives, which the diff only shows as binary
+func scanStagedArchives(cfg *config.Config, secretScanner *scanner.SecretScanner, differ *scanner.GitDiffer, archives []string) []scanner.Finding {
+	var findings []scanner.Finding
+	for _, filePath := range archives {
+		data, err := differ.StagedContent(filePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			continue
+		}
+		findings = append(findings, scanArchive(secretScanner, cfg.Settings.Archives, filePath, data)...)
+	}
+	return findings
+}
+
+// stagedArchives lists staged archives to scan, or nil when archive scanning is off
+func stagedArchives(cfg *config.Config, secretScanner *scanner.SecretScanner, files []string) []string {
+	if !cfg.Settings.Archives.Enabled {
+		return nil
+	}
+	var archives []string
+	for _, filePath := range files {
+		if archive.IsArchive(filePath) && !secretScanner.GetIgnoreChecker().ShouldIgnore(filePath) {
+			archives = append(archives, filePath)
+		}
+	}
+	return archives
+}
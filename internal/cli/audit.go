@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// auditModeFlags select scans other than a history scan, so audit refuses
+// them rather than quietly scanning something else
+var auditModeFlags = []string{"all", "staged", "pre-push", "image", "hook", "interactive", "partial"}
+
+// runAudit scans the commit history for secrets committed before secretlint
+// was installed. It is 'scan --history' under its own name: args are the
+// scan flags that apply to history scans, then optional revisions such as
+// main..HEAD.
+func runAudit(args []string) error {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name := strings.TrimLeft(arg, "-")
+		if eq := strings.Index(name, "="); eq >= 0 {
+			name = name[:eq]
+		}
+		for _, mode := range auditModeFlags {
+			if strings.HasPrefix(arg, "-") && name == mode {
+				return fmt.Errorf("audit scans history; use 'secretlint scan --%s' for that scan", mode)
+			}
+		}
+	}
+	return runScan(append([]string{"--history"}, args...))
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"secretlint/internal/auditlog"
+	"secretlint/internal/config"
+	"secretlint/internal/scanner"
+)
+
+// auditHook appends a hook decision to the audit log when it is enabled.
+// Like the findings database, logging failures never change the decision.
+func auditHook(cfg *config.Config, differ *scanner.GitDiffer, entry auditlog.Entry, findings []scanner.Finding) {
+	if !cfg.Settings.Audit.Enabled {
+		return
+	}
+
+	gitDir, err := differ.GitDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not write audit log: %v\n", err)
+		return
+	}
+
+	entry.Time = time.Now().UTC()
+	entry.User = differ.ConfigValue("user.email")
+	entry.Host, _ = os.Hostname()
+	if root, err := differ.RepoRoot(); err == nil {
+		entry.Repo = filepath.Base(root)
+	}
+	entry.Findings = len(findings)
+	for _, finding := range findings {
+		entry.Fingerprints = appendUnique(entry.Fingerprints, finding.Fingerprint())
+	}
+
+	if err := auditlog.Append(auditlog.Path(gitDir), entry); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not write audit log: %v\n", err)
+	}
+}
+
+// hookDecision maps the outcome of a hook scan to an audit decision
+func hookDecision(findings []scanner.Finding, err error) string {
+	switch {
+	case err == nil && len(scanner.BlockingFindings(findings)) == 0:
+		return auditlog.DecisionPassed
+	case err == nil:
+		return auditlog.DecisionPartial
+	case len(findings) > 0:
+		return auditlog.DecisionBlocked
+	default:
+		return auditlog.DecisionError
+	}
+}
+
+func runAuditLog(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: secretlint audit-log <subcommand>\n\nSubcommands:\n  show  List recorded hook decisions\n  ship  Send new entries to the configured endpoint")
+	}
+
+	differ := scanner.NewGitDiffer()
+	gitDir, err := differ.GitDir()
+	if err != nil {
+		return err
+	}
+	logPath := auditlog.Path(gitDir)
+
+	switch args[0] {
+	case "show":
+		return showAuditLog(logPath, args[1:])
+	case "ship":
+		return shipAuditLog(logPath, args[1:])
+	default:
+		return fmt.Errorf("unknown audit-log subcommand: %s", args[0])
+	}
+}
+
+func showAuditLog(logPath string, args []string) error {
+	flags := flag.NewFlagSet("audit-log show", flag.ContinueOnError)
+	limit := flags.Int("n", 20, "Number of most recent entries to show (0 for all)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	entries, err := auditlog.Read(logPath)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("📭 No hook decisions recorded yet.")
+		fmt.Println("Enable 'settings.audit.enabled' in .secretlintrc.yml to keep an audit log.")
+		return nil
+	}
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[len(entries)-*limit:]
+	}
+
+	for _, entry := range entries {
+		icon := "✅"
+		if entry.Decision != auditlog.DecisionPassed {
+			icon = "⛔"
+		}
+		fmt.Printf("%s %s %-10s %-8s %s on %s (%d finding(s))\n", icon, entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Hook, entry.Decision, entry.User, entry.Branch, entry.Findings)
+		if entry.Bypassed {
+			fmt.Printf("   ⚠️  Pre-commit hook bypassed for: %s\n", joinShort(entry.BypassCommits))
+		}
+	}
+	return nil
+}
+
+func shipAuditLog(logPath string, args []string) error {
+	flags := flag.NewFlagSet("audit-log ship", flag.ContinueOnError)
+	endpoint := flags.String("endpoint", "", "URL to POST entries to (defaults to settings.audit.endpoint)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if err := requireOnline(cfg, "audit-log ship"); err != nil {
+		return err
+	}
+	if *endpoint == "" {
+		*endpoint = cfg.Settings.Audit.Endpoint
+	}
+	if *endpoint == "" {
+		return fmt.Errorf("no audit endpoint configured (set settings.audit.endpoint or pass --endpoint)")
+	}
+
+	shipped, err := auditlog.Ship(logPath, *endpoint, os.Getenv(auditlog.TokenEnv))
+	if err != nil {
+		return err
+	}
+	if shipped == 0 {
+		fmt.Println("✅ Audit log is up to date - nothing to ship")
+		return nil
+	}
+	fmt.Printf("📤 Shipped %d audit log entries to %s\n", shipped, *endpoint)
+	return nil
+}
+
+// joinShort abbreviates commit SHAs for display
+func joinShort(shas []string) string {
+	result := ""
+	for i, sha := range shas {
+		if i > 0 {
+			result += ", "
+		}
+		if len(sha) > 12 {
+			sha = sha[:12]
+		}
+		result += sha
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"secretlint/internal/scanner"
+)
+
+// sourceContext prints the lines around each finding for 'scan --context N'
+type sourceContext struct {
+	lines  int
+	differ *scanner.GitDiffer
+	
+	// staged reads files from the index; otherwise the working tree under root is used
+	staged bool
+	root   string
+	
+	files map[string][]string
+	
+	// secrets holds every match being reported, so none shows up unmasked
+	secrets []string
+}
+
+func newSourceContext(lines int, staged bool) *sourceContext {
+	return &sourceContext{
+		lines:  lines,
+		differ: scanner.NewGitDiffer(),
+		staged: staged,
+		files:  make(map[string][]string),
+	}
+}
+
+// fileLines returns the content the finding was found in, split into lines.
+// Findings in commits are read at that commit.
+func (c *sourceContext) fileLines(finding scanner.Finding) ([]string, bool) {
+	key := finding.FilePath
+	if finding.Commit != nil {
+		key = finding.Commit.SHA + ":" + key
+	}
+	if lines, ok := c.files[key]; ok {
+		return lines, lines != nil
+	}
+	
+	var data []byte
+	var err error
+	switch {
+	case finding.Commit != nil:
+		data, err = c.differ.ContentAt(finding.Commit.SHA, finding.FilePath)
+	case c.staged:
+		data, err = c.differ.StagedContent(finding.FilePath)
+	default:
+		// Tracked paths are relative to the repository root
+		if c.root == "" {
+			c.root, err = c.differ.RepoRoot()
+		}
+		if err == nil {
+			data, err = os.ReadFile(filepath.Join(c.root, finding.FilePath))
+		}
+	}
+	// Entries inside archives and binary files have no lines to show
+	var lines []string
+	if err == nil {
+		if text, _, ok := scanner.DecodeText(data); ok {
+			lines = strings.Split(strings.TrimSuffix(string(text), "\n"), "\n")
+		}
+	}
+	c.files[key] = lines
+	return lines, lines != nil
+}
+
+// related remembers the matches of the findings about to be printed, so
+// each is masked wherever it appears in context lines
+func (c *sourceContext) related(findings []scanner.Finding) {
+	if c == nil {
+		return
+	}
+	for _, finding := range findings {
+		if finding.Match != "" && finding.RuleID != scanner.SensitiveFileRule {
+			c.secrets = append(c.secrets, finding.Match)
+		}
+	}
+}
+
+// print renders the lines around a finding with every known secret masked,
+// so the context never shows more than the snippet does
+func (c *sourceContext) print(finding scanner.Finding) {
+	if c == nil || finding.LineNum == 0 || finding.RuleID == scanner.SensitiveFileRule {
+		return
+	}
+	lines, ok := c.fileLines(finding)
+	if !ok || finding.LineNum > len(lines) {
+		return
+	}
+	
+	first := finding.LineNum - c.lines
+	if first < 1 {
+		first = 1
+	}
+	last := finding.LineNum + c.lines
+	if last > len(lines) {
+		last = len(lines)
+	}
+	fmt.Println("Context  :")
+	for lineNum := first; lineNum <= last; lineNum++ {
+		line := strings.TrimSuffix(lines[lineNum-1], "\r")
+		for _, secret := range c.secrets {
+			line = strings.ReplaceAll(line, secret, finding.DisplayValue(secret))
+		}
+		marker := " "
+		if lineNum == finding.LineNum {
+			marker = ">"
+		}
+		fmt.Printf("  %s %4d | %s\n", marker, lineNum, line)
+	}
+}
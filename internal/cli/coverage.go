@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/scanner"
+)
+
+// Directories of a labeled corpus: secrets/<RULE_ID>/ holds samples the
+// rule must detect, benign/ lookalikes no rule should flag
+const (
+	corpusSecretsDir = "secrets"
+	corpusBenignDir  = "benign"
+)
+
+// ruleCoverage is one rule's detection quality over a corpus
+type ruleCoverage struct {
+	RuleID  string `json:"rule_id"`
+	Enabled bool   `json:"enabled"`
+
+	// Samples are the files under secrets/<RULE_ID>/, Detected those the
+	// rule flagged, and FalsePositives the benign files it flagged
+	Samples        int `json:"samples"`
+	Detected       int `json:"detected"`
+	FalsePositives int `json:"false_positives"`
+
+	// Precision and Recall are null when there is nothing to divide by:
+	// the rule never fired, or has no samples
+	Precision *float64 `json:"precision"`
+	Recall    *float64 `json:"recall"`
+
+	Missed             []string `json:"missed,omitempty"`
+	FalsePositiveFiles []string `json:"false_positive_files,omitempty"`
+}
+
+// runRulesCoverage runs the active rules over a labeled corpus and reports
+// each rule's precision and recall, so custom packs can be tuned and
+// detection quality compared between releases
+func runRulesCoverage(args []string) error {
+	flags := flag.NewFlagSet("rules coverage", flag.ContinueOnError)
+	corpus := flags.String("corpus", "", "Labeled corpus: secrets/<RULE_ID>/ samples and benign/ lookalikes")
+	format := flags.String("format", "text", "Output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: text, json)", *format)
+	}
+	if *corpus == "" || flags.NArg() > 0 {
+		return fmt.Errorf("usage: secretlint rules coverage --corpus <dir> [--format text|json]")
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	files, err := scanner.WalkFiles(*corpus)
+	if err != nil {
+		return err
+	}
+
+	coverage := make(map[string]*ruleCoverage)
+	for _, rule := range listRules(cfg) {
+		coverage[rule.ID] = &ruleCoverage{RuleID: rule.ID, Enabled: rule.Enabled}
+	}
+	unknown := make(map[string]bool)
+	rule := func(id string) *ruleCoverage {
+		if coverage[id] == nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s/%s is not a rule secretlint knows\n", corpusSecretsDir, id)
+			coverage[id] = &ruleCoverage{RuleID: id}
+			unknown[id] = true
+		}
+		return coverage[id]
+	}
+
+	secretScanner, err := scanner.NewRuleScanner()
+	if err != nil {
+		return err
+	}
+	samples, benign := 0, 0
+	for _, filePath := range files {
+		parts := strings.SplitN(filePath, "/", 3)
+		switch {
+		case parts[0] == corpusSecretsDir && len(parts) == 3:
+			samples++
+		case parts[0] == corpusBenignDir && len(parts) > 1:
+			benign++
+		default:
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping %s: corpus files go under %s/<RULE_ID>/ or %s/\n", filePath, corpusSecretsDir, corpusBenignDir)
+			continue
+		}
+
+		file, err := os.Open(filepath.Join(*corpus, filepath.FromSlash(filePath)))
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", filePath, err)
+		}
+		findings, err := secretScanner.ScanSource(scanner.Files(scanner.File{Path: filePath, Reader: file}))
+		if err != nil {
+			return err
+		}
+
+		if parts[0] == corpusSecretsDir {
+			expected := rule(parts[1])
+			expected.Samples++
+			if hasRule(findings, expected.RuleID) {
+				expected.Detected++
+			} else {
+				expected.Missed = append(expected.Missed, filePath)
+			}
+			continue
+		}
+		// A rule flagging a benign file several times is one false positive
+		flagged := make(map[string]bool)
+		for _, finding := range findings {
+			if !flagged[finding.RuleID] {
+				flagged[finding.RuleID] = true
+				fp := rule(finding.RuleID)
+				fp.FalsePositives++
+				fp.FalsePositiveFiles = append(fp.FalsePositiveFiles, fmt.Sprintf("%s:%d", filePath, finding.LineNum))
+			}
+		}
+	}
+	if samples == 0 && benign == 0 {
+		return fmt.Errorf("no corpus files in %s (expected %s/<RULE_ID>/ and %s/)", *corpus, corpusSecretsDir, corpusBenignDir)
+	}
+
+	// Rules without samples or false positives say nothing about quality
+	var rules []*ruleCoverage
+	total := &ruleCoverage{RuleID: "TOTAL", Enabled: true}
+	for _, c := range coverage {
+		if c.Samples == 0 && c.FalsePositives == 0 {
+			continue
+		}
+		c.Precision, c.Recall = ratio(c.Detected, c.Detected+c.FalsePositives), ratio(c.Detected, c.Samples)
+		rules = append(rules, c)
+		total.Samples += c.Samples
+		total.Detected += c.Detected
+		total.FalsePositives += c.FalsePositives
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].RuleID < rules[j].RuleID })
+	total.Precision, total.Recall = ratio(total.Detected, total.Detected+total.FalsePositives), ratio(total.Detected, total.Samples)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		report := struct {
+			Tool        string          `json:"tool"`
+			Version     string          `json:"version"`
+			GeneratedAt time.Time       `json:"generated_at"`
+			Corpus      string          `json:"corpus"`
+			Samples     int             `json:"samples"`
+			BenignFiles int             `json:"benign_files"`
+			Rules       []*ruleCoverage `json:"rules"`
+			Total       *ruleCoverage   `json:"total"`
+		}{"secretlint", Version, time.Now().UTC(), *corpus, samples, benign, rules, total}
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to write coverage: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Printf("📊 Rule coverage over %s (%d secret sample(s), %d benign file(s))\n\n", *corpus, samples, benign)
+	fmt.Printf("  %-22s %7s %8s %6s %9s %9s %7s\n", "RULE", "SAMPLES", "DETECTED", "MISSED", "FALSE POS", "PRECISION", "RECALL")
+	for _, c := range append(rules, total) {
+		id := c.RuleID
+		if unknown[id] {
+			id += " (unknown)"
+		} else if !c.Enabled {
+			id += " (off)"
+		}
+		fmt.Printf("  %-22s %7d %8d %6d %9d %9s %7s\n", id, c.Samples, c.Detected, c.Samples-c.Detected, c.FalsePositives, percent(c.Precision), percent(c.Recall))
+	}
+	var details []string
+	for _, c := range rules {
+		for _, filePath := range c.Missed {
+			details = append(details, fmt.Sprintf("❌ %s missed %s", c.RuleID, filePath))
+		}
+		for _, location := range c.FalsePositiveFiles {
+			details = append(details, fmt.Sprintf("⚠️  %s flagged %s", c.RuleID, location))
+		}
+	}
+	if len(details) > 0 {
+		fmt.Printf("\n%s\n", strings.Join(details, "\n"))
+	}
+	return nil
+}
+
+// hasRule reports whether rule is among the findings
+func hasRule(findings []scanner.Finding, rule string) bool {
+	for _, finding := range findings {
+		if finding.RuleID == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// ratio returns n/d, or nil when d is zero
+func ratio(n, d int) *float64 {
+	if d == 0 {
+		return nil
+	}
+	r := float64(n) / float64(d)
+	return &r
+}
+
+// percent formats a ratio for the coverage table
+func percent(r *float64) string {
+	if r == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", *r*100)
+}
@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+)
+
+// A time-budgeted hook hands the staged archives it had no time for to a
+// background scan. Each hook run's archives are one job in
+// .git/secretlint/deferred: <id>.queued.json until a background scan takes
+// it, <id>.running.json while it scans, and <id>.report.json, a JSON report,
+// until the next hook run prints it.
+const (
+	deferredQueued  = ".queued.json"
+	deferredRunning = ".running.json"
+	deferredReport  = ".report.json"
+)
+
+// deferredStaleAfter is how long a job may be running before the scan is
+// taken to have died and the job is picked up again
+const deferredStaleAfter = time.Hour
+
+// deferredJob is the archives one hook run queued. Archives are read back
+// by blob, since the index has moved on by the time they are scanned.
+type deferredJob struct {
+	QueuedAt time.Time         `json:"queued_at"`
+	Archives []deferredArchive `json:"archives"`
+}
+
+type deferredArchive struct {
+	Path string `json:"path"`
+	Blob string `json:"blob"`
+}
+
+// hookTimeBudget returns settings.hook.time_budget, or 0 when it is unset
+func hookTimeBudget(cfg *config.Config) (time.Duration, error) {
+	if cfg.Settings.Hook.TimeBudget == "" {
+		return 0, nil
+	}
+	budget, err := time.ParseDuration(cfg.Settings.Hook.TimeBudget)
+	if err != nil || budget <= 0 {
+		return 0, fmt.Errorf("invalid settings.hook.time_budget %q (use a duration such as 2s)", cfg.Settings.Hook.TimeBudget)
+	}
+	return budget, nil
+}
+
+// scanStagedArchivesWithin scans staged archives until deadline and returns
+// their findings and the archives left over, including the one being
+// scanned when time ran out
+func scanStagedArchivesWithin(cfg *config.Config, secretScanner *scanner.SecretScanner, differ *scanner.GitDiffer, archives []string, deadline time.Time) ([]scanner.Finding, []string) {
+	results := make(chan []scanner.Finding, len(archives))
+	go func() {
+		for _, filePath := range archives {
+			results <- scanStagedArchives(cfg, secretScanner, differ, []string{filePath})
+		}
+	}()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	var findings []scanner.Finding
+	for i := range archives {
+		select {
+		case archiveFindings := <-results:
+			findings = append(findings, archiveFindings...)
+		case <-timer.C:
+			return findings, archives[i:]
+		}
+	}
+	return findings, nil
+}
+
+// queueDeferredScan records archives as a job and starts a background scan.
+// Failing to queue them is a warning: the hook can't wait for them either.
+func queueDeferredScan(status io.Writer, differ *scanner.GitDiffer, archives []string) {
+	job := deferredJob{QueuedAt: time.Now().UTC()}
+	for _, filePath := range archives {
+		blob, err := differ.StagedBlob(filePath)
+		if err != nil {
+			fmt.Fprintf(status, "⚠️  %v\n", err)
+			continue
+		}
+		job.Archives = append(job.Archives, deferredArchive{Path: filePath, Blob: blob})
+	}
+	if len(job.Archives) == 0 {
+		return
+	}
+
+	err := func() error {
+		dir, err := deferredDir(differ)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		data, err := json.MarshalIndent(job, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode deferred scan: %w", err)
+		}
+		id := fmt.Sprintf("%s-%d", job.QueuedAt.Format("20060102T150405.000000000"), os.Getpid())
+		if err := os.WriteFile(filepath.Join(dir, id+deferredQueued), data, 0600); err != nil {
+			return fmt.Errorf("failed to queue deferred scan: %w", err)
+		}
+		return startDeferredScan()
+	}()
+	if err != nil {
+		fmt.Fprintf(status, "⚠️  %d staged archive(s) were not scanned: %v\n", len(job.Archives), err)
+		return
+	}
+	fmt.Fprintf(status, "ℹ️  Time budget (settings.hook.time_budget) spent; %d staged archive(s) are scanned in the background and reported on the next commit\n", len(job.Archives))
+}
+
+// startDeferredScan starts 'secretlint hook deferred' without waiting for
+// it. Its output goes nowhere, so git doesn't wait on it either.
+func startDeferredScan() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	cmd := exec.Command(executable, "hook", "deferred")
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start the background scan: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// reportDeferredScans prints what background scans of earlier commits
+// found and removes their reports. The secrets are already committed, so
+// they don't block. Jobs no scan took, or whose scan died, are started again.
+func reportDeferredScans(status io.Writer, differ *scanner.GitDiffer) {
+	dir, err := deferredDir(differ)
+	if err != nil {
+		return
+	}
+	reports, _ := filepath.Glob(filepath.Join(dir, "*"+deferredReport))
+	sort.Strings(reports)
+	for _, reportPath := range reports {
+		r, err := report.Load(reportPath)
+		if err != nil {
+			fmt.Fprintf(status, "⚠️  %v\n", err)
+			continue
+		}
+		if len(r.Findings) > 0 {
+			fmt.Fprintf(status, "⚠️  The background scan of archives staged for an earlier commit found %d secret(s); if it was committed, they are in history:\n", len(r.Findings))
+			for _, finding := range r.Findings {
+				fmt.Fprintf(status, "   %s  %s:%d  %s\n", finding.RuleID, finding.File, finding.Line, finding.Snippet)
+			}
+			fmt.Fprintln(status, "   Remove them and rotate the secrets; 'secretlint purge' helps if the commit was pushed")
+		}
+		os.Remove(reportPath)
+	}
+
+	pending, scannable := deferredJobs(dir)
+	if len(scannable) > 0 {
+		if err := startDeferredScan(); err != nil {
+			fmt.Fprintf(status, "⚠️  %v\n", err)
+		}
+	}
+	if len(pending) > 0 {
+		fmt.Fprintf(status, "ℹ️  %d background archive scan(s) of earlier commits still running\n", len(pending))
+	}
+}
+
+// runHookDeferred scans the archives time-budgeted hooks queued, writes a
+// report per job and raises a desktop notification when secrets are found
+func runHookDeferred() error {
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		return fmt.Errorf("not in a git repository")
+	}
+	dir, err := deferredDir(differ)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if cfg, err = selectHookProfile(cfg); err != nil {
+		return err
+	}
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+
+	_, jobs := deferredJobs(dir)
+	for _, jobPath := range jobs {
+		id := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(jobPath), deferredQueued), deferredRunning)
+		running := filepath.Join(dir, id+deferredRunning)
+		// Renaming claims the job, so concurrent scans don't both take it
+		if err := os.Rename(jobPath, running); err != nil {
+			continue
+		}
+		now := time.Now()
+		os.Chtimes(running, now, now)
+
+		data, err := os.ReadFile(running)
+		if err != nil {
+			return fmt.Errorf("failed to read deferred scan: %w", err)
+		}
+		var job deferredJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			return fmt.Errorf("failed to parse deferred scan %s: %w", running, err)
+		}
+		var findings []scanner.Finding
+		for _, a := range job.Archives {
+			content, err := differ.BlobContent(a.Blob)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+				continue
+			}
+			findings = append(findings, scanArchive(secretScanner, cfg.Settings.Archives, a.Path, content)...)
+		}
+
+		file, err := os.Create(filepath.Join(dir, id+deferredReport))
+		if err != nil {
+			return fmt.Errorf("failed to write deferred scan report: %w", err)
+		}
+		if err := report.New("deferred", findings).Write(file); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write deferred scan report: %w", err)
+		}
+		os.Remove(running)
+
+		if len(findings) > 0 {
+			notify("secretlint", fmt.Sprintf("%d secret(s) found in archives staged on %s; details on the next commit", len(findings), job.QueuedAt.Local().Format("Jan 2 15:04")))
+		}
+	}
+	return nil
+}
+
+// deferredJobs lists the jobs not yet reported: those queued or running,
+// and those to scan, which are the queued ones and any running too long
+func deferredJobs(dir string) (pending, scannable []string) {
+	queued, _ := filepath.Glob(filepath.Join(dir, "*"+deferredQueued))
+	running, _ := filepath.Glob(filepath.Join(dir, "*"+deferredRunning))
+	// Copied, so appending to one list doesn't write into the other
+	scannable = append([]string(nil), queued...)
+	for _, jobPath := range running {
+		if info, err := os.Stat(jobPath); err == nil && time.Since(info.ModTime()) > deferredStaleAfter {
+			scannable = append(scannable, jobPath)
+		}
+	}
+	pending = append(append([]string(nil), queued...), running...)
+	sort.Strings(pending)
+	sort.Strings(scannable)
+	return pending, scannable
+}
+
+// deferredDir returns the directory of the deferred scan jobs
+func deferredDir(differ *scanner.GitDiffer) (string, error) {
+	gitDir, err := differ.GitDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "secretlint", "deferred"), nil
+}
+
+// notify shows a desktop notification where the platform has a CLI for it;
+// results of background scans would otherwise wait for the next commit
+func notify(title, message string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", message, title))
+	case "windows":
+		return
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return
+		}
+		cmd = exec.Command("notify-send", title, message)
+	}
+	cmd.Run()
+}
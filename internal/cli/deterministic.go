@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"secretlint/internal/report"
+)
+
+// makeDeterministic removes what varies between two runs over the same tree
+// (scan --deterministic): reports are stamped with SOURCE_DATE_EPOCH, as in
+// reproducible builds, or the Unix epoch, and no progress line is drawn.
+// Findings are always sorted, so their order needs nothing here.
+func makeDeterministic() error {
+	stamp := time.Unix(0, 0)
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+		}
+		stamp = time.Unix(seconds, 0)
+	}
+	report.Pin(stamp)
+	progressOff = true
+	return nil
+}
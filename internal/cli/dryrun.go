@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"secretlint/internal/archive"
+	"secretlint/internal/config"
+	"secretlint/internal/scanner"
+)
+
+// printDryRun shows what a scan would cover - config sources, files and
+// lines, ignored files and active rules - without scanning anything. args
+// are the revisions of a history scan or the paths of a full scan.
+func printDryRun(cfg *config.Config, mode string, args []string) error {
+	differ := scanner.NewGitDiffer()
+	// Without git, or in a Mercurial or Jujutsu repository, an --all scan
+	// walks the files instead
+	if (mode != "all" || (scanner.CheckGit() == nil && otherVCS() == nil)) && !differ.IsInGitRepo() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	// Count lines per file the same way the scan would collect them
+	var files []string
+	lineCounts := make(map[string]int)
+	scope := ""
+	switch mode {
+	case "staged":
+		scope = "staged changes"
+		lines, err := differ.GetStagedChanges()
+		if err != nil {
+			return fmt.Errorf("failed to get staged changes: %w", err)
+		}
+		for _, line := range lines {
+			lineCounts[line.FilePath]++
+		}
+		if files, err = differ.StagedFiles(); err != nil {
+			return err
+		}
+	case "all":
+		scope = "every tracked file"
+		if len(args) > 0 {
+			scope = "the tracked files under " + strings.Join(args, ", ")
+		}
+		root, tracked, skipped, err := trackedFiles(differ, args)
+		if err != nil {
+			return err
+		}
+		files = tracked
+		if skipped > 0 {
+			scope += fmt.Sprintf(" (%d outside the sparse checkout or skip-worktree, not scanned)", skipped)
+		}
+		// Tracked paths are relative to the root, where the scan runs too
+		return inDir(root, func() error {
+			return printDryRunScope(cfg, scope, files, nil)
+		})
+	case "history":
+		scope = "history"
+		if len(args) > 0 {
+			scope += " of " + strings.Join(args, " ")
+		}
+		lines, err := differ.GetHistoryChanges(args...)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			if _, ok := lineCounts[line.FilePath]; !ok {
+				files = append(files, line.FilePath)
+			}
+			lineCounts[line.FilePath]++
+		}
+	default:
+		return fmt.Errorf("--dry-run supports staged, --all and --history scans")
+	}
+	return printDryRunScope(cfg, scope, files, lineCounts)
+}
+
+// printDryRunScope prints the report; lineCounts is nil when whole files are scanned
+func printDryRunScope(cfg *config.Config, scope string, files []string, lineCounts map[string]int) error {
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+	ignoreChecker := secretScanner.GetIgnoreChecker()
+
+	fmt.Println("🧪 Dry run - nothing is scanned")
+	fmt.Println()
+	printOrigins(cfg)
+
+	fmt.Printf("\n📐 Scope: %s\n", scope)
+	ignored := make(map[string]string)
+	scanned, lines := 0, 0
+	for _, filePath := range files {
+		if pattern := ignoreChecker.MatchingPattern(filePath); pattern != "" {
+			ignored[filePath] = pattern
+			continue
+		}
+		note := ""
+		switch {
+		case cfg.Settings.Archives.Enabled && archive.IsArchive(filePath):
+			note = " (archive, entries scanned)"
+		case lineCounts == nil:
+		case lineCounts[filePath] == 0:
+			note = " (no added lines; only the file name is checked)"
+		default:
+			note = fmt.Sprintf(" (%d line(s))", lineCounts[filePath])
+		}
+		fmt.Printf("  %s%s\n", filePath, note)
+		scanned++
+		lines += lineCounts[filePath]
+	}
+	if lineCounts == nil {
+		fmt.Printf("  %d file(s) would be scanned\n", scanned)
+	} else {
+		fmt.Printf("  %d file(s), %d line(s) would be scanned\n", scanned, lines)
+	}
+
+	if len(ignored) > 0 {
+		fmt.Printf("\n🚫 Ignored (%d file(s)):\n", len(ignored))
+		var paths []string
+		for filePath := range ignored {
+			paths = append(paths, filePath)
+		}
+		sort.Strings(paths)
+		for _, filePath := range paths {
+			fmt.Printf("  %s (matches %s)\n", filePath, ignored[filePath])
+		}
+	}
+	if patterns := ignoreChecker.GetPatterns(); len(patterns) > 0 {
+		fmt.Printf("\n🙈 .secretignore patterns: %s\n", strings.Join(patterns, ", "))
+	}
+
+	rules := secretScanner.GetRules()
+	fmt.Printf("\n🔎 Active rules (%d):\n", len(rules))
+	for _, rule := range rules {
+		marker := ""
+		if secretScanner.IsMandatory(rule.ID) {
+			marker = ", mandatory"
+		}
+		fmt.Printf("  %-22s %s [%s%s]\n", rule.ID, rule.Name, rule.Severity, marker)
+	}
+	if cfg.RuleEnabled(scanner.SensitiveKeyRule) {
+		fmt.Printf("  %-22s Credential-looking keys in YAML/JSON/TOML/INI/.env files\n", scanner.SensitiveKeyRule)
+	}
+	if cfg.Settings.References.Enabled && cfg.RuleEnabled(scanner.LiteralSecretRule) {
+		fmt.Printf("  %-22s Credential-looking names set to literals in source files [%s]\n", scanner.LiteralSecretRule, scanner.SeverityWarning)
+	}
+	if severity := cfg.Settings.Filenames.Severity; cfg.RuleEnabled(scanner.SensitiveFileRule) && severity != "off" {
+		if severity == "" {
+			severity = scanner.SeverityError
+		}
+		fmt.Printf("  %-22s Credential files by name [%s]\n", scanner.SensitiveFileRule, severity)
+	}
+	var disabled []string
+	for ruleID, enabled := range cfg.Rules {
+		if !enabled {
+			disabled = append(disabled, ruleID)
+		}
+	}
+	if len(disabled) > 0 {
+		sort.Strings(disabled)
+		fmt.Printf("  Disabled: %s\n", strings.Join(disabled, ", "))
+	}
+	if len(cfg.Packs) > 0 {
+		fmt.Printf("  Packs: %s\n", strings.Join(cfg.Packs, ", "))
+	}
+	if baseline := secretScanner.GetBaseline(); baseline != nil && len(baseline.Entries) > 0 {
+		fmt.Printf("\n📋 %d baselined finding(s) are skipped (%s)\n", len(baseline.Entries), scanner.DefaultBaselineFile)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+
+	"secretlint/internal/fix"
+	"secretlint/internal/scanner"
+)
+
+// envExampleFile documents the variables an application expects, without values
+const envExampleFile = ".env.example"
+
+func runEnvify(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: secretlint envify <file>...")
+	}
+
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+	var findings []scanner.Finding
+
+	for _, filePath := range args {
+		lines, err := scanner.ReadFileLines(filePath)
+		if err != nil {
+			return err
+		}
+
+		// Rule hits take precedence; the key-name heuristic catches the rest
+		seen := make(map[string]bool)
+		for _, line := range lines {
+			for _, finding := range secretScanner.ScanLine(line.FilePath, line.LineNum, line.Content) {
+				seen[finding.Secret] = true
+				findings = append(findings, finding)
+			}
+		}
+		for _, finding := range fix.FindHardcodedCredentials(lines) {
+			if !seen[finding.Secret] {
+				seen[finding.Secret] = true
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	if len(findings) == 0 {
+		fmt.Println("✅ No hardcoded credentials found")
+		return nil
+	}
+
+	result, err := fix.NewFixer().Apply(findings)
+	if err != nil {
+		return fmt.Errorf("failed to envify: %w", err)
+	}
+
+	var names []string
+	for _, change := range result.Changes {
+		fmt.Printf("✅ %s:%d now reads %s\n", change.FilePath, change.LineNum, change.Reference)
+		names = append(names, change.EnvVar)
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Printf("⚠️  %s:%d - %s not rewritten: %s\n", skipped.FilePath, skipped.LineNum, skipped.RuleID, skipped.Reason)
+	}
+	for _, filePath := range result.UnstageFiles {
+		fmt.Printf("⚠️  %s is already an environment file - nothing to rewrite\n", filePath)
+	}
+
+	added, err := fix.AppendExample(envExampleFile, names)
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		fmt.Printf("📄 Added %d variable(s) to %s\n", added, envExampleFile)
+	}
+	if len(result.Changes) > 0 {
+		fmt.Printf("🔑 Current values were written to %s\n", result.EnvFile)
+	}
+	if result.GitignoreUpdated {
+		fmt.Printf("✅ Added %s to %s\n", result.EnvFile, result.GitignoreFile)
+	}
+
+	return nil
+}
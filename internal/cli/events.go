@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/events"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+	"secretlint/internal/telemetry"
+)
+
+// sendScanEvent posts a scan-completed event when an events URL is configured.
+// Delivery is best effort and never changes the scan result.
+func sendScanEvent(cfg *config.Config, mode string, hook bool, duration time.Duration, stats *scanStats, scanErr error) {
+	if cfg.Settings.Events.URL == "" {
+		return
+	}
+
+	differ := scanner.NewGitDiffer()
+	event := events.Event{
+		Event:       events.ScanCompleted,
+		Time:        time.Now().UTC(),
+		Tool:        "secretlint",
+		ToolVersion: Version,
+		Repo:        repoIdentity(differ),
+		Ref:         differ.CurrentBranch(),
+		Head:        differ.HeadSHA(),
+		Operation:   differ.Operation(),
+		Mode:        mode,
+		Hook:        hook,
+		DurationMS:  int64(duration / time.Millisecond),
+		Files:       stats.files,
+		Findings:    stats.findings,
+		Blocked:     scanErr != nil && stats.findings > 0,
+		Error:       scanErr != nil && stats.findings == 0,
+	}
+
+	if err := events.Send(cfg.Settings.Events.URL, os.Getenv(events.TokenEnv), event); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not send scan event: %v\n", err)
+	}
+}
+
+// sendTelemetry posts anonymous usage counts when telemetry is enabled and
+// compiled in. Like scan events it is best effort.
+func sendTelemetry(cfg *config.Config, mode string, hook bool, duration time.Duration, stats *scanStats) {
+	if !cfg.Settings.Telemetry.Enabled {
+		return
+	}
+
+	usage := telemetry.Report{
+		Tool:       "secretlint",
+		Version:    Version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Mode:       mode,
+		Hook:       hook,
+		DurationMS: int64(duration / time.Millisecond),
+		Files:      stats.files,
+		Findings:   stats.findings,
+		Rules:      stats.rules,
+	}
+	if err := telemetry.Send(cfg.Settings.Telemetry.Endpoint, usage); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not send telemetry: %v\n", err)
+	}
+}
+
+// repoIdentity names the repository by its origin URL (without credentials),
+// falling back to the directory name for repositories without a remote
+func repoIdentity(differ *scanner.GitDiffer) string {
+	if origin := originURL(differ); origin != "" {
+		return origin
+	}
+
+	root, err := differ.RepoRoot()
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(root)
+}
+
+// originURL returns the origin remote's URL without credentials, or ""
+func originURL(differ *scanner.GitDiffer) string {
+	origin := differ.ConfigValue("remote.origin.url")
+	if origin == "" {
+		return ""
+	}
+	if parsed, err := url.Parse(origin); err == nil && parsed.Host != "" {
+		parsed.User = nil
+		return parsed.String()
+	}
+	// scp-like syntax: user@host:path
+	if i := strings.Index(origin, "@"); i >= 0 && !strings.Contains(origin[:i], "/") {
+		return origin[i+1:]
+	}
+	return origin
+}
+
+// annotateReport records the repository, branch and head a report's scan
+// ran on, and for staged scans the commit being made
+func annotateReport(r *report.Report) *report.Report {
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		return r
+	}
+	r.Repository = &report.Repository{
+		Remote: originURL(differ),
+		Branch: differ.CurrentBranch(),
+		Head:   differ.HeadSHA(),
+	}
+	if r.Scope == "staged" {
+		name, email := differ.AuthorIdent()
+		r.Repository.CommitInProgress = &report.CommitInProgress{
+			Author:    name,
+			Email:     email,
+			Operation: differ.Operation(),
+		}
+	}
+	return r
+}
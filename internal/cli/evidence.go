@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"secretlint/internal/auditlog"
+	"secretlint/internal/config"
+	"secretlint/internal/evidence"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+)
+
+// evidenceKeyEnv points at the Ed25519 key used to sign evidence bundles
+const evidenceKeyEnv = "SECRETLINT_EVIDENCE_KEY"
+
+// evidenceRule is a rule as recorded in an evidence bundle. The version is a
+// digest of the pattern, so auditors can tell when detection logic changed.
+type evidenceRule struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// evidenceSummary is the findings part of an evidence bundle; like reports it
+// holds fingerprints and masked snippets only
+type evidenceSummary struct {
+	Scope       string           `json:"scope"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Total       int              `json:"total"`
+	Unique      int              `json:"unique"`
+	ByRule      map[string]int   `json:"by_rule"`
+	Findings    []report.Finding `json:"findings"`
+}
+
+// runReportEvidence builds a signed archive documenting the secret-scanning
+// control: configuration, rules, installed hooks, audit log and findings
+func runReportEvidence(args []string) error {
+	flags := flag.NewFlagSet("report evidence", flag.ContinueOnError)
+	reportPath := flags.String("report", "", "Summarize this JSON report instead of scanning all tracked files")
+	output := flags.String("out", "", "Bundle path (default secretlint-evidence-<date>.tar.gz)")
+	keyPath := flags.String("key", os.Getenv(evidenceKeyEnv), "Ed25519 PEM signing key (created if missing; default .git/secretlint/evidence-signing.pem)")
+	verifyPath := flags.String("verify", "", "Verify an existing bundle instead of creating one")
+	keyID := flags.String("key-id", "", "With --verify, require the bundle to be signed by this key ID")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *verifyPath != "" {
+		return verifyEvidence(*verifyPath, *keyID)
+	}
+
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		return fmt.Errorf("not in a git repository")
+	}
+	gitDir, err := differ.GitDir()
+	if err != nil {
+		return err
+	}
+	root, err := differ.RepoRoot()
+	if err != nil {
+		return err
+	}
+
+	var r *report.Report
+	if *reportPath != "" {
+		if r, err = report.Load(*reportPath); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, "🔍 Scanning tracked files for the findings summary...")
+		cfg, err := config.Load(filepath.Join(root, config.DefaultConfigFile))
+		if err != nil {
+			return err
+		}
+		findings, _, _, err := collectTrackedFindings(cfg, differ, 0, nil)
+		if err != nil {
+			return err
+		}
+		r = report.New("all", findings)
+	}
+
+	files, err := collectEvidenceFiles(gitDir, r)
+	if err != nil {
+		return err
+	}
+
+	if *keyPath == "" {
+		*keyPath = filepath.Join(gitDir, "secretlint", "evidence-signing.pem")
+	}
+	key, created, err := evidence.LoadOrCreateKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Fprintf(os.Stderr, "🔑 Created signing key %s - keep it safe and share its key ID with auditors\n", *keyPath)
+	}
+
+	now := time.Now().UTC()
+	if *output == "" {
+		*output = fmt.Sprintf("secretlint-evidence-%s.tar.gz", now.Format("20060102-150405"))
+	}
+	manifest := evidence.Manifest{
+		Tool:        "secretlint",
+		ToolVersion: Version,
+		GeneratedAt: now,
+		Repo:        filepath.Base(root),
+		Branch:      differ.CurrentBranch(),
+		Head:        differ.HeadSHA(),
+		Scope:       r.Scope,
+	}
+
+	file, err := os.OpenFile(*output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *output, err)
+	}
+	if err := evidence.Write(file, manifest, files, key); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+
+	bundle, err := os.Open(*output)
+	if err != nil {
+		return err
+	}
+	defer bundle.Close()
+	signed, err := evidence.Verify(bundle, "")
+	if err != nil {
+		return fmt.Errorf("bundle failed self-verification: %w", err)
+	}
+
+	fmt.Printf("📦 Wrote %s (%d documents, scope %s)\n", *output, len(signed.Files), signed.Scope)
+	fmt.Printf("🔏 Signed with key %s at %s\n", signed.KeyID, signed.GeneratedAt.Format(time.RFC3339))
+	fmt.Printf("Verify with: secretlint report evidence --verify %s --key-id %s\n", *output, signed.KeyID)
+	return nil
+}
+
+// collectEvidenceFiles gathers the documents that go into a bundle
+func collectEvidenceFiles(gitDir string, r *report.Report) ([]evidence.File, error) {
+	var files []evidence.File
+
+	optional := []struct {
+		name string
+		path string
+	}{
+		{"config/" + config.DefaultConfigFile, config.DefaultConfigFile},
+		{"config/.secretignore", ".secretignore"},
+		{"config/" + scanner.DefaultBaselineFile, scanner.DefaultBaselineFile},
+		{"audit/" + auditlog.FileName, auditlog.Path(gitDir)},
+	}
+	for _, doc := range optional {
+		data, err := os.ReadFile(doc.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", doc.path, err)
+		}
+		files = append(files, evidence.File{Name: doc.name, Data: data})
+	}
+
+	var rules []evidenceRule
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range secretScanner.GetRules() {
+		sum := sha256.Sum256([]byte(rule.Pattern.String()))
+		rules = append(rules, evidenceRule{ID: rule.ID, Name: rule.Name, Version: hex.EncodeToString(sum[:6])})
+	}
+
+	hooks := make(map[string]bool)
+	for _, hookName := range []string{"pre-commit", "pre-push"} {
+		data, err := os.ReadFile(filepath.Join(gitDir, "hooks", hookName))
+		hooks[hookName] = err == nil && strings.Contains(string(data), "# Secretlint "+hookName+" hook")
+	}
+
+	summary := evidenceSummary{
+		Scope:       r.Scope,
+		GeneratedAt: r.GeneratedAt,
+		Total:       len(r.Findings),
+		ByRule:      make(map[string]int),
+		Findings:    r.Findings,
+	}
+	unique := make(map[string]bool)
+	for _, finding := range r.Findings {
+		summary.ByRule[finding.RuleID]++
+		unique[finding.Fingerprint] = true
+	}
+	summary.Unique = len(unique)
+	sort.Slice(summary.Findings, func(i, j int) bool {
+		return summary.Findings[i].Fingerprint < summary.Findings[j].Fingerprint
+	})
+
+	for _, doc := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"rules.json", rules},
+		{"hooks.json", hooks},
+		{"findings-summary.json", summary},
+	} {
+		data, err := json.MarshalIndent(doc.value, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", doc.name, err)
+		}
+		files = append(files, evidence.File{Name: doc.name, Data: append(data, '\n')})
+	}
+
+	return files, nil
+}
+
+func verifyEvidence(bundlePath, keyID string) error {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", bundlePath, err)
+	}
+	defer file.Close()
+
+	manifest, err := evidence.Verify(file, keyID)
+	if err != nil {
+		return fmt.Errorf("evidence bundle %s failed verification: %w", bundlePath, err)
+	}
+
+	fmt.Printf("✅ %s is intact and signed by key %s\n", bundlePath, manifest.KeyID)
+	fmt.Printf("   Generated : %s by secretlint %s\n", manifest.GeneratedAt.Format(time.RFC3339), manifest.ToolVersion)
+	fmt.Printf("   Repository: %s (%s @ %.12s), scope %s\n", manifest.Repo, manifest.Branch, manifest.Head, manifest.Scope)
+	for _, digest := range manifest.Files {
+		fmt.Printf("   %-36s %s\n", digest.Name, digest.SHA256[:16])
+	}
+	if keyID == "" {
+		fmt.Println("⚠️  Pass --key-id to also check the bundle was signed by a trusted key")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"secretlint/internal/config"
+	"secretlint/internal/scanner"
+)
+
+// runExplain prints the long-form help for a rule, which findings point to
+// instead of repeating it in every report
+func runExplain(args []string) error {
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: secretlint explain <RULE_ID>\n\nRules:\n  %s", strings.Join(scanner.RuleIDs(cfg), "\n  "))
+	}
+
+	explanation, ok := scanner.Explain(args[0], cfg)
+	if !ok {
+		return fmt.Errorf("unknown rule %q; run 'secretlint explain' to list rules", args[0])
+	}
+	rule := explanation.Rule
+
+	fmt.Printf("📖 %s (%s)\n\n", rule.ID, rule.Name)
+	if explanation.Details != "" {
+		fmt.Printf("%s\n\n", explanation.Details)
+	} else if rule.Description != "" {
+		fmt.Printf("%s\n\n", rule.Description)
+	}
+
+	switch {
+	case !cfg.RuleEnabled(rule.ID):
+		fmt.Printf("Status   : disabled in %s\n", config.DefaultConfigFile)
+	case rule.ID == scanner.LiteralSecretRule && !cfg.Settings.References.Enabled:
+		fmt.Printf("Status   : off (set settings.references.enabled in %s)\n", config.DefaultConfigFile)
+	case explanation.Pack != "" && !packEnabled(cfg.Packs, explanation.Pack):
+		fmt.Printf("Status   : part of the %s pack, which is not enabled (add it to packs: in %s)\n", explanation.Pack, config.DefaultConfigFile)
+	case explanation.Pack != "":
+		fmt.Printf("Status   : enabled (%s pack)\n", explanation.Pack)
+	default:
+		fmt.Println("Status   : enabled")
+	}
+	override := cfg.Policy.RuleOverrides[rule.ID]
+	if override.Severity != "" {
+		fmt.Printf("Severity : %s (set by the policy from %s)\n", rule.Severity, cfg.Policy.Source)
+	} else {
+		fmt.Printf("Severity : %s\n", rule.Severity)
+	}
+	if explanation.Rationale != "" {
+		fmt.Printf("Why      : %s\n", explanation.Rationale)
+	}
+
+	if len(explanation.Examples) > 0 {
+		fmt.Println("\nExample matches:")
+		for _, example := range explanation.Examples {
+			fmt.Printf("  %s\n", strings.ReplaceAll(example, "\n", "\n  "))
+		}
+	}
+
+	fmt.Println("\nRemediation:")
+	step := 1
+	if rule.Remediation.RevokeURL != "" {
+		fmt.Printf("  %d. Revoke the secret: %s\n", step, rule.Remediation.RevokeURL)
+		step++
+	}
+	if rule.Remediation.RotateCommand != "" {
+		fmt.Printf("  %d. Rotate it: %s\n", step, rule.Remediation.RotateCommand)
+		step++
+	}
+	// The policy's advice, e.g. an internal runbook, comes before the generic steps
+	if override.Advice != "" {
+		fmt.Printf("  %d. %s\n", step, rule.Advice)
+		step++
+	}
+	for _, s := range explanation.Steps {
+		fmt.Printf("  %d. %s\n", step, s)
+		step++
+	}
+	if rule.Advice != "" && override.Advice == "" && len(explanation.Steps) == 0 {
+		fmt.Printf("  %d. %s\n", step, rule.Advice)
+	}
+	fmt.Printf("  If the match is a false positive, put 'secretlint:allow %s' in a comment on the line.\n", rule.ID)
+
+	if len(rule.Remediation.DocLinks) > 0 {
+		fmt.Println("\nLinks:")
+		for _, link := range rule.Remediation.DocLinks {
+			fmt.Printf("  %s\n", link)
+		}
+	}
+	return nil
+}
+
+// packEnabled reports whether pack is listed in packs; optional pack names
+// are case-insensitive
+func packEnabled(packs []string, pack string) bool {
+	for _, p := range packs {
+		if strings.EqualFold(p, pack) {
+			return true
+		}
+	}
+	return false
+}
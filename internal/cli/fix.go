@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+
+	"secretlint/internal/fix"
+)
+
+func runFix(args []string) error {
+	fmt.Println("🔧 Moving detected secrets to environment variables...")
+
+	differ, findings, err := collectStagedFindings()
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		fmt.Println("✅ No secrets detected in staged changes")
+		return nil
+	}
+
+	fixer := fix.NewFixer()
+
+	// Only restage files whose working tree matched the index before fixing,
+	// otherwise unrelated unstaged edits would be swept into the commit
+	cleanFiles := make(map[string]bool)
+	for _, finding := range findings {
+		if _, checked := cleanFiles[finding.FilePath]; checked {
+			continue
+		}
+		dirty, err := differ.HasUnstagedChanges(finding.FilePath)
+		if err != nil {
+			return err
+		}
+		cleanFiles[finding.FilePath] = !dirty
+	}
+	gitignoreDirty, err := differ.HasUnstagedChanges(".gitignore")
+	if err != nil {
+		return err
+	}
+
+	result, err := fixer.Apply(findings)
+	if err != nil {
+		return fmt.Errorf("failed to fix secrets: %w", err)
+	}
+
+	for _, change := range result.Changes {
+		fmt.Printf("✅ %s:%d - %s replaced with %s\n", change.FilePath, change.LineNum, change.RuleID, change.Reference)
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Printf("⚠️  %s:%d - %s not fixed: %s\n", skipped.FilePath, skipped.LineNum, skipped.RuleID, skipped.Reason)
+	}
+
+	if len(result.UnstageFiles) > 0 {
+		if err := differ.UnstageFiles(result.UnstageFiles...); err != nil {
+			return err
+		}
+		for _, filePath := range result.UnstageFiles {
+			fmt.Printf("🚫 Unstaged %s (environment files must not be committed)\n", filePath)
+		}
+	}
+
+	var restage, manual []string
+	for _, filePath := range result.ModifiedFiles {
+		if cleanFiles[filePath] {
+			restage = append(restage, filePath)
+		} else {
+			manual = append(manual, filePath)
+		}
+	}
+	if result.GitignoreUpdated {
+		fmt.Printf("✅ Added %s to %s\n", result.EnvFile, result.GitignoreFile)
+		if gitignoreDirty {
+			manual = append(manual, result.GitignoreFile)
+		} else {
+			restage = append(restage, result.GitignoreFile)
+		}
+	}
+
+	if err := differ.StageFiles(restage...); err != nil {
+		return err
+	}
+	if len(restage) > 0 {
+		fmt.Printf("📦 Restaged %d file(s)\n", len(restage))
+	}
+	for _, filePath := range manual {
+		fmt.Printf("💡 %s has other unstaged changes - review and 'git add' it yourself\n", filePath)
+	}
+
+	if len(result.Changes) > 0 {
+		fmt.Printf("\n🔑 Secret values were written to %s\n", result.EnvFile)
+		fmt.Println("Remember to rotate any credential that may have been shared.")
+	}
+
+	if len(result.Skipped) > 0 {
+		return fmt.Errorf("%d secret(s) could not be fixed automatically", len(result.Skipped))
+	}
+	return nil
+}
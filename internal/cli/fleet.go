@@ -0,0 +1,290 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/fleet"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+	"secretlint/internal/store"
+)
+
+func runFleet(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: secretlint fleet <subcommand>\n\nSubcommands:\n  scan    Clone or update many repositories, scan each and aggregate the results")
+	}
+
+	switch args[0] {
+	case "scan":
+		return runFleetScan(args[1:])
+	default:
+		return fmt.Errorf("unknown fleet subcommand: %s", args[0])
+	}
+}
+
+// fleetResult is the outcome of scanning one repository of the fleet
+type fleetResult struct {
+	summary  report.RepoSummary
+	findings []report.Finding
+}
+
+// runFleetScan scans every repository of a list or GitHub organization with
+// its own configuration, several at a time, and aggregates one report
+func runFleetScan(args []string) error {
+	flags := flag.NewFlagSet("fleet scan", flag.ContinueOnError)
+	reposFile := flags.String("repos", "", "File listing clone URLs or local paths, one per line")
+	githubOrg := flags.String("github-org", "", "Scan every non-archived repository of this GitHub organization (GITHUB_TOKEN)")
+	baseURL := flags.String("url", "https://api.github.com", "GitHub API base URL (for GitHub Enterprise)")
+	workdir := flags.String("workdir", "", "Where repositories are cloned and kept between runs (default: user cache directory)")
+	concurrency := flags.Int("concurrency", 4, "Number of repositories cloned and scanned at once")
+	history := flags.Bool("history", false, "Scan every commit instead of the tracked files at HEAD (full clones)")
+	format := flags.String("format", "text", "Output format: text or json")
+	output := flags.String("out", "", "Also write the aggregated JSON report to this file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if (*reposFile == "") == (*githubOrg == "") {
+		return fmt.Errorf("usage: secretlint fleet scan --repos <repos.txt> | --github-org <org> [flags]")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: text, json)", *format)
+	}
+	if *concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	// Keep stdout clean for machine-readable output
+	var status io.Writer = os.Stdout
+	if *format == "json" {
+		status = os.Stderr
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	var repos []fleet.Repo
+	if *githubOrg != "" {
+		if err := requireOnline(cfg, "fleet scan --github-org"); err != nil {
+			return err
+		}
+		if repos, err = fleet.GitHubOrg(*baseURL, *githubOrg, os.Getenv("GITHUB_TOKEN")); err != nil {
+			return err
+		}
+	} else if repos, err = fleet.ParseList(*reposFile); err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if repo.IsRemote() {
+			if err := requireOnline(cfg, "cloning "+repo.Source); err != nil {
+				return err
+			}
+		}
+	}
+	if len(repos) == 0 {
+		fmt.Fprintln(status, "ℹ️  No repositories to scan")
+		return nil
+	}
+
+	if *workdir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return fmt.Errorf("no cache directory for clones (pass --workdir): %w", err)
+		}
+		*workdir = filepath.Join(dir, "secretlint", "fleet")
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the secretlint binary: %w", err)
+	}
+
+	fmt.Fprintf(status, "🚢 Scanning %d repositories, %d at a time...\n", len(repos), *concurrency)
+	results := make([]fleetResult, len(repos))
+	var progress sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, *concurrency)
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, repo fleet.Repo) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			results[i] = scanFleetRepo(executable, repo, *workdir, *history)
+			progress.Lock()
+			printFleetSummary(status, results[i].summary)
+			progress.Unlock()
+		}(i, repo)
+	}
+	wg.Wait()
+
+	var summaries []report.RepoSummary
+	var findings []report.Finding
+	blocking, failed := 0, 0
+	for _, result := range results {
+		summaries = append(summaries, result.summary)
+		findings = append(findings, result.findings...)
+		blocking += result.summary.Blocking
+		if result.summary.Error != "" {
+			failed++
+		}
+	}
+	fleetReport := report.NewFleet(summaries, findings)
+	if !*history {
+		recordFleet(cfg, status, results)
+	}
+
+	if *format == "json" {
+		if err := fleetReport.Write(os.Stdout); err != nil {
+			return err
+		}
+	}
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *output, err)
+		}
+		err = fleetReport.Write(file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(fleetReport.Duplicates) > 0 {
+		fmt.Fprintf(status, "\n🔁 %d secret(s) appear in more than one place:\n", len(fleetReport.Duplicates))
+		for _, duplicate := range fleetReport.Duplicates {
+			fmt.Fprintf(status, "   %s %s in %s\n", duplicate.Fingerprint, duplicate.RuleID, strings.Join(duplicate.Locations, ", "))
+		}
+	}
+	fmt.Fprintf(status, "\n📊 %d repositories, %d finding(s), %d blocking, %d failed to scan\n", len(repos), len(findings), blocking, failed)
+
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%d repositories could not be scanned", failed)
+	case blocking > 0:
+		return fmt.Errorf("secrets detected in the fleet")
+	}
+	return nil
+}
+
+// scanFleetRepo brings one repository up to date and scans it in a separate
+// secretlint process, so each scan runs in its own directory with its own
+// config, .secretignore and baseline
+func scanFleetRepo(executable string, repo fleet.Repo, workdir string, history bool) fleetResult {
+	result := fleetResult{summary: report.RepoSummary{Repo: repo.Name, Source: repo.Source}}
+
+	dir := repo.Source
+	if !repo.IsRemote() {
+		if _, err := os.Stat(dir); err != nil {
+			result.summary.Error = fmt.Sprintf("no repository at %s", dir)
+			return result
+		}
+	} else {
+		dir = filepath.Join(workdir, filepath.FromSlash(repo.Name))
+		if err := fleet.Sync(repo, dir, history); err != nil {
+			result.summary.Error = err.Error()
+			return result
+		}
+	}
+
+	mode := "--all"
+	if history {
+		mode = "--history"
+	}
+	var stdout, stderr bytes.Buffer
+	// Findings are attributed to the repository, so keep paths relative to it
+	cmd := exec.Command(executable, "scan", mode, "--format", "json", "--absolute-paths=false")
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// A blocking finding exits non-zero too; only a missing report is a failure
+	runErr := cmd.Run()
+
+	var repoReport report.Report
+	if err := json.Unmarshal(stdout.Bytes(), &repoReport); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if i := strings.LastIndex(message, "\n"); i >= 0 {
+			message = message[i+1:]
+		}
+		if message == "" && runErr != nil {
+			message = runErr.Error()
+		}
+		result.summary.Error = "scan failed: " + message
+		return result
+	}
+
+	for _, finding := range repoReport.Findings {
+		finding.Repo = repo.Name
+		result.findings = append(result.findings, finding)
+		if finding.Severity != scanner.SeverityWarning {
+			result.summary.Blocking++
+		}
+	}
+	result.summary.Findings = len(result.findings)
+	return result
+}
+
+// recordFleet adds each repository's scan to the shared findings database,
+// under the repository's owner/name, when settings.store uses sqlite or
+// postgres. Like recordScan it is best effort.
+func recordFleet(cfg *config.Config, status io.Writer, results []fleetResult) {
+	settings := cfg.Settings.Store
+	if !settings.Enabled || settings.Backend == "" || settings.Backend == "file" {
+		return
+	}
+	now := time.Now().UTC()
+	recorded := 0
+	for _, result := range results {
+		if result.summary.Error != "" {
+			continue
+		}
+		backend, err := sharedStore(settings, result.summary.Repo)
+		if err == nil {
+			err = recordFleetRepo(backend, result.findings, now)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not record scan of %s: %v\n", result.summary.Repo, err)
+			continue
+		}
+		recorded++
+	}
+	fmt.Fprintf(status, "🗄️  Recorded %d repositories in the %s findings database\n", recorded, settings.Backend)
+}
+
+func recordFleetRepo(backend store.Backend, findings []report.Finding, now time.Time) error {
+	db, err := store.OpenBackend(backend)
+	if err != nil {
+		return err
+	}
+	detections := make([]store.Detection, len(findings))
+	for i, finding := range findings {
+		detections[i] = store.Detection{Fingerprint: finding.Fingerprint, RuleID: finding.RuleID, File: finding.File, Line: finding.Line}
+	}
+	db.RecordFull("fleet", detections, now)
+	return db.Save()
+}
+
+func printFleetSummary(status io.Writer, summary report.RepoSummary) {
+	switch {
+	case summary.Error != "":
+		fmt.Fprintf(status, "❌ %s: %s\n", summary.Repo, summary.Error)
+	case summary.Blocking > 0:
+		fmt.Fprintf(status, "⛔ %s: %d finding(s), %d blocking\n", summary.Repo, summary.Findings, summary.Blocking)
+	case summary.Findings > 0:
+		fmt.Fprintf(status, "⚠️  %s: %d warning(s)\n", summary.Repo, summary.Findings)
+	default:
+		fmt.Fprintf(status, "✅ %s: clean\n", summary.Repo)
+	}
+}
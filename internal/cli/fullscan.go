@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"secretlint/internal/archive"
+	"secretlint/internal/config"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+)
+
+// scanAll scans every tracked file in the working tree, not just staged changes
+func scanAll(cfg *config.Config, options scanOptions) error {
+	status := options.status
+	differ := scanner.NewGitDiffer()
+	if scanner.CheckGit() == nil && !differ.IsInGitRepo() && otherVCS() == nil {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	findings, scanned, stopped, err := collectTrackedFindings(cfg, differ, options.stopAt, options.paths)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(status, "📂 Scanned %d tracked file(s)\n", len(scanned))
+	if stopped {
+		fmt.Fprintf(status, "⚠️  Stopped scanning after %d finding(s) (--stop-at-max); later files were not scanned\n", len(findings))
+	}
+	if differ.IsInGitRepo() {
+		recordFullScan(cfg, differ, "all", scanned, findings)
+	}
+	options.stats.files = len(scanned)
+	options.stats.addFindings(findings)
+
+	if options.format == "json" {
+		if err := annotateReport(report.New("all", findings)).Write(os.Stdout); err != nil {
+			return err
+		}
+	}
+
+	if len(findings) == 0 {
+		fmt.Fprintln(status, "✅ No secrets detected in tracked files")
+		return nil
+	}
+
+	blocking := len(scanner.BlockingFindings(findings)) > 0
+	if blocking {
+		fmt.Fprintf(status, "\n⛔ %d secret(s) detected in tracked files:\n\n", len(findings))
+	} else {
+		fmt.Fprintf(status, "\n⚠️  %d warning(s) in tracked files (not blocking):\n\n", len(findings))
+	}
+	if options.format != "json" {
+		if options.groupBy == "owner" {
+			printFindingsByOwner(findings, options.output)
+		} else {
+			printFindings(findings, options.output)
+		}
+	}
+
+	if !blocking {
+		return nil
+	}
+	return fmt.Errorf("secrets detected in tracked files")
+}
+
+// collectTrackedFindings scans every tracked file with ownership attached,
+// returning the findings and the files scanned. With stopAt set,
+// scanning stops once that many findings were found and stopped is true.
+// With paths set, only the files under them are scanned.
+func collectTrackedFindings(cfg *config.Config, differ *scanner.GitDiffer, stopAt int, paths []string) (findings []scanner.Finding, scanned []string, stopped bool, err error) {
+	root, files, skipped, err := trackedFiles(differ, paths)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "ℹ️  Skipping %d tracked file(s) outside the sparse checkout or marked skip-worktree\n", skipped)
+	}
+
+	follow := cfg.Settings.Symlinks.Follow
+	if follow == "" {
+		follow = scanner.FollowRepo
+	}
+	err = inDir(root, func() error {
+		secretScanner, err := scanner.NewSecretScanner()
+		if err != nil {
+			return err
+		}
+		progress := newProgress("files", len(files))
+		for _, filePath := range files {
+			if stopAt > 0 && len(findings) >= stopAt {
+				stopped = true
+				break
+			}
+			progress.step(filePath)
+			if secretScanner.GetIgnoreChecker().ShouldIgnore(filePath) {
+				continue
+			}
+			if ok, reason := scanner.CheckReadable(root, filePath, follow); !ok {
+				if reason != "" {
+					fmt.Fprintf(os.Stderr, "ℹ️  Skipped %s: %s\n", filePath, reason)
+				}
+				continue
+			}
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				// Deleted in the working tree but still tracked
+				continue
+			}
+			// Without git-lfs installed, LFS files are checked out as pointers
+			if scanner.IsLFSPointer(data) {
+				object, ok := secretScanner.LFSObject(data)
+				if !ok {
+					findings = append(findings, secretScanner.ScanLines(scanner.ContentLines(filePath, data))...)
+					continue
+				}
+				data = object
+			}
+			if cfg.Settings.Archives.Enabled && archive.IsArchive(filePath) {
+				findings = append(findings, scanArchive(secretScanner, cfg.Settings.Archives, filePath, data)...)
+				scanned = append(scanned, filePath)
+				continue
+			}
+			// UTF-16 and latin-1 files are transcoded; binary files are skipped
+			data, _, ok := scanner.DecodeText(data)
+			if !ok {
+				continue
+			}
+			findings = append(findings, secretScanner.ScanStructured(scanner.ContentLines(filePath, data), data)...)
+			scanned = append(scanned, filePath)
+		}
+		progress.finish()
+
+		findings = append(findings, scanSensitivePaths(cfg, secretScanner, files)...)
+		if severity, ok := filenameSeverity(cfg); ok {
+			findings = append(findings, secretScanner.ScanSymlinks(root, files, severity)...)
+		}
+		enrichOwnership(differ, findings, true)
+		findings = applyRegoPolicy(cfg, differ, "all", findings)
+		return nil
+	})
+	return findings, scanned, stopped, err
+}
+
+// trackedFiles lists the files a full-tree scan covers and the directory
+// their paths are relative to. Without a usable git it falls back to the
+// files under the current directory, with a notice, so --all still works
+// on an export or in a minimal container. A Mercurial or Jujutsu
+// repository's files are walked the same way, from its root. With paths
+// set, only the files under them are listed.
+func trackedFiles(differ *scanner.GitDiffer, paths []string) (root string, files []string, skipped int, err error) {
+	root, files, skipped, err = allTrackedFiles(differ)
+	if err != nil || len(paths) == 0 {
+		return root, files, skipped, err
+	}
+	var selected []string
+	for _, filePath := range files {
+		if underPaths(filePath, paths) {
+			selected = append(selected, filePath)
+		}
+	}
+	if len(selected) == 0 {
+		return "", nil, 0, fmt.Errorf("no tracked files under %s", strings.Join(paths, ", "))
+	}
+	return root, selected, skipped, nil
+}
+
+// allTrackedFiles lists every file trackedFiles chooses from
+func allTrackedFiles(differ *scanner.GitDiffer) (root string, files []string, skipped int, err error) {
+	if vcs := otherVCS(); vcs != nil {
+		if root, err = vcs.RepoRoot(); err != nil {
+			return "", nil, 0, err
+		}
+		files, err = scanner.WalkFiles(root)
+		return root, files, 0, err
+	}
+	if gitErr := scanner.CheckGit(); gitErr != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v; scanning the files under the current directory instead of tracked files\n", gitErr)
+		if root, err = os.Getwd(); err != nil {
+			return "", nil, 0, err
+		}
+		files, err = scanner.WalkFiles(root)
+		return root, files, 0, err
+	}
+	if root, err = differ.RepoRoot(); err != nil {
+		return "", nil, 0, err
+	}
+	files, skipped, err = differ.TrackedFiles()
+	return root, files, skipped, err
+}
+
+// repoRelativePaths converts the absolute paths given to a full scan to
+// paths relative to root, the repository root the scan runs from
+func repoRelativePaths(root string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	relative := make([]string, len(paths))
+	for i, path := range paths {
+		// git reports the root with symlinks resolved
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+			path = filepath.Join(dir, filepath.Base(path))
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+			return nil, fmt.Errorf("%s is outside the repository at %s", path, root)
+		}
+		relative[i] = filepath.ToSlash(rel)
+	}
+	return relative, nil
+}
+
+// underPaths reports whether a repo-relative file path is one of paths or
+// inside one of them
+func underPaths(filePath string, paths []string) bool {
+	for _, path := range paths {
+		if path == "." || filePath == path || strings.HasPrefix(filePath, path+"/") {
+			return true
+		}
+	}
+	return false
+}
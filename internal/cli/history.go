@@ -0,0 +1,401 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"secretlint/internal/config"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+)
+
+// scanHistory scans every commit reachable from revs (all refs by default)
+// and reports each leaked secret's lifetime, grouped by fingerprint. With
+// several repos, secrets shared between them are reported once.
+func scanHistory(options scanOptions, revs []string, repos []string) error {
+	status := options.status
+
+	var findings []scanner.Finding
+	headLocations := make(map[string][]string)
+	if len(repos) == 0 {
+		repoFindings, repoHead, err := scanRepoHistory(status, options.stats, revs, "", options.stopAt, !options.noCache)
+		if err != nil {
+			return err
+		}
+		findings, headLocations = repoFindings, repoHead
+	}
+	for _, repo := range repos {
+		// --stop-at-max counts findings across all repositories
+		stopAt := options.stopAt
+		if stopAt > 0 {
+			if len(findings) >= stopAt {
+				fmt.Fprintf(status, "⚠️  Skipping %s: already stopped after %d finding(s) (--stop-at-max)\n", repo, len(findings))
+				continue
+			}
+			stopAt -= len(findings)
+		}
+		var repoFindings []scanner.Finding
+		var repoHead map[string][]string
+		err := inDir(repo, func() error {
+			var err error
+			repoFindings, repoHead, err = scanRepoHistory(status, options.stats, revs, filepath.Base(filepath.Clean(repo)), stopAt, !options.noCache)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", repo, err)
+		}
+		findings = append(findings, repoFindings...)
+		for fingerprint, locations := range repoHead {
+			headLocations[fingerprint] = append(headLocations[fingerprint], locations...)
+		}
+	}
+
+	historyReport := report.NewHistory(findings, headLocations)
+	// Findings of several repositories are attributed by their repo field
+	if len(repos) == 0 {
+		annotateReport(historyReport)
+	}
+	if options.format == "json" {
+		if err := historyReport.Write(os.Stdout); err != nil {
+			return err
+		}
+	}
+
+	if len(findings) == 0 {
+		fmt.Fprintln(status, "✅ No secrets detected in history")
+		return nil
+	}
+
+	if len(historyReport.Duplicates) > 0 && options.format != "json" {
+		fmt.Fprintf(status, "\n🔁 %d secret(s) appear in more than one place:\n", len(historyReport.Duplicates))
+		for _, duplicate := range historyReport.Duplicates {
+			fmt.Fprintf(status, "   %s %s in %d location(s)\n", duplicate.Fingerprint, duplicate.RuleID, len(duplicate.Locations))
+		}
+	}
+
+	fmt.Fprintf(status, "\n⛔ %d secret(s) found in history (%d occurrence(s)):\n\n", len(historyReport.Lifetimes), len(findings))
+	if options.format != "json" {
+		lifetimes := historyReport.Lifetimes
+		if max := options.output.maxFindings; max > 0 && len(lifetimes) > max {
+			lifetimes = lifetimes[:max]
+		}
+		if options.groupBy == "owner" {
+			printLifetimesByOwner(lifetimes)
+		} else {
+			for _, lifetime := range lifetimes {
+				printLifetime(lifetime)
+			}
+		}
+		printTruncation(len(lifetimes), len(historyReport.Lifetimes))
+	}
+
+	return fmt.Errorf("secrets detected in history")
+}
+
+// historyRevs adds the refs of each namespace to revs, so notes or refs
+// written by tooling (refs/pull/*, refs/ci/*) are scanned along with the
+// given revisions. A namespace is a name under refs/ or a refs/... glob.
+func historyRevs(revs []string, namespaces []string) []string {
+	// Without revisions every ref is scanned, namespaces included
+	if len(namespaces) == 0 || len(revs) == 0 {
+		return revs
+	}
+	for _, namespace := range namespaces {
+		glob := strings.TrimSuffix(namespace, "/")
+		if !strings.HasPrefix(glob, "refs/") {
+			glob = "refs/" + glob
+		}
+		if !strings.ContainsAny(glob, "*?[") {
+			glob += "/*"
+		}
+		revs = append(revs, "--glob="+glob)
+	}
+	return revs
+}
+
+// firstParentRevs follows only the first parent of merges, so a branch is
+// scanned as its mainline: each merge is diffed against the branch it was
+// merged into and carries the lines its side branch brought in
+func firstParentRevs(revs []string) []string {
+	if len(revs) == 0 {
+		revs = []string{"--all"}
+	}
+	return append([]string{"--first-parent", "-m"}, revs...)
+}
+
+// scanRepoHistory scans the history of the repository in the working
+// directory. repo labels findings and HEAD locations in multi-repo scans;
+// stopAt, when set, ends the scan once that many findings were found;
+// useCache skips file changes an earlier scan found clean.
+func scanRepoHistory(status io.Writer, stats *scanStats, revs []string, repo string, stopAt int, useCache bool) ([]scanner.Finding, map[string][]string, error) {
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		if vcs := otherVCS(); vcs != nil {
+			return scanVCSHistory(status, stats, vcs, revs, repo, stopAt)
+		}
+		return nil, nil, fmt.Errorf("not in a git repository")
+	}
+
+	lines, err := differ.GetHistoryChanges(revs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	label := "history"
+	if repo != "" {
+		label = repo + " history"
+	}
+	fmt.Fprintf(status, "📜 Found %d added lines across %s\n", len(lines), label)
+	if shallow := differ.ShallowCommits(); len(shallow) > 0 {
+		name := "This repository"
+		if repo != "" {
+			name = repo
+		}
+		fmt.Fprintf(status, "⚠️  %s is a shallow clone: only the fetched commits were scanned, and secrets in files of its %d oldest commit(s) may be older than shown ('git fetch --unshallow' fetches the rest)\n", name, len(shallow))
+	}
+
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return nil, nil, err
+	}
+	var cache *scanner.BlobCache
+	if useCache {
+		if cache, err = openHistoryCache(differ, secretScanner); err != nil {
+			fmt.Fprintf(status, "⚠️  %v; scanning without the history cache\n", err)
+		}
+	}
+	findings, stopped := scanHistoryLines(secretScanner, lines, stopAt, cache)
+	if cache != nil {
+		if cache.Skipped > 0 {
+			fmt.Fprintf(status, "♻️  Skipped %d file change(s) already found clean (--no-cache rescans them)\n", cache.Skipped)
+		}
+		if err := cache.Save(); err != nil {
+			fmt.Fprintf(status, "⚠️  %v\n", err)
+		}
+	}
+	if stopped {
+		fmt.Fprintf(status, "⚠️  Stopped scanning %s after %d finding(s) (--stop-at-max); older commits were not scanned\n", label, len(findings))
+	}
+	stats.files += countFiles(lines)
+	stats.addFindings(findings)
+	for i := range findings {
+		findings[i].Repo = repo
+	}
+
+	// Look up which secrets survive at HEAD, keyed by fingerprint for the report
+	var secrets []string
+	fingerprints := make(map[string][]string)
+	for _, finding := range findings {
+		if _, ok := fingerprints[finding.Secret]; !ok {
+			secrets = append(secrets, finding.Secret)
+		}
+		fingerprints[finding.Secret] = appendUnique(fingerprints[finding.Secret], finding.Fingerprint())
+	}
+	headLocations := make(map[string][]string)
+	if len(secrets) > 0 {
+		bySecret, err := differ.HeadLocations(secrets)
+		if err != nil {
+			return nil, nil, err
+		}
+		for secret, locations := range bySecret {
+			for _, fingerprint := range fingerprints[secret] {
+				for _, location := range locations {
+					if repo != "" {
+						location = repo + ":" + location
+					}
+					headLocations[fingerprint] = append(headLocations[fingerprint], location)
+				}
+			}
+		}
+	}
+
+	enrichOwnership(differ, findings, false)
+	if cfg, err := config.Load(config.DefaultConfigFile); err == nil {
+		findings = applyRegoPolicy(cfg, differ, "history", findings)
+	}
+	blameHeadLocations(differ, findings, headLocations)
+
+	return findings, headLocations, nil
+}
+
+// inDir runs fn with the working directory switched to dir, so a scan picks
+// up that repository's .secretignore and baseline
+func inDir(dir string, fn func() error) error {
+	previous, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("failed to enter %s: %w", dir, err)
+	}
+	defer os.Chdir(previous)
+	return fn()
+}
+
+// scanHistoryLines scans history one commit at a time, showing progress
+// on a terminal. The lines of a commit are contiguous in the log.
+func scanHistoryLines(secretScanner *scanner.SecretScanner, lines []scanner.DiffLine, stopAt int, cache *scanner.BlobCache) ([]scanner.Finding, bool) {
+	commits := 0
+	for i, line := range lines {
+		if i == 0 || line.Commit != lines[i-1].Commit {
+			commits++
+		}
+	}
+
+	var findings []scanner.Finding
+	reused := make(map[string][]scanner.Finding)
+	progress := newProgress("commits", commits)
+	for start := 0; start < len(lines); {
+		if stopAt > 0 && len(findings) >= stopAt {
+			progress.finish()
+			return findings, true
+		}
+		end := start + 1
+		for end < len(lines) && lines[end].Commit == lines[start].Commit {
+			end++
+		}
+		current := lines[start].FilePath
+		if commit := lines[start].Commit; commit != nil {
+			current = fmt.Sprintf("%.12s %s", commit.SHA, commit.Subject)
+		}
+		progress.step(current)
+		findings = append(findings, scanCommitLines(secretScanner, lines[start:end], cache, reused)...)
+		start = end
+	}
+	progress.finish()
+	return findings, false
+}
+
+// scanCommitLines scans the lines of one commit a file change at a time.
+// Changes the cache knows are clean are skipped, and the findings of a
+// change seen earlier in this scan, on another branch or through a
+// cherry-pick, are reused for this commit.
+func scanCommitLines(secretScanner *scanner.SecretScanner, lines []scanner.DiffLine, cache *scanner.BlobCache, reused map[string][]scanner.Finding) []scanner.Finding {
+	if cache == nil {
+		return secretScanner.ScanLines(lines)
+	}
+	var findings []scanner.Finding
+	for start := 0; start < len(lines); {
+		end := start + 1
+		for end < len(lines) && lines[end].FilePath == lines[start].FilePath && lines[end].Blobs == lines[start].Blobs {
+			end++
+		}
+		key := scanner.BlobKey(lines[start])
+		if cache.Clean(key) {
+			cache.Skipped++
+			start = end
+			continue
+		}
+		if earlier, ok := reused[key]; ok {
+			for _, finding := range earlier {
+				finding.Commit = lines[start].Commit
+				findings = append(findings, finding)
+			}
+			start = end
+			continue
+		}
+		changeFindings := secretScanner.ScanLines(lines[start:end])
+		if len(changeFindings) == 0 {
+			cache.MarkClean(key)
+		} else if key != "" {
+			reused[key] = changeFindings
+		}
+		findings = append(findings, changeFindings...)
+		start = end
+	}
+	return findings
+}
+
+// openHistoryCache loads the history cache of the repository for the
+// scanner's rules, config, .secretignore and baseline; changing any of
+// them, or upgrading secretlint, starts a new cache
+func openHistoryCache(differ *scanner.GitDiffer, secretScanner *scanner.SecretScanner) (*scanner.BlobCache, error) {
+	gitDir, err := differ.GitDir()
+	if err != nil {
+		return nil, err
+	}
+	version := sha256.New()
+	fmt.Fprintf(version, "%s\n", Version)
+	for _, rule := range secretScanner.GetRules() {
+		fmt.Fprintf(version, "%s\x00%s\x00%s\n", rule.ID, rule.Pattern, rule.Severity)
+	}
+	if cfg, err := config.Load(config.DefaultConfigFile); err == nil {
+		if data, err := yaml.Marshal(cfg); err == nil {
+			version.Write(data)
+		}
+	}
+	for _, path := range []string{".secretignore", scanner.DefaultBaselineFile} {
+		if data, err := os.ReadFile(path); err == nil {
+			fmt.Fprintf(version, "%s\x00", path)
+			version.Write(data)
+		}
+	}
+	return scanner.LoadBlobCache(filepath.Join(gitDir, "secretlint", "history-cache"), hex.EncodeToString(version.Sum(nil)))
+}
+
+// printLifetime renders one secret's history: who introduced it, when, and where it lives now
+func printLifetime(lifetime report.Lifetime) {
+	fmt.Printf("FP       : %s\n", lifetime.Fingerprint)
+	fmt.Printf("Rule     : %s\n", lifetime.RuleID)
+	fmt.Printf("Snippet  : %s\n", lifetime.Snippet)
+	if commit := lifetime.IntroducedBy; commit != nil {
+		fmt.Printf("Added    : %s\n", formatCommit(*commit))
+	}
+	if commit := lifetime.LastAddedBy; commit != nil && len(lifetime.Commits) > 1 {
+		fmt.Printf("Re-added : %s\n", formatCommit(*commit))
+		fmt.Printf("Commits  : %d\n", len(lifetime.Commits))
+	}
+	fmt.Printf("Files    : %s\n", strings.Join(lifetime.Files, ", "))
+	if lifetime.AtHead {
+		fmt.Printf("At HEAD  : yes - %s\n", strings.Join(lifetime.HeadFiles, ", "))
+	} else {
+		fmt.Println("At HEAD  : no - removed from the tree but still in history (see 'secretlint purge')")
+	}
+	if len(lifetime.Owners) > 0 {
+		fmt.Printf("Owners   : %s\n", strings.Join(lifetime.Owners, " "))
+	}
+	if commit := lifetime.LastTouched; commit != nil {
+		fmt.Printf("Touched  : %s\n", formatCommit(*commit))
+	}
+	fmt.Println()
+}
+
+// printLifetimesByOwner renders lifetimes under a heading per owning team
+func printLifetimesByOwner(lifetimes []report.Lifetime) {
+	counts := make(map[string]int)
+	grouped := make(map[string][]report.Lifetime)
+	for _, lifetime := range lifetimes {
+		key := ownerKey(lifetime.Owners)
+		counts[key]++
+		grouped[key] = append(grouped[key], lifetime)
+	}
+	for _, key := range sortedOwnerKeys(counts) {
+		fmt.Printf("👥 %s (%d)\n\n", key, counts[key])
+		for _, lifetime := range grouped[key] {
+			printLifetime(lifetime)
+		}
+	}
+}
+
+// formatCommit renders a commit as "sha date author <email> - subject"
+func formatCommit(commit report.Commit) string {
+	formatted := fmt.Sprintf("%.12s %s %s <%s> - %s", commit.SHA, commit.Date.Format("2006-01-02"), commit.Author, commit.Email, commit.Subject)
+	if commit.Shallow {
+		formatted += " (shallow clone boundary; may be older)"
+	}
+	return formatted
+}
+
+// appendUnique appends value unless it is already present
+func appendUnique(values []string, value string) []string {
+	if containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
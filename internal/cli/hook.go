@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"secretlint/internal/config"
+	"secretlint/internal/integrity"
+	"secretlint/internal/scanner"
+)
+
+func runHook(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: secretlint hook <subcommand>\n\nSubcommands:\n  verify  Check the installed hooks and config against the hashes recorded at init\n  record  Record the current hooks and config as the trusted state\n  deferred Scan the archives a time-budgeted pre-commit hook left for later (started by the hook)")
+	}
+
+	switch args[0] {
+	case "verify":
+		return runHookVerify()
+	case "record":
+		return runHookRecord()
+	case "deferred":
+		return runHookDeferred()
+	default:
+		return fmt.Errorf("unknown hook subcommand: %s", args[0])
+	}
+}
+
+// protectedFiles lists the files whose tampering would weaken scanning,
+// relative to the main worktree, and returns where their hashes are kept.
+// Linked worktrees share the main .git directory and its hooks, so they
+// check the same files against the same hashes.
+func protectedFiles(differ *scanner.GitDiffer) (string, []string, string, error) {
+	if !differ.IsInGitRepo() {
+		return "", nil, "", fmt.Errorf("not in a git repository")
+	}
+	gitDir, err := differ.GitDir()
+	if err != nil {
+		return "", nil, "", err
+	}
+	absGitDir, err := filepath.Abs(gitDir)
+	if err != nil {
+		return "", nil, "", err
+	}
+	root := filepath.Dir(absGitDir)
+	hooksDir := filepath.Join(filepath.Base(absGitDir), "hooks")
+
+	files := []string{config.DefaultConfigFile}
+	for _, hook := range []string{"pre-commit", "pre-push", "secretlint-config"} {
+		files = append(files, filepath.ToSlash(filepath.Join(hooksDir, hook)))
+	}
+	return root, files, integrity.Path(gitDir), nil
+}
+
+// recordIntegrity stores the hashes that 'secretlint hook verify' checks against
+func recordIntegrity(differ *scanner.GitDiffer) (*integrity.Manifest, error) {
+	root, files, manifestPath, err := protectedFiles(differ)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := integrity.Record(root, files)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, manifest.Save(manifestPath)
+}
+
+func runHookRecord() error {
+	manifest, err := recordIntegrity(scanner.NewGitDiffer())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🔏 Recorded hashes of %d file(s)\n", len(manifest.Files))
+	return nil
+}
+
+// runHookVerify reports hooks and config that changed since their hashes were
+// recorded, e.g. a pre-commit hook edited to always exit 0
+func runHookVerify() error {
+	differ := scanner.NewGitDiffer()
+	root, files, manifestPath, err := protectedFiles(differ)
+	if err != nil {
+		return err
+	}
+	manifest, err := integrity.Load(manifestPath)
+	if err != nil {
+		return err
+	}
+	changes, err := manifest.Verify(root, files)
+	if err != nil {
+		return err
+	}
+
+	// A different hooks directory means the installed hooks never run
+	problems := len(changes)
+	if hooksPath := differ.ConfigValue("core.hooksPath"); hooksPath != "" {
+		fmt.Printf("⛔ core.hooksPath is set to %s, so the installed hooks don't run\n", hooksPath)
+		problems++
+	}
+	for _, change := range changes {
+		fmt.Printf("⛔ %s: %s\n", change.Path, change.Problem)
+	}
+
+	if problems > 0 {
+		fmt.Printf("\nHashes were recorded %s. Run 'secretlint init' to restore the hooks, then\n'secretlint hook record' once the changes are reviewed.\n", manifest.RecordedAt.Local().Format("2006-01-02 15:04:05"))
+		return fmt.Errorf("hook or config files were modified")
+	}
+	fmt.Printf("✅ Hooks and config match the hashes recorded %s\n", manifest.RecordedAt.Local().Format("2006-01-02 15:04:05"))
+	return nil
+}
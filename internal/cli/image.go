@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"secretlint/internal/archive"
+	"secretlint/internal/image"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+)
+
+// scanImage scans the files in every layer of a container image plus its
+// ENV, LABEL and build history metadata. Findings are reported at paths like
+// app:latest!layer3/app/.env and app:latest!config.env. Images missing
+// locally are pulled only when pull is set.
+func scanImage(options scanOptions, ref string, pull bool) error {
+	status := options.status
+	fmt.Fprintf(status, "🐳 Exporting image %s...\n", ref)
+	tarPath, cleanup, err := image.Save(ref, pull)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+	var findings []scanner.Finding
+	scanned := 0
+	cfg, err := image.Walk(tarPath, func(file image.File) {
+		text, _, ok := scanner.DecodeText(file.Content)
+		if !ok {
+			return
+		}
+		filePath := ref + archive.Separator + "layer" + strconv.Itoa(file.Layer) + "/" + file.Path
+		if secretScanner.GetIgnoreChecker().ShouldIgnore(filePath) {
+			return
+		}
+		findings = append(findings, secretScanner.ScanStructured(scanner.ContentLines(filePath, text), text)...)
+		scanned++
+	})
+	if err != nil {
+		return err
+	}
+	findings = append(findings, scanImageConfig(secretScanner, ref, cfg)...)
+
+	fmt.Fprintf(status, "📂 Scanned %d file(s) and the image config\n", scanned)
+	options.stats.files = scanned
+	options.stats.addFindings(findings)
+
+	if options.format == "json" {
+		if err := report.New("image", findings).Write(os.Stdout); err != nil {
+			return err
+		}
+	}
+	if len(findings) == 0 {
+		fmt.Fprintf(status, "✅ No secrets detected in %s\n", ref)
+		return nil
+	}
+
+	fmt.Fprintf(status, "\n⛔ %d secret(s) detected in image %s:\n\n", len(findings), ref)
+	if options.format != "json" {
+		printFindings(findings, options.output)
+	}
+	return fmt.Errorf("secrets detected in image %s", ref)
+}
+
+// scanImageConfig scans ENV and LABEL values and build args as dotenv files,
+// which flags credential-looking names, and the build history with the token rules
+func scanImageConfig(secretScanner *scanner.SecretScanner, ref string, cfg *image.Config) []scanner.Finding {
+	var env []string
+	env = append(env, cfg.Env...)
+	var labels []string
+	for key, value := range cfg.Labels {
+		labels = append(labels, key+"="+value)
+	}
+	sort.Strings(labels)
+	env = append(env, labels...)
+
+	envPath := ref + archive.Separator + "config.env"
+	content := []byte(strings.Join(env, "\n"))
+	findings := secretScanner.ScanStructured(scanner.ContentLines(envPath, content), content)
+
+	// Build args used by RUN steps are recorded as "|2 KEY=value KEY2=value /bin/sh -c ..."
+	var buildArgs []string
+	for _, createdBy := range cfg.History {
+		fields := strings.Fields(createdBy)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "|") {
+			continue
+		}
+		count, err := strconv.Atoi(strings.TrimPrefix(fields[0], "|"))
+		if err != nil {
+			continue
+		}
+		for i := 1; i <= count && i < len(fields); i++ {
+			buildArgs = append(buildArgs, fields[i])
+		}
+	}
+	argsPath := ref + archive.Separator + "build-args.env"
+	content = []byte(strings.Join(buildArgs, "\n"))
+	findings = append(findings, secretScanner.ScanStructured(scanner.ContentLines(argsPath, content), content)...)
+
+	var history []scanner.DiffLine
+	for i, createdBy := range cfg.History {
+		history = append(history, scanner.DiffLine{
+			FilePath: ref + archive.Separator + "history",
+			LineNum:  i + 1,
+			Content:  createdBy,
+		})
+	}
+	return append(findings, secretScanner.ScanLines(history)...)
+}
@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"secretlint/internal/config"
+	"secretlint/internal/detectsecrets"
+	"secretlint/internal/gitleaks"
+	"secretlint/internal/rulepack"
+	"secretlint/internal/scanner"
+)
+
+// importedPackFile keeps the imported rules in the repository, so they can
+// be signed and installed by the rest of the team
+const importedPackFile = "gitleaks-pack.yml"
+
+// runImport converts another scanner's configuration into secretlint's
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := flags.String("from", "", "Tool the configuration comes from: gitleaks or detect-secrets")
+	dryRun := flags.Bool("dry-run", false, "Show the converted configuration without writing anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("usage: secretlint import --from gitleaks|detect-secrets [--dry-run] [file]")
+	}
+
+	switch *from {
+	case "gitleaks":
+		source := gitleaks.DefaultConfigFile
+		if flags.NArg() == 1 {
+			source = flags.Arg(0)
+		}
+		return importGitleaks(source, *dryRun)
+	case "detect-secrets":
+		source := detectsecrets.DefaultBaselineFile
+		if flags.NArg() == 1 {
+			source = flags.Arg(0)
+		}
+		return importDetectSecrets(source, *dryRun)
+	}
+	return fmt.Errorf("usage: secretlint import --from gitleaks|detect-secrets [--dry-run] [file]")
+}
+
+// importGitleaks converts a gitleaks config into a rule pack, allowlist
+// entries and .secretignore patterns
+func importGitleaks(source string, dryRun bool) error {
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	leaks, err := gitleaks.Load(source)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, ruleID := range scanner.RuleIDs(cfg) {
+		known[ruleID] = true
+	}
+	imported := gitleaks.Convert(leaks, "gitleaks", time.Now().Format("2006.01.02"), known)
+
+	fmt.Printf("📥 Importing %s: %d rule(s), %d allowlist entr(ies), %d path exclude(s)\n", source, len(leaks.Rules), len(imported.Allowlist), len(imported.Ignore))
+
+	if imported.Pack != nil {
+		if err := importPack(imported.Pack, dryRun); err != nil {
+			return err
+		}
+	}
+	if len(imported.Allowlist) > 0 {
+		if err := importAllowlist(source, imported.Allowlist, dryRun); err != nil {
+			return err
+		}
+	}
+	if len(imported.Ignore) > 0 {
+		if err := importIgnores(source, imported.Ignore, dryRun); err != nil {
+			return err
+		}
+	}
+
+	if len(imported.Notes) > 0 {
+		fmt.Printf("\n⚠️  %d setting(s) were adapted or not imported:\n", len(imported.Notes))
+		for _, note := range imported.Notes {
+			fmt.Printf("   - %s\n", note)
+		}
+	}
+	if !dryRun {
+		fmt.Println("\n💡 Run 'secretlint scan --all' to compare the results with gitleaks")
+	}
+	return nil
+}
+
+// importDetectSecrets accepts the findings of a detect-secrets baseline into
+// ours. detect-secrets only stores a hash of each secret, so the files are
+// rescanned to find the secrets and compute their fingerprints.
+func importDetectSecrets(source string, dryRun bool) error {
+	imported, err := detectsecrets.Load(source)
+	if err != nil {
+		return err
+	}
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		return fmt.Errorf("not in a git repository")
+	}
+	root, err := differ.RepoRoot()
+	if err != nil {
+		return err
+	}
+
+	var added, confirmed int
+	var unmatched []string
+	err = inDir(root, func() error {
+		secretScanner, err := scanner.NewSecretScanner()
+		if err != nil {
+			return err
+		}
+		baseline := secretScanner.GetBaseline()
+		for _, file := range imported.Files() {
+			var findings []scanner.Finding
+			if data, err := os.ReadFile(file); err == nil {
+				if text, _, ok := scanner.DecodeText(data); ok {
+					findings = secretScanner.ScanStructured(scanner.ContentLines(file, text), text)
+				}
+			}
+			for _, secret := range imported.Results[file] {
+				location := fmt.Sprintf("%s:%d (%s)", file, secret.LineNumber, secret.Type)
+				if secret.IsSecret != nil && *secret.IsSecret {
+					// Audited as real: it has to be rotated, not accepted
+					confirmed++
+					continue
+				}
+				finding, ok := secret.Match(findings)
+				if !ok {
+					unmatched = append(unmatched, location)
+					continue
+				}
+				before := len(baseline.Entries)
+				baseline.Add(finding, fmt.Sprintf("Imported from detect-secrets baseline (%s)", secret.Type))
+				added += len(baseline.Entries) - before
+			}
+		}
+		if dryRun || added == 0 {
+			return nil
+		}
+		return baseline.Save(scanner.DefaultBaselineFile)
+	})
+	if err != nil {
+		return err
+	}
+
+	verb := "Added"
+	if dryRun {
+		verb = "Would add"
+	}
+	fmt.Printf("✅ %s %d finding(s) from %s to %s\n", verb, added, source, scanner.DefaultBaselineFile)
+	if confirmed > 0 {
+		fmt.Printf("⛔ %d result(s) audited as real secrets were not imported; rotate them\n", confirmed)
+	}
+	if len(unmatched) > 0 {
+		fmt.Printf("ℹ️  %d result(s) have no secretlint finding (already in the baseline, removed, or not detected by secretlint):\n", len(unmatched))
+		for _, location := range unmatched {
+			fmt.Printf("   %s\n", location)
+		}
+	}
+	return nil
+}
+
+// importPack writes the converted rules as a rule pack, installs it for this
+// user and enables it in the config
+func importPack(pack *rulepack.Pack, dryRun bool) error {
+	data, err := yaml.Marshal(pack)
+	if err != nil {
+		return fmt.Errorf("failed to encode rule pack: %w", err)
+	}
+	if _, err := rulepack.Parse(data); err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Printf("\n📦 Rule pack %s (%s):\n%s", pack.Ref(), importedPackFile, data)
+		return nil
+	}
+
+	if err := os.WriteFile(importedPackFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", importedPackFile, err)
+	}
+	path, err := rulepack.Install(pack, data)
+	if err != nil {
+		return err
+	}
+	if err := registerPack(config.DefaultConfigFile, pack.Name, pack.Ref()); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Converted %d rule(s) into %s, installed to %s and enabled in %s\n", len(pack.Rules), importedPackFile, path, config.DefaultConfigFile)
+	fmt.Printf("   Teammates need the pack too: sign it with minisign and have them run 'secretlint rules install %s'\n", importedPackFile)
+	return nil
+}
+
+// importAllowlist appends the allowlist to the config, unless it already has
+// one, which is left for the user to merge
+func importAllowlist(source string, entries []config.AllowlistEntry, dryRun bool) error {
+	data, err := yaml.Marshal(map[string][]config.AllowlistEntry{"allowlist": entries})
+	if err != nil {
+		return fmt.Errorf("failed to encode allowlist: %w", err)
+	}
+	block := fmt.Sprintf("\n# Imported from %s by 'secretlint import'\n%s", source, data)
+	if dryRun {
+		fmt.Printf("\n📝 Allowlist for %s:%s", config.DefaultConfigFile, block)
+		return nil
+	}
+
+	existing, err := os.ReadFile(config.DefaultConfigFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", config.DefaultConfigFile, err)
+	}
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.HasPrefix(line, "allowlist:") {
+			fmt.Printf("⚠️  %s already has an allowlist; merge these entries into it:%s", config.DefaultConfigFile, block)
+			return nil
+		}
+	}
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		block = "\n" + block
+	}
+	file, err := os.OpenFile(config.DefaultConfigFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", config.DefaultConfigFile, err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(block); err != nil {
+		return fmt.Errorf("failed to update %s: %w", config.DefaultConfigFile, err)
+	}
+	fmt.Printf("✅ Added %d allowlist entr(ies) to %s\n", len(entries), config.DefaultConfigFile)
+	return nil
+}
+
+// importIgnores appends the path excludes .secretignore doesn't have yet
+func importIgnores(source string, globs []string, dryRun bool) error {
+	existing, err := os.ReadFile(".secretignore")
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .secretignore: %w", err)
+	}
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(existing), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, glob := range globs {
+		if !present[glob] {
+			present[glob] = true
+			missing = append(missing, glob)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	block := fmt.Sprintf("\n# Imported from %s by 'secretlint import'\n%s\n", source, strings.Join(missing, "\n"))
+	if dryRun {
+		fmt.Printf("\n🚫 Patterns for .secretignore:%s", block)
+		return nil
+	}
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		block = "\n" + block
+	}
+	file, err := os.OpenFile(".secretignore", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to update .secretignore: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(block); err != nil {
+		return fmt.Errorf("failed to update .secretignore: %w", err)
+	}
+	fmt.Printf("✅ Added %d pattern(s) to .secretignore\n", len(missing))
+	return nil
+}
@@ -1,14 +1,26 @@
 package cli
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"secretlint/internal/config"
+	"secretlint/internal/integrity"
+	"secretlint/internal/scanner"
 )
 
-func runInit() error {
+func runInit(args []string) (err error) {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	interactive := flags.Bool("interactive", false, "Ask which hooks, strictness and CI platform to set up")
+	prePush := flags.Bool("pre-push", false, "Also install the pre-push hook, which blocks pushing commits with secrets")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	
 	fmt.Println("🔧 Initializing secretlint...")
 	
 	// Check if we're in a git repository
@@ -16,20 +28,73 @@ func runInit() error {
 		return err
 	}
 	
+	// Undo a partial install, so a failed or interrupted init never leaves
+	// a hook behind that can't find its config or binary
+	initJournal = &writeJournal{}
+	defer func() {
+		if err != nil {
+			initJournal.rollback()
+		}
+	}()
+	stop := rollbackOnInterrupt(initJournal)
+	defer stop()
+	
+	// Hooks live in the main .git directory, also when run from a linked worktree
+	gitDir, err := scanner.NewGitDiffer().GitDir()
+	if err != nil {
+		return err
+	}
+	hooksDir := filepath.Join(gitDir, "hooks")
+	
 	// Find the current secretlint binary path
 	binaryPath, err := findCurrentBinary()
 	if err != nil {
 		return fmt.Errorf("failed to locate secretlint binary: %w", err)
 	}
 	
+	answers := defaultInitAnswers()
+	answers.prePush = *prePush
+	if *interactive {
+		if answers, err = askInitQuestions(os.Stdin); err != nil {
+			return err
+		}
+	}
+	
 	// Create configuration files
-	if err := createConfigFiles(); err != nil {
+	if err := createConfigFiles(answers); err != nil {
 		return fmt.Errorf("failed to create config files: %w", err)
 	}
 	
 	// Install pre-commit hook with stored binary path
-	if err := installPreCommitHook(binaryPath); err != nil {
-		return fmt.Errorf("failed to install pre-commit hook: %w", err)
+	if answers.preCommit {
+		if err := installPreCommitHook(hooksDir, binaryPath); err != nil {
+			return fmt.Errorf("failed to install pre-commit hook: %w", err)
+		}
+	} else if err := writeSecretlintConfig(filepath.Join(hooksDir, "secretlint-config"), binaryPath); err != nil {
+		return err
+	}
+	
+	// The pre-push hook blocks pushes, so it is only installed when asked
+	// for, or when the config turns on the push quarantine it runs
+	if !answers.prePush && answers.preCommit {
+		if cfg, err := config.Load(config.DefaultConfigFile); err == nil && cfg.Settings.Hook.Quarantine {
+			answers.prePush = true
+		}
+	}
+	if answers.prePush {
+		if err := installPrePushHook(hooksDir); err != nil {
+			return fmt.Errorf("failed to install pre-push hook: %w", err)
+		}
+	}
+	
+	ciPath, err := writeCIFile(answers)
+	if err != nil {
+		return fmt.Errorf("failed to write CI config: %w", err)
+	}
+	
+	// Record hashes so 'secretlint hook verify' can detect later edits
+	if _, err := recordIntegrity(scanner.NewGitDiffer()); err != nil {
+		return fmt.Errorf("failed to record hook hashes: %w", err)
 	}
 	
 	fmt.Println("✅ Secretlint initialized successfully!")
@@ -37,9 +102,22 @@ func runInit() error {
 	fmt.Println("Created files:")
 	fmt.Println("  📄 .secretlintrc.yml - Configuration and rules")
 	fmt.Println("  🚫 .secretignore - Files and patterns to ignore")
-	fmt.Println("  🪝 .git/hooks/pre-commit - Git hook integration")
-	fmt.Printf("  ⚙️  .git/hooks/secretlint-config - Binary path (%s)\n", binaryPath)
+	if answers.preCommit {
+		fmt.Printf("  🪝 %s - Git hook integration\n", filepath.Join(hooksDir, "pre-commit"))
+	}
+	if answers.prePush {
+		fmt.Printf("  🪝 %s - Scans commits before they are pushed\n", filepath.Join(hooksDir, "pre-push"))
+	}
+	fmt.Printf("  ⚙️  %s - Binary path (%s)\n", filepath.Join(hooksDir, "secretlint-config"), binaryPath)
+	fmt.Printf("  🔏 %s - Hashes checked by 'secretlint hook verify'\n", integrity.Path(gitDir))
+	if ciPath != "" {
+		fmt.Printf("  🚢 %s - Full scan on every push\n", ciPath)
+	}
 	fmt.Println("")
+	if !answers.preCommit {
+		fmt.Println("Scan your staged changes with 'secretlint scan' before committing.")
+		return nil
+	}
 	fmt.Println("Try making a commit with secrets to test it:")
 	fmt.Println("  echo 'API_KEY=sk-abc123' > test.txt")
 	fmt.Println("  git add test.txt && git commit -m 'test'")
@@ -48,6 +126,9 @@ func runInit() error {
 }
 
 func checkGitRepository() error {
+	if err := scanner.CheckGit(); err != nil {
+		return fmt.Errorf("%v: the hooks run git, please install git (https://git-scm.com)", err)
+	}
 	if _, err := os.Stat(".git"); err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("not in a git repository - please run 'git init' first")
@@ -100,11 +181,19 @@ func findCurrentBinary() (string, error) {
 	return "", fmt.Errorf("secretlint binary not found. Please build it first: go build -o secretlint cmd/secretlint/main.go")
 }
 
-func createConfigFiles() error {
+func createConfigFiles(answers initAnswers) error {
 	// Create .secretlintrc.yml
 	configContent := `# Secretlint configuration file
 # See https://github.com/ZichenYuan/secretlint for documentation
 
+# Inherit an org-wide config (path or https URL); its policy.mandatory_rules
+# can't be disabled or suppressed here
+# extends: https://config.example.com/secretlint/org.yml
+# Require a minisign signature (<url>.minisig) from this key on extended configs:
+# extends_public_key: RWQ...
+# Remote extended configs are cached and reused for this long (and when offline):
+# extends_ttl: 1h
+
 # Enable/disable specific rules
 rules:
   OPENAI_API_KEY: true
@@ -117,23 +206,206 @@ rules:
   JWT_TOKEN: true
   GENERIC_API_KEY: true
   PRIVATE_KEY: true
+  OPENSSH_PRIVATE_KEY: true
+  PGP_PRIVATE_KEY: true
+  AGE_SECRET_KEY: true
+  NETRC_CREDENTIAL: true
+  NPMRC_AUTH_TOKEN: true
+  PYPI_TOKEN: true
+  PGPASS_CREDENTIAL: true
+
+# Tune the token length or charset of a built-in rule instead of copying its
+# regex into a custom rule. Rules with a token body: OPENAI_API_KEY,
+# GITHUB_PAT, AWS_ACCESS_KEY, AWS_SECRET_KEY, STRIPE_LIVE_PK, STRIPE_LIVE_SK,
+# SLACK_TOKEN and GENERIC_API_KEY
+# rule_options:
+#   STRIPE_LIVE_SK:
+#     max_length: 99       # newer Stripe keys are longer than 24 characters
+#   OPENAI_API_KEY:
+#     charset: A-Za-z0-9_- # project keys (sk-proj-...) contain - and _
+
+# Org-specific detectors that report a pattern only when all of their
+# conditions hold: a keyword within N lines, an entropy threshold and file globs
+# custom_rules:
+#   - id: ISVC_TOKEN
+#     name: Internal Service Token
+#     description: Internal service token found
+#     advice: Load it from the vault with 'isvc token' instead of committing it
+#     pattern: '(?P<secret>ISVC_[A-Za-z0-9]{32})'
+#     keywords: [credential]  # case-insensitive
+#     within: 3               # lines around the match; 0 is the same line
+#     min_entropy: 3.5        # Shannon bits per character of the secret
+#     paths: ["*.yml", "*.yaml"]
+#     not_preceded_by: ["example_"] # in place of (?<!...), which Go regexps lack
+#     not_followed_by: ["_TEST"]    # in place of (?!...)
+#     engine: regexp2         # only for patterns with lookarounds or backreferences
+#     severity: error         # or warning
+
+# Optional rule packs, off unless listed:
+#   pii - credit card numbers (Luhn-checked), national IDs, email:password lines
+#   npm - npm access tokens
+#   cicd - CircleCI, Buildkite, Jenkins, Azure DevOps and Codecov tokens
+#   observability - Sentry DSN secrets, Rollbar, Honeycomb, Grafana Cloud and PagerDuty keys
+#   storage - Backblaze B2, Wasabi, Cloudinary, Fastly and Akamai credentials
+# plus packs installed with 'secretlint rules install', pinned as name@version
+packs: []
+
+# Known false positives to drop; an entry matches on any of its criteria
+# allowlist:
+#   - description: documentation examples
+#     rules: [AWS_ACCESS_KEY]   # default: every rule
+#     regexes: ['EXAMPLE$']     # matched against the secret (target: match or line)
+#     stopwords: [dummy]
+#     paths: ['^docs/']
 
 # Global settings
 settings:
-  # Exit with code 1 when secrets are found (blocks commits)
+  # Exit with code 1 when secrets are found (blocks commits); false only
+  # reports them, like fail_on: never, unless fail_on is set
   fail_on_detection: true
   
   # Show detailed output
   verbose: false
   
-  # Minimum secret length to scan
+  # Show finding paths as absolute paths instead of relative to the repository root
+  absolute_paths: false
+  
+  # Ignore detected values shorter than this; mandatory rules report them all
   min_length: 10
-
-# Custom patterns (future feature)
-custom_rules: []
+  
+  # Pre-commit hook behavior
+  hook:
+    # Unstage files containing secrets (working tree changes are kept).
+    # Run 'SECRETLINT_PARTIAL=1 git commit' to commit the remaining files.
+    auto_unstage: false
+    
+    # On pre-push, offer to move commits containing secrets to a local
+    # quarantine branch so the rest of the branch can still be pushed
+    quarantine: false
+    
+    # When secretlint itself fails in a hook (a crash, a broken config, a git
+    # error): block the commit or push (fail closed), or allow it with a
+    # warning (fail open). SECRETLINT_ON_ERROR overrides this.
+    on_error: block
+    
+    # Scan for at most this long in the pre-commit hook, e.g. 2s. The diff
+    # is always scanned; staged archives that don't fit are scanned in the
+    # background and reported on the next commit. Empty means no limit.
+    time_budget: ""
+  
+  # Record every scan's findings in .git/secretlint/findings.json
+  # so 'secretlint stats' can show trends over time. Staged and pre-push
+  # scans only see added lines; findings are resolved by 'scan --all'.
+  store:
+    enabled: false
+    
+    # file, or sqlite / postgres (through the sqlite3 / psql CLIs) to share
+    # one database between repositories and 'secretlint fleet scan'. dsn is
+    # the SQLite path (default .git/secretlint/findings.db) or the Postgres
+    # connection string; set SECRETLINT_STORE_DSN to keep credentials out of
+    # this file. repo names this repository's rows (default: owner/name of origin).
+    backend: file
+    dsn: ""
+    repo: ""
+  
+  # Append every hook decision to .git/secretlint/audit.log; ship it to a
+  # central endpoint with 'secretlint audit-log ship' (token: SECRETLINT_AUDIT_TOKEN)
+  audit:
+    enabled: false
+    endpoint: ""
+  
+  # POST a small scan-completed event (repo, ref, duration, counts) after
+  # every scan, e.g. to measure adoption (token: SECRETLINT_EVENTS_TOKEN)
+  events:
+    url: ""
+  
+  # Opt-in anonymous usage counts (version, OS, scan mode, duration, files,
+  # findings per rule) for internal rollouts; no repository, path or user is
+  # sent. Builds made with -tags notelemetry never send anything.
+  telemetry:
+    enabled: false
+    endpoint: ""
+  
+  # Unpack zip/jar/tar/gz files in memory and scan their text entries;
+  # findings are reported as archive.zip!path/inside
+  archives:
+    enabled: false
+    max_size_mb: 50
+    max_depth: 2
+  
+  # Flag committed credential files by name (id_rsa, *.pem, credentials.json,
+  # .npmrc, .netrc, ...): error blocks, warning only reports, off disables
+  filenames:
+    severity: error
+  
+  # Symlinks 'scan --all' reads: repo follows links that stay inside the
+  # repository, never reads none, always reads any. Links to credential files
+  # such as ~/.aws/credentials are flagged by filenames.severity either way;
+  # sockets, pipes and device files are never read.
+  symlinks:
+    follow: repo
+  
+  # How secrets are hidden in output and reports: partial shows at most
+  # 'reveal' characters and never more than a quarter of a secret, full
+  # hides it entirely, hash shows a short SHA-256. 'scan --no-mask' shows
+  # secrets in full on the terminal only, for trusted local use.
+  masking:
+    strategy: partial
+    reveal: 8
+  
+  # Print at most this many findings as text, so a directory of fixtures
+  # can't flood the hook output with thousands of lines; 0 prints all.
+  # JSON reports always list every finding. Override with --max-findings.
+  max_findings: 0
+  
+  # Reference mode: secret manager references (op://vault/item/field,
+  # vault:path#key) are the approved way to name a secret. When enabled,
+  # credential-looking names set to literals in source files are reported
+  # as LITERAL_SECRET warnings suggesting a reference.
+  references:
+    enabled: false
+  
+  # Files tracked with Git LFS are committed as pointers, which are never
+  # scanned. When enabled, the object behind a pointer is read from the local
+  # LFS store (or downloaded with 'git lfs smudge') and scanned if it is text
+  # and at most max_size_mb.
+  lfs:
+    enabled: false
+    max_size_mb: 10
+  
+  # Once a week, look up the latest release and print a one-line hint when
+  # it is newer than this binary ('secretlint self-update' installs it).
+  # api_url and repo point at a GitHub Enterprise mirror of the releases.
+  update:
+    check: false
+    api_url: ""
+    repo: ""
+  
+  # Forbid all network access (remote extends:, events, audit shipping,
+  # recheck, tracker sync, image pulls, updates); commands needing it fail instead.
+  # Same as passing --offline or setting SECRETLINT_OFFLINE=1
+  offline: false
+
+# Named profiles, selected with --profile <name> (or SECRETLINT_PROFILE);
+# git hooks use "hook" when it is defined. fail_on sets the lowest severity
+# that fails a scan: error (default), warning, or never (report only).
+# report_only makes single rules warnings under a profile, until the end of
+# report_only_until if set
+# profiles:
+#   hook:
+#     report_only: [NEW_RULE]
+#     report_only_until: "2026-11-01"
+#   ci:
+#     fail_on: warning
+#     format: json
+#   audit:
+#     packs: [pii]
 `
 
-	if err := writeFileIfNotExists(".secretlintrc.yml", configContent); err != nil {
+	// A .secretlintrc.yml would shadow the config shared with the npm secretlint
+	if _, err := os.Stat(config.JSConfigFile); err == nil {
+		fmt.Printf("ℹ️  Using %s shared with the npm secretlint; not creating .secretlintrc.yml\n", config.JSConfigFile)
+	} else if err := writeFileIfNotExists(".secretlintrc.yml", answers.tailorConfig(configContent)); err != nil {
 		return err
 	}
 
@@ -170,8 +442,6 @@ LICENSE*
 package.json
 package-lock.json
 yarn.lock
-Dockerfile
-docker-compose.yml
 
 # IDE and editor files
 .vscode/
@@ -203,13 +473,12 @@ Thumbs.db
 	return nil
 }
 
-func installPreCommitHook(binaryPath string) error {
-	hookPath := ".git/hooks/pre-commit"
-	configPath := ".git/hooks/secretlint-config"
+func installPreCommitHook(hooksDir, binaryPath string) error {
+	hookPath := filepath.Join(hooksDir, "pre-commit")
+	configPath := filepath.Join(hooksDir, "secretlint-config")
 	
 	// Create the hooks directory if it doesn't exist
-	hookDir := filepath.Dir(hookPath)
-	if err := os.MkdirAll(hookDir, 0755); err != nil {
+	if err := initJournal.mkdirAll(hooksDir, 0755); err != nil {
 		return fmt.Errorf("failed to create hooks directory: %w", err)
 	}
 	
@@ -218,42 +487,68 @@ func installPreCommitHook(binaryPath string) error {
 		return err
 	}
 	
+	return installHook(hookPath, "pre-commit", getPreCommitHookContent())
+}
+
+func installPrePushHook(hooksDir string) error {
+	return installHook(filepath.Join(hooksDir, "pre-push"), "pre-push", getPrePushHookContent())
+}
+
+// installHook writes a secretlint hook, refreshing our own previous version
+// and backing up any foreign hook that is already installed
+func installHook(hookPath, hookName, content string) error {
+	if err := refuseSymlink(hookPath); err != nil {
+		return err
+	}
+	
 	// Check if hook already exists
 	if _, err := os.Stat(hookPath); err == nil {
-		fmt.Println("⚠️  Pre-commit hook already exists")
+		fmt.Printf("⚠️  A %s hook already exists\n", hookName)
 		
 		// Read existing hook content
-		content, err := os.ReadFile(hookPath)
+		existing, err := os.ReadFile(hookPath)
 		if err != nil {
 			return fmt.Errorf("failed to read existing hook: %w", err)
 		}
 		
-		hookContent := string(content)
+		hookContent := string(existing)
 		
 		// Check for secretlint signature markers
-		hasSecretlintMarker := strings.Contains(hookContent, "# Secretlint pre-commit hook")
-		hasConfigSource := strings.Contains(hookContent, "source .git/hooks/secretlint-config") ||
-			strings.Contains(hookContent, ". .git/hooks/secretlint-config")
+		hasSecretlintMarker := strings.Contains(hookContent, "# Secretlint "+hookName+" hook")
+		hasConfigSource := strings.Contains(hookContent, "hooks/secretlint-config")
 		
 		if hasSecretlintMarker && hasConfigSource {
-			fmt.Println("✅ Secretlint is already integrated in pre-commit hook")
-			fmt.Println("✅ Updated binary path configuration")
+			fmt.Printf("✅ Secretlint is already integrated in %s hook\n", hookName)
+			
+			// Refresh our own hook so existing installs pick up new behavior,
+			// keeping a copy in case it was edited by hand
+			if hookContent != content {
+				fmt.Printf("📝 Backing up the previous %s hook to %s.backup\n", hookName, hookName)
+				if err := initJournal.rename(hookPath, hookPath+".backup"); err != nil {
+					return fmt.Errorf("failed to backup existing hook: %w", err)
+				}
+				if err := initJournal.writeFile(hookPath, []byte(content), 0755); err != nil {
+					return fmt.Errorf("failed to update %s hook: %w", hookName, err)
+				}
+				fmt.Printf("✅ Updated %s hook to the latest version\n", hookName)
+				fmt.Println("💡 If you added your own logic to the hook, merge it back from the backup")
+			}
 			return nil
 		}
 		
-		fmt.Println("📝 Backing up existing pre-commit hook to pre-commit.backup")
-		if err := os.Rename(hookPath, hookPath+".backup"); err != nil {
+		fmt.Printf("📝 Backing up existing %s hook to %s.backup\n", hookName, hookName)
+		if err := initJournal.rename(hookPath, hookPath+".backup"); err != nil {
 			return fmt.Errorf("failed to backup existing hook: %w", err)
 		}
 		fmt.Println("💡 You can merge your custom hook logic with the new secretlint hook if needed")
 	}
 	
-	// Write the pre-commit hook
-	if err := os.WriteFile(hookPath, []byte(getPreCommitHookContent()), 0755); err != nil {
-		return fmt.Errorf("failed to write pre-commit hook: %w", err)
+	// Write the hook
+	if err := initJournal.writeFile(hookPath, []byte(content), 0755); err != nil {
+		return fmt.Errorf("failed to write %s hook: %w", hookName, err)
 	}
 	
-	fmt.Println("✅ Created pre-commit hook")
+	fmt.Printf("✅ Created %s hook\n", hookName)
 	return nil
 }
 
@@ -265,7 +560,8 @@ func writeSecretlintConfig(configPath, binaryPath string) error {
 export SECRETLINT_BINARY="%s"
 `, binaryPath)
 
-	if err := os.WriteFile(configPath, []byte(configContent), 0755); err != nil {
+	// Sourced by the hooks, not run, so it needn't be executable
+	if err := initJournal.writeFile(configPath, []byte(configContent), 0644); err != nil {
 		return fmt.Errorf("failed to write secretlint config: %w", err)
 	}
 	
@@ -278,8 +574,8 @@ func writeFileIfNotExists(filename, content string) error {
 		return nil
 	}
 	
-	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write %s: %w", filename, err)
+	if err := initJournal.writeFile(filename, []byte(content), 0644); err != nil {
+		return err
 	}
 	
 	fmt.Printf("✅ Created %s\n", filename)
@@ -293,17 +589,37 @@ func getPreCommitHookContent() string {
 # Automatically scans staged changes for secrets
 #
 
-# Colors for output
-RED='\033[0;31m'
-GREEN='\033[0;32m'
-YELLOW='\033[1;33m'
-NC='\033[0m' # No Color
+# Colors and emoji only on a terminal; SECRETLINT_PLAIN=1 or 0 forces plain text or not
+FANCY=""
+case "$SECRETLINT_PLAIN" in
+    0) FANCY=1 ;;
+    "") [ -t 1 ] && FANCY=1 ;;
+esac
+if [ -n "$FANCY" ]; then
+    RED='\033[0;31m'
+    GREEN='\033[0;32m'
+    YELLOW='\033[1;33m'
+    NC='\033[0m' # No Color
+    SCANNING="🔍 "
+    PASSED="✅ "
+    FAILED="❌ "
+else
+    RED=''
+    GREEN=''
+    YELLOW=''
+    NC=''
+    SCANNING=""
+    PASSED="OK: "
+    FAILED="ERROR: "
+fi
 
-echo "🔍 Scanning staged changes for secrets..."
+echo "${SCANNING}Scanning staged changes for secrets..."
 
-# Load secretlint configuration (binary path)
-if [ -f ".git/hooks/secretlint-config" ]; then
-    source .git/hooks/secretlint-config
+# Load secretlint configuration (binary path); hooks live in the main .git
+# directory, also for commits from a linked worktree
+SECRETLINT_CONFIG="$(git rev-parse --git-common-dir)/hooks/secretlint-config"
+if [ -f "$SECRETLINT_CONFIG" ]; then
+    source "$SECRETLINT_CONFIG"
 fi
 
 # Find secretlint binary using stored path first, then fallback
@@ -315,20 +631,35 @@ elif command -v secretlint >/dev/null 2>&1; then
 elif [ -f "./secretlint" ]; then
     SECRETLINT="./secretlint"
 else
-    echo "${RED}❌ secretlint binary not found${NC}"
+    echo "${RED}${FAILED}secretlint binary not found${NC}"
     echo "Stored path: $SECRETLINT_BINARY"
     echo "Please run 'secretlint init' again or build the binary:"
     echo "  go build -o secretlint cmd/secretlint/main.go"
+    # Without the binary only the environment can choose to fail open
+    if [ "$SECRETLINT_ON_ERROR" = "allow" ]; then
+        echo "${YELLOW}Committing unchecked (SECRETLINT_ON_ERROR=allow)${NC}"
+        exit 0
+    fi
     exit 1
 fi
 
-# Run secretlint scan
-$SECRETLINT scan
+# Run secretlint scan (SECRETLINT_PARTIAL=1 commits only the files without secrets)
+if [ -n "$SECRETLINT_PARTIAL" ]; then
+    $SECRETLINT scan --hook --partial
+else
+    $SECRETLINT scan --hook
+fi
+STATUS=$?
 
-# Check exit code
-if [ $? -ne 0 ]; then
+# Check exit code: 1 means secrets were found, 2 that secretlint failed
+if [ $STATUS -eq 2 ]; then
+    echo ""
+    echo "${RED}${FAILED}Commit blocked because secretlint failed (settings.hook.on_error: block)${NC}"
+    echo "Fix the error above, or commit unchecked with SECRETLINT_ON_ERROR=allow"
+    exit 1
+elif [ $STATUS -ne 0 ]; then
     echo ""
-    echo "${RED}❌ Commit blocked due to secrets detected${NC}"
+    echo "${RED}${FAILED}Commit blocked due to secrets detected${NC}"
     echo ""
     echo "To bypass this check (NOT recommended):"
     echo "  git commit --no-verify -m 'your message'"
@@ -339,8 +670,60 @@ if [ $? -ne 0 ]; then
     echo "  3. Remove secrets from the code"
     exit 1
 else
-    echo "${GREEN}✅ No secrets detected${NC}"
+    echo "${GREEN}${PASSED}Secretlint check passed${NC}"
     exit 0
 fi
 `
-}
\ No newline at end of file
+}
+
+func getPrePushHookContent() string {
+	return `#!/bin/sh
+#
+# Secretlint pre-push hook
+# Scans the commits being pushed for secrets
+#
+
+# Colors and emoji only on a terminal; SECRETLINT_PLAIN=1 or 0 forces plain text or not
+FANCY=""
+case "$SECRETLINT_PLAIN" in
+    0) FANCY=1 ;;
+    "") [ -t 1 ] && FANCY=1 ;;
+esac
+if [ -n "$FANCY" ]; then
+    RED='\033[0;31m'
+    NC='\033[0m' # No Color
+    FAILED="❌ "
+else
+    RED=''
+    NC=''
+    FAILED="ERROR: "
+fi
+
+# Load secretlint configuration (binary path) from the main .git directory
+SECRETLINT_CONFIG="$(git rev-parse --git-common-dir)/hooks/secretlint-config"
+if [ -f "$SECRETLINT_CONFIG" ]; then
+    . "$SECRETLINT_CONFIG"
+fi
+
+SECRETLINT=""
+if [ -n "$SECRETLINT_BINARY" ] && [ -f "$SECRETLINT_BINARY" ]; then
+    SECRETLINT="$SECRETLINT_BINARY"
+elif command -v secretlint >/dev/null 2>&1; then
+    SECRETLINT="secretlint"
+elif [ -f "./secretlint" ]; then
+    SECRETLINT="./secretlint"
+else
+    echo "${RED}${FAILED}secretlint binary not found${NC}"
+    echo "Please run 'secretlint init' again"
+    # Without the binary only the environment can choose to fail open
+    if [ "$SECRETLINT_ON_ERROR" = "allow" ]; then
+        echo "Pushing unchecked (SECRETLINT_ON_ERROR=allow)"
+        exit 0
+    fi
+    exit 1
+fi
+
+# git passes the refs being pushed on stdin
+$SECRETLINT scan --pre-push "$@"
+`
+}
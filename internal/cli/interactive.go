@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"secretlint/internal/scanner"
+)
+
+// triageFindings walks through each finding and lets the user resolve it.
+// It returns the findings that are still unresolved and should block the commit.
+func triageFindings(findings []scanner.Finding, secretScanner *scanner.SecretScanner, differ *scanner.GitDiffer) ([]scanner.Finding, error) {
+	// Git hooks don't get the terminal on stdin, so talk to the tty directly
+	input := io.Reader(os.Stdin)
+	if tty, err := os.Open("/dev/tty"); err == nil {
+		defer tty.Close()
+		input = tty
+	}
+	reader := bufio.NewReader(input)
+
+	baseline := secretScanner.GetBaseline()
+	baselineChanged := false
+	unstaged := make(map[string]bool)
+	var unresolved []scanner.Finding
+
+	fmt.Printf("\n🧭 Triaging %d finding(s)\n", len(findings))
+
+	for i, finding := range findings {
+		if unstaged[finding.FilePath] {
+			continue
+		}
+
+		fmt.Printf("\n[%d/%d] %s in %s\n", i+1, len(findings), finding.RuleID, finding.Location())
+		fmt.Printf("Snippet  : %s\n", finding.DisplaySecret())
+		fmt.Printf("Advice   : %s\n", finding.Advice)
+
+		mandatory := secretScanner.IsMandatory(finding.RuleID)
+		resolved, quit, err := triageFinding(reader, finding, mandatory, differ, baseline, unstaged, &baselineChanged)
+		if err != nil {
+			return nil, err
+		}
+		if quit {
+			for _, rest := range findings[i:] {
+				if !unstaged[rest.FilePath] {
+					unresolved = append(unresolved, rest)
+				}
+			}
+			break
+		}
+		if !resolved {
+			unresolved = append(unresolved, finding)
+		}
+	}
+
+	if baselineChanged {
+		if err := baseline.Save(scanner.DefaultBaselineFile); err != nil {
+			return nil, err
+		}
+		if err := differ.StageFiles(scanner.DefaultBaselineFile); err != nil {
+			return nil, err
+		}
+		fmt.Printf("\n📒 Updated and staged %s\n", scanner.DefaultBaselineFile)
+	}
+
+	return unresolved, nil
+}
+
+// triageFinding prompts until the user picks an action for a single finding
+func triageFinding(reader *bufio.Reader, finding scanner.Finding, mandatory bool, differ *scanner.GitDiffer, baseline *scanner.Baseline, unstaged map[string]bool, baselineChanged *bool) (resolved bool, quit bool, err error) {
+	for {
+		answer, ok := prompt(reader, "Action: [u]nstage file, [a]llow inline, [b]aseline, [o]pen in editor, [s]kip, [q]uit > ")
+		if !ok {
+			return false, true, nil
+		}
+
+		switch strings.ToLower(answer) {
+		case "u", "unstage":
+			if err := differ.UnstageFiles(finding.FilePath); err != nil {
+				return false, false, err
+			}
+			unstaged[finding.FilePath] = true
+			fmt.Printf("🚫 Unstaged %s\n", finding.FilePath)
+			return true, false, nil
+
+		case "a", "allow":
+			if mandatory {
+				fmt.Printf("⚠️  %s is mandatory under org policy and can't be suppressed inline\n", finding.RuleID)
+				continue
+			}
+			added, err := addInlineSuppression(finding, differ)
+			if err != nil {
+				return false, false, err
+			}
+			return added, false, nil
+
+		case "b", "baseline":
+			reason, ok := prompt(reader, "Reason for accepting this finding: ")
+			if !ok {
+				return false, true, nil
+			}
+			if reason == "" {
+				fmt.Println("⚠️  A reason is required to baseline a finding")
+				continue
+			}
+			baseline.Add(finding, reason)
+			*baselineChanged = true
+			fmt.Printf("📒 Added %s to baseline\n", finding.Fingerprint())
+			return true, false, nil
+
+		case "o", "open":
+			if err := openInEditor(finding.FilePath, finding.LineNum); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+			}
+			fmt.Println("💡 Re-stage the file after editing, then run the scan again")
+
+		case "s", "skip":
+			return false, false, nil
+
+		case "q", "quit":
+			return false, true, nil
+
+		default:
+			fmt.Println("⚠️  Unknown action")
+		}
+	}
+}
+
+// prompt prints a question and reads one trimmed line; ok is false on EOF
+func prompt(reader *bufio.Reader, question string) (string, bool) {
+	fmt.Print(question)
+	answer, err := reader.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return "", false
+	}
+	return strings.TrimSpace(answer), true
+}
+
+// addInlineSuppression appends an allow comment to the offending line and
+// restages the file so the suppression is part of the commit
+func addInlineSuppression(finding scanner.Finding, differ *scanner.GitDiffer) (bool, error) {
+	comment := scanner.AllowComment(finding.FilePath, finding.RuleID)
+	if comment == "" {
+		fmt.Printf("⚠️  %s doesn't support comments - use the baseline instead\n", filepath.Base(finding.FilePath))
+		return false, nil
+	}
+
+	dirty, err := differ.HasUnstagedChanges(finding.FilePath)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(finding.FilePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat %s: %w", finding.FilePath, err)
+	}
+	data, err := os.ReadFile(finding.FilePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", finding.FilePath, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	idx := finding.LineNum - 1
+	if idx < 0 || idx >= len(lines) || !strings.Contains(lines[idx], finding.Match) {
+		fmt.Printf("⚠️  %s:%d no longer matches the staged content\n", finding.FilePath, finding.LineNum)
+		return false, nil
+	}
+
+	// Keep Windows line endings intact
+	line := strings.TrimSuffix(lines[idx], "\r")
+	lines[idx] = line + " " + comment + lines[idx][len(line):]
+
+	if err := os.WriteFile(finding.FilePath, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", finding.FilePath, err)
+	}
+	fmt.Printf("✅ Added '%s' to %s:%d\n", comment, finding.FilePath, finding.LineNum)
+
+	if dirty {
+		fmt.Printf("💡 %s has other unstaged changes - stage the comment with 'git add -p'\n", finding.FilePath)
+		return false, nil
+	}
+	if err := differ.StageFiles(finding.FilePath); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// openInEditor opens the file at the given line using $VISUAL or $EDITOR
+func openInEditor(filePath string, lineNum int) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	parts := strings.Fields(editor)
+	line := strconv.Itoa(lineNum)
+
+	// Editors disagree on how to jump to a line
+	var args []string
+	switch filepath.Base(parts[0]) {
+	case "code", "code-insiders", "codium":
+		args = []string{"-g", filePath + ":" + line}
+	case "subl", "zed":
+		args = []string{filePath + ":" + line}
+	default:
+		args = []string{"+" + line, filePath}
+	}
+
+	cmd := exec.Command(parts[0], append(parts[1:], args...)...)
+	if tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0); err == nil {
+		defer tty.Close()
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	} else {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %s: %w", parts[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// initJournal records what 'secretlint init' changed, so a failed or
+// interrupted run can put the repository back as it was
+var initJournal = &writeJournal{}
+
+// writeJournal makes file changes one at a time and remembers how to undo
+// each of them
+type writeJournal struct {
+	mu   sync.Mutex
+	undo []func() error
+}
+
+// writeFile replaces path with data through a temporary file and a rename,
+// so readers (and git running a hook) see the old file or the new one,
+// never half of it. Symlinks are refused rather than followed.
+func (j *writeJournal) writeFile(path string, data []byte, mode os.FileMode) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := refuseSymlink(path); err != nil {
+		return err
+	}
+	undo := func() error { return os.Remove(path) }
+	if info, err := os.Stat(path); err == nil {
+		previous, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		undo = func() error { return writeAtomic(path, previous, info.Mode().Perm()) }
+	}
+	if err := writeAtomic(path, data, mode); err != nil {
+		return err
+	}
+	j.undo = append(j.undo, undo)
+	return nil
+}
+
+// mkdirAll creates dir and its missing parents; rolling back removes the
+// ones it created if they are still empty
+func (j *writeJournal) mkdirAll(dir string, mode os.FileMode) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var missing []string
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil || filepath.Dir(d) == d {
+			break
+		}
+		missing = append(missing, d)
+	}
+	if err := os.MkdirAll(dir, mode); err != nil {
+		return err
+	}
+	for _, d := range missing {
+		created := d
+		j.undo = append(j.undo, func() error { return os.Remove(created) })
+	}
+	return nil
+}
+
+// rename moves a file aside, e.g. to back up a foreign hook
+func (j *writeJournal) rename(from, to string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	j.undo = append(j.undo, func() error { return os.Rename(to, from) })
+	return nil
+}
+
+// rollback undoes every recorded change, newest first
+func (j *writeJournal) rollback() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if len(j.undo) == 0 {
+		return
+	}
+	fmt.Println("⚠️  Rolling back the changes made by this init")
+	for i := len(j.undo) - 1; i >= 0; i-- {
+		if err := j.undo[i](); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not roll back: %v\n", err)
+		}
+	}
+	j.undo = nil
+}
+
+// rollbackOnInterrupt undoes the journal's changes and exits when the user
+// interrupts; the returned function stops watching
+func rollbackOnInterrupt(j *writeJournal) func() {
+	interrupted := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-interrupted:
+			fmt.Println()
+			j.rollback()
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(interrupted)
+		close(done)
+	}
+}
+
+// refuseSymlink fails when path is a symlink: writing through one could
+// install a hook, or overwrite a file, somewhere else entirely
+func refuseSymlink(path string) error {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("refusing to write %s: it is a symlink", path)
+	}
+	return nil
+}
+
+// writeAtomic writes data to a temporary file next to path, sets its mode
+// and renames it over path
+func writeAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	// Chmod rather than relying on CreateTemp's 0600 and the umask, so hooks
+	// are always executable
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("failed to set the mode of %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"secretlint/internal/migrate"
+	"secretlint/internal/scanner"
+)
+
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	target := flags.String("to", "", "Secret manager to migrate to: "+strings.Join(migrate.Targets, ", "))
+	fingerprints := flags.String("fingerprint", "", "Comma-separated fingerprints of the findings to migrate (default: all)")
+	rules := flags.String("rule", "", "Comma-separated rule IDs of the findings to migrate (default: all)")
+	prefix := flags.String("prefix", "", "Vault path, Secrets Manager name prefix or SOPS file (default derived from the repo name)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !containsString(migrate.Targets, *target) {
+		return fmt.Errorf("usage: secretlint migrate --to %s [--fingerprint fp,...] [--rule ID,...]", strings.Join(migrate.Targets, "|"))
+	}
+
+	differ, findings, err := collectStagedFindings()
+	if err != nil {
+		return err
+	}
+
+	selected := selectFindings(findings, splitList(*fingerprints), splitList(*rules))
+	if len(selected) == 0 {
+		fmt.Fprintln(os.Stderr, "✅ No matching secrets in staged changes")
+		return nil
+	}
+
+	if *prefix == "" {
+		repoName := "app"
+		if root, err := differ.RepoRoot(); err == nil {
+			repoName = filepath.Base(root)
+		}
+		switch *target {
+		case "vault":
+			*prefix = "secret/" + repoName
+		case "aws-sm":
+			*prefix = repoName
+		case "sops":
+			*prefix = "secrets.enc.yaml"
+		}
+	}
+
+	plan, err := migrate.Plan(*target, *prefix, migrate.Collect(selected))
+	if err != nil {
+		return err
+	}
+
+	// Keep stdout pipeable into a script; the plan contains plaintext values
+	fmt.Fprintln(os.Stderr, "⚠️  The migration plan contains plaintext secrets - run it locally and don't share it")
+	fmt.Print(plan)
+	return nil
+}
+
+// selectFindings filters findings by fingerprint and rule ID; empty filters match everything
+func selectFindings(findings []scanner.Finding, fingerprints, ruleIDs []string) []scanner.Finding {
+	var selected []scanner.Finding
+	for _, finding := range findings {
+		if len(fingerprints) > 0 && !containsString(fingerprints, finding.Fingerprint()) {
+			continue
+		}
+		if len(ruleIDs) > 0 && !containsString(ruleIDs, finding.RuleID) {
+			continue
+		}
+		selected = append(selected, finding)
+	}
+	return selected
+}
+
+// splitList parses a comma-separated flag value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
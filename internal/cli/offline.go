@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"secretlint/internal/config"
+)
+
+// takeOfflineFlag removes --offline from the arguments, wherever it appears,
+// and turns on offline mode for this process
+func takeOfflineFlag() error {
+	args := os.Args[:1]
+	found := false
+	for _, arg := range os.Args[1:] {
+		if arg == "--offline" {
+			found = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
+	if !found {
+		return nil
+	}
+	return os.Setenv(config.OfflineEnv, "1")
+}
+
+// requireOnline fails loudly when a feature needs the network but offline
+// mode is on, rather than silently skipping it
+func requireOnline(cfg *config.Config, feature string) error {
+	if !cfg.Offline() {
+		return nil
+	}
+	return fmt.Errorf("%s needs network access, which offline mode forbids (--offline, %s or settings.offline)", feature, config.OfflineEnv)
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"secretlint/internal/config"
+)
+
+// OnErrorEnv overrides settings.hook.on_error, and decides alone when the
+// config itself can't be loaded
+const OnErrorEnv = "SECRETLINT_ON_ERROR"
+
+// ExitFailed is the exit status of a hook run blocked because secretlint
+// failed rather than because it found secrets, which exit with 1
+const ExitFailed = 2
+
+// The verdicts of a hook that found secrets. Any other error from a hook
+// scan means secretlint failed, and settings.hook.on_error decides.
+var (
+	errCommitBlocked = errors.New("secrets detected - commit blocked")
+	errPushBlocked   = errors.New("secrets detected - push blocked")
+)
+
+// failedError marks a hook run that secretlint failed to complete
+type failedError struct {
+	err error
+}
+
+func (e *failedError) Error() string { return e.err.Error() }
+
+func (e *failedError) Unwrap() error { return e.err }
+
+// ExitCode returns the exit status for an error returned by Execute
+func ExitCode(err error) int {
+	var failed *failedError
+	if errors.As(err, &failed) {
+		return ExitFailed
+	}
+	return 1
+}
+
+// onErrorPolicy returns block or allow for a hook run; cfg is nil when the
+// config couldn't be loaded
+func onErrorPolicy(cfg *config.Config) string {
+	if policy := os.Getenv(OnErrorEnv); policy == "block" || policy == "allow" {
+		return policy
+	}
+	if cfg != nil && cfg.Settings.Hook.OnError != "" {
+		return cfg.Settings.Hook.OnError
+	}
+	return "block"
+}
+
+// hookFailure applies settings.hook.on_error to the outcome of a hook run.
+// Findings pass through untouched; a panic or any other error blocks the
+// commit or push with ExitFailed, or lets it through with a warning.
+func hookFailure(cfg *config.Config, recovered interface{}, err error) error {
+	if recovered != nil {
+		err = fmt.Errorf("secretlint crashed: %v", recovered)
+	}
+	if err == nil || errors.Is(err, errCommitBlocked) || errors.Is(err, errPushBlocked) {
+		return err
+	}
+	if onErrorPolicy(cfg) == "allow" {
+		fmt.Fprintf(os.Stderr, "⚠️  secretlint failed, so nothing was scanned: %v\n", err)
+		fmt.Fprintln(os.Stderr, "⚠️  Letting this through unchecked (settings.hook.on_error: allow)")
+		return nil
+	}
+	return &failedError{fmt.Errorf("%w\nBlocked because secretlint failed (settings.hook.on_error: block); set %s=allow to let this one through", err, OnErrorEnv)}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"secretlint/internal/archive"
+	"secretlint/internal/owners"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+)
+
+// enrichOwnership attaches CODEOWNERS owners to findings and, when blame is
+// set, the author who last touched each line in the working tree
+func enrichOwnership(differ *scanner.GitDiffer, findings []scanner.Finding, blame bool) {
+	root, err := differ.RepoRoot()
+	if err != nil {
+		return
+	}
+	codeowners, err := owners.Load(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not read CODEOWNERS: %v\n", err)
+		codeowners = &owners.Codeowners{}
+	}
+
+	for i := range findings {
+		// Entries inside an archive belong to whoever owns the archive
+		filePath := archive.Outer(findings[i].FilePath)
+		findings[i].Owners = codeowners.Owners(filePath)
+		if blame && filePath == findings[i].FilePath {
+			if commit, err := differ.Blame("", findings[i].FilePath, findings[i].LineNum); err == nil {
+				findings[i].LastTouchedBy = commit
+			}
+		}
+	}
+}
+
+// blameHeadLocations sets LastTouchedBy on history findings whose secret is
+// still at HEAD, blaming the first place it appears there
+func blameHeadLocations(differ *scanner.GitDiffer, findings []scanner.Finding, headLocations map[string][]string) {
+	cache := make(map[string]*scanner.CommitInfo)
+	for i := range findings {
+		fingerprint := findings[i].Fingerprint()
+		commit, ok := cache[fingerprint]
+		if !ok {
+			if locations := headLocations[fingerprint]; len(locations) > 0 {
+				if filePath, lineNum, ok := splitLocation(locations[0]); ok {
+					commit, _ = differ.Blame("HEAD", filePath, lineNum)
+				}
+			}
+			cache[fingerprint] = commit
+		}
+		findings[i].LastTouchedBy = commit
+	}
+}
+
+// splitLocation parses "file:line"
+func splitLocation(location string) (string, int, bool) {
+	i := strings.LastIndex(location, ":")
+	if i < 0 {
+		return "", 0, false
+	}
+	lineNum, err := strconv.Atoi(location[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return location[:i], lineNum, true
+}
+
+// ownerKey is the routing key for a set of owners
+func ownerKey(findingOwners []string) string {
+	if len(findingOwners) == 0 {
+		return owners.Unowned
+	}
+	return strings.Join(findingOwners, " ")
+}
+
+// sortedOwnerKeys orders owner groups alphabetically, unowned last
+func sortedOwnerKeys(groups map[string]int) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == owners.Unowned) != (keys[j] == owners.Unowned) {
+			return keys[j] == owners.Unowned
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// printFindingsByOwner renders findings under a heading per owning team
+func printFindingsByOwner(findings []scanner.Finding, output printOptions) {
+	output.context.related(findings)
+	groups := report.GroupByFingerprint(findings)
+	shown := limitGroups(groups, output.maxFindings)
+	
+	counts := make(map[string]int)
+	grouped := make(map[string][]scanner.Finding)
+	for _, group := range shown {
+		for _, finding := range group {
+			key := ownerKey(finding.Owners)
+			counts[key]++
+			grouped[key] = append(grouped[key], finding)
+		}
+	}
+	for _, key := range sortedOwnerKeys(counts) {
+		fmt.Printf("👥 %s (%d)\n\n", key, counts[key])
+		printFindings(grouped[key], printOptions{context: output.context})
+	}
+	printTruncation(len(shown), len(groups))
+}
+
+// printOwnership adds ownership lines to a finding's output
+func printOwnership(findingOwners []string, lastTouched *scanner.CommitInfo) {
+	if len(findingOwners) > 0 {
+		fmt.Printf("Owners   : %s\n", strings.Join(findingOwners, " "))
+	}
+	if commit := lastTouched; commit != nil {
+		fmt.Printf("Touched  : %.12s %s <%s> %s\n", commit.SHA, commit.Author, commit.AuthorEmail, commit.Date.Format("2006-01-02"))
+	}
+}
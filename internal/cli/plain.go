@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// PlainEnv selects plain output: 1 always, 0 never. Unset, output is plain
+// wherever it doesn't go to a terminal.
+const PlainEnv = "SECRETLINT_PLAIN"
+
+// plainLabels spell out the emoji that carry meaning; the others are decoration
+// and are dropped
+var plainLabels = map[rune]string{
+	'✅': "OK:",
+	'⛔': "ERROR:",
+	'❌': "ERROR:",
+	'⚠': "WARNING:",
+	'💡': "TIP:",
+	'ℹ': "INFO:",
+	'🔴': "LIVE:",
+	'❔': "UNKNOWN:",
+}
+
+// takePlainFlag removes --plain from the arguments, wherever it appears, and
+// turns on plain output for this process and the scans it starts
+func takePlainFlag() error {
+	args := os.Args[:1]
+	found := false
+	for _, arg := range os.Args[1:] {
+		if arg == "--plain" {
+			found = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
+	if !found {
+		return nil
+	}
+	return os.Setenv(PlainEnv, "1")
+}
+
+// startPlainOutput routes stdout and stderr through a filter that replaces
+// emoji with text labels, for CI logs and screen readers. The returned
+// function flushes the output and must run before the process exits.
+func startPlainOutput() (func(), error) {
+	var stops []func()
+	for _, stream := range []**os.File{&os.Stdout, &os.Stderr} {
+		if !plainOutput(*stream) {
+			continue
+		}
+		stop, err := filterStream(stream)
+		if err != nil {
+			return nil, err
+		}
+		stops = append(stops, stop)
+	}
+	return func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}, nil
+}
+
+// plainOutput decides whether a stream gets plain output
+func plainOutput(stream *os.File) bool {
+	switch os.Getenv(PlainEnv) {
+	case "":
+	case "0":
+		return false
+	default:
+		return true
+	}
+	info, err := stream.Stat()
+	return err != nil || info.Mode()&os.ModeCharDevice == 0
+}
+
+// filterStream replaces *stream with a pipe whose contents are rewritten by
+// plainText and copied to the original file
+func filterStream(stream **os.File) (func(), error) {
+	original := *stream
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	*stream = writer
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		filter := &plainWriter{out: original, lineStart: true}
+		io.Copy(filter, reader)
+		filter.flush()
+	}()
+	return func() {
+		*stream = original
+		writer.Close()
+		<-done
+		reader.Close()
+	}, nil
+}
+
+// plainWriter rewrites emoji at the start of lines, where secretlint puts its
+// status markers; emoji elsewhere belong to file content and are kept
+type plainWriter struct {
+	out       io.Writer
+	lineStart bool
+
+	// pending holds an incomplete UTF-8 sequence split across writes
+	pending []byte
+}
+
+func (w *plainWriter) Write(data []byte) (int, error) {
+	n := len(data)
+	data = append(w.pending, data...)
+	w.pending = nil
+
+	var out bytes.Buffer
+	for len(data) > 0 {
+		if !utf8.FullRune(data) {
+			w.pending = append([]byte(nil), data...)
+			break
+		}
+		r, size := utf8.DecodeRune(data)
+		raw := data[:size]
+		data = data[size:]
+		switch {
+		case r == '\n':
+			w.lineStart = true
+		case r == ' ' || r == '\t':
+		case w.lineStart && isEmoji(r):
+			// Drop the variation selector and the padding after the emoji
+			for len(data) > 0 {
+				next, nextSize := utf8.DecodeRune(data)
+				if next != '\uFE0F' && next != ' ' {
+					break
+				}
+				data = data[nextSize:]
+			}
+			if label, ok := plainLabels[r]; ok {
+				out.WriteString(label + " ")
+			}
+			w.lineStart = false
+			continue
+		default:
+			w.lineStart = false
+		}
+		// Copy the original bytes; content that isn't UTF-8 passes unchanged
+		out.Write(raw)
+	}
+	if _, err := w.out.Write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (w *plainWriter) flush() {
+	w.out.Write(w.pending)
+	w.pending = nil
+}
+
+// isEmoji reports whether r is one of the pictographs or symbols used as
+// status markers
+func isEmoji(r rune) bool {
+	return r == 'ℹ' || r == '\uFE0F' || (r >= 0x2600 && r <= 0x27BF) || (r >= 0x1F300 && r <= 0x1FAFF)
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"secretlint/internal/config"
+	"secretlint/internal/scanner"
+)
+
+func runPolicy(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: secretlint policy <subcommand>\n\nSubcommands:\n  sync    Re-fetch the configs named by extends: and refresh the cache\n  show    Show the effective merged config and where each part came from")
+	}
+
+	switch args[0] {
+	case "sync":
+		return runPolicySync()
+	case "show":
+		return runPolicyShow()
+	default:
+		return fmt.Errorf("unknown policy subcommand: %s", args[0])
+	}
+}
+
+func runPolicySync() error {
+	cfg, err := config.LoadWith(config.DefaultConfigFile, config.LoadOptions{Refresh: true})
+	if err != nil {
+		return err
+	}
+	if len(cfg.Origins) <= 1 {
+		fmt.Printf("ℹ️  %s does not extend another config - nothing to sync\n", config.DefaultConfigFile)
+		return nil
+	}
+	for _, origin := range cfg.Origins[:len(cfg.Origins)-1] {
+		fmt.Printf("✅ %s\n", origin.Source)
+	}
+	printMandatory(cfg)
+	printOverrides(cfg)
+	return nil
+}
+
+func runPolicyShow() error {
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+
+	printOrigins(cfg)
+	printMandatory(cfg)
+	printOverrides(cfg)
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	fmt.Println("\n⚙️  Effective config:")
+	fmt.Print(string(data))
+	return nil
+}
+
+// printOrigins lists the files the config was merged from
+func printOrigins(cfg *config.Config) {
+	fmt.Println("📜 Sources (base first):")
+	if _, err := os.Stat(config.DefaultConfigFile); os.IsNotExist(err) {
+		fmt.Println("  built-in defaults")
+	}
+	for _, origin := range cfg.Origins {
+		status := ""
+		switch {
+		case origin.Stale:
+			status = fmt.Sprintf(" (stale cache from %s, refresh failed)", origin.CachedAt.Format(time.RFC3339))
+		case !origin.CachedAt.IsZero():
+			status = fmt.Sprintf(" (cached %s)", origin.CachedAt.Format(time.RFC3339))
+		}
+		fmt.Printf("  %s%s\n", origin.Source, status)
+	}
+	if cfg.Profile != "" {
+		fmt.Printf("  profile %s\n", cfg.Profile)
+		if len(cfg.ReportOnly) > 0 {
+			fmt.Printf("  report only: %s\n", strings.Join(cfg.ReportOnly, ", "))
+		}
+	}
+}
+
+// printMandatory lists the rules the org policy forbids disabling
+func printMandatory(cfg *config.Config) {
+	if len(cfg.Policy.MandatoryRules) == 0 {
+		return
+	}
+	fmt.Printf("\n🔒 Mandatory rules (from %s):\n  %s\n", cfg.Policy.Source, strings.Join(cfg.Policy.MandatoryRules, "\n  "))
+}
+
+// printOverrides lists the rules whose severity, advice or remediation the
+// org policy changes, flagging IDs that match no rule
+func printOverrides(cfg *config.Config) {
+	if len(cfg.Policy.RuleOverrides) == 0 {
+		return
+	}
+	known := make(map[string]bool)
+	for _, ruleID := range scanner.RuleIDs(cfg) {
+		known[ruleID] = true
+	}
+	var ruleIDs []string
+	for ruleID := range cfg.Policy.RuleOverrides {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	sort.Strings(ruleIDs)
+
+	fmt.Printf("\n📝 Rule overrides (from %s):\n", cfg.Policy.Source)
+	for _, ruleID := range ruleIDs {
+		override := cfg.Policy.RuleOverrides[ruleID]
+		var changes []string
+		if override.Severity != "" {
+			changes = append(changes, "severity "+override.Severity)
+		}
+		if override.Advice != "" {
+			changes = append(changes, "advice")
+		}
+		if override.RevokeURL != "" || override.RotateCommand != "" || len(override.DocLinks) > 0 {
+			changes = append(changes, "remediation")
+		}
+		note := ""
+		if !known[ruleID] {
+			note = " ⚠️  not a rule secretlint knows"
+		}
+		fmt.Printf("  %s: %s%s\n", ruleID, strings.Join(changes, ", "), note)
+	}
+}
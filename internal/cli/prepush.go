@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"secretlint/internal/auditlog"
+	"secretlint/internal/config"
+	"secretlint/internal/scanner"
+)
+
+// zeroSHA is what git sends for refs that don't exist on one side of a push
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// pushUpdate is one line of the pre-push hook's stdin
+type pushUpdate struct {
+	localRef  string
+	localSHA  string
+	remoteRef string
+	remoteSHA string
+}
+
+// scanPrePush scans every commit that a push would publish
+func scanPrePush(cfg *config.Config, input io.Reader, stats *scanStats) error {
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	updates, err := parsePushUpdates(input)
+	if err != nil {
+		return err
+	}
+
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+	blocked := false
+
+	for _, update := range updates {
+		// Deleting a remote branch publishes nothing
+		if update.localSHA == zeroSHA {
+			continue
+		}
+
+		revs := []string{update.remoteSHA + ".." + update.localSHA}
+		if update.remoteSHA == zeroSHA {
+			revs = []string{update.localSHA, "--not", "--remotes"}
+		}
+
+		lines, err := differ.GetHistoryChanges(revs...)
+		if err != nil {
+			return err
+		}
+		findings := applyRegoPolicy(cfg, differ, "pre-push", secretScanner.ScanLines(lines))
+		recordScan(cfg, differ, "pre-push", lines, findings)
+		stats.files += countFiles(lines)
+		stats.addFindings(findings)
+		
+		entry := auditlog.Entry{
+			Hook:     "pre-push",
+			Branch:   strings.TrimPrefix(update.localRef, "refs/heads/"),
+			Head:     update.localSHA,
+			Ref:      update.remoteRef,
+			Files:    countFiles(lines),
+			Decision: auditlog.DecisionPassed,
+		}
+		if len(scanner.BlockingFindings(findings)) == 0 {
+			auditHook(cfg, differ, entry, findings)
+			if len(findings) > 0 {
+				fmt.Printf("\n⚠️  %d warning(s) in commits pushed to %s (not blocking):\n\n", len(findings), update.remoteRef)
+				printFindings(findings, printOptions{maxFindings: cfg.Settings.MaxFindings, summary: true})
+			}
+			continue
+		}
+		blocked = true
+		
+		// Committed secrets got past the pre-commit hook, so it was bypassed
+		entry.Decision = auditlog.DecisionBlocked
+		entry.Bypassed = true
+		for _, finding := range findings {
+			entry.BypassCommits = appendUnique(entry.BypassCommits, finding.Commit.SHA)
+		}
+		auditHook(cfg, differ, entry, findings)
+
+		fmt.Printf("\n⛔ %d secret(s) detected in commits pushed to %s:\n\n", len(findings), update.remoteRef)
+		printFindings(findings, printOptions{maxFindings: cfg.Settings.MaxFindings, summary: true})
+
+		if cfg.Settings.Hook.Quarantine && strings.HasPrefix(update.localRef, "refs/heads/") {
+			if err := offerQuarantine(differ, update, revs, findings); err != nil {
+				fmt.Printf("⚠️  Quarantine failed: %v\n", err)
+			}
+		}
+	}
+
+	if blocked {
+		fmt.Println("Push aborted.")
+		return errPushBlocked
+	}
+
+	fmt.Println("✅ No secrets detected in pushed commits")
+	return nil
+}
+
+// parsePushUpdates reads "<local ref> <local sha> <remote ref> <remote sha>" lines
+func parsePushUpdates(input io.Reader) ([]pushUpdate, error) {
+	var updates []pushUpdate
+	lineScanner := bufio.NewScanner(input)
+	for lineScanner.Scan() {
+		fields := strings.Fields(lineScanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		updates = append(updates, pushUpdate{
+			localRef:  fields[0],
+			localSHA:  fields[1],
+			remoteRef: fields[2],
+			remoteSHA: fields[3],
+		})
+	}
+	if err := lineScanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pushed refs: %w", err)
+	}
+	return updates, nil
+}
+
+// offerQuarantine moves the branch back to the last commit before the first
+// offending one, keeping all work on a local quarantine branch
+func offerQuarantine(differ *scanner.GitDiffer, update pushUpdate, revs []string, findings []scanner.Finding) error {
+	// stdin carries the pushed refs, so ask on the terminal
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return nil
+	}
+	defer tty.Close()
+
+	offending := make(map[string]bool)
+	for _, finding := range findings {
+		offending[finding.Commit.SHA] = true
+	}
+
+	commits, err := differ.RevList(append([]string{"--reverse", "--topo-order"}, revs...)...)
+	if err != nil {
+		return err
+	}
+	first := ""
+	for _, sha := range commits {
+		if offending[sha] {
+			first = sha
+			break
+		}
+	}
+	if first == "" {
+		return nil
+	}
+
+	parents, err := differ.RevList("-n", "1", first+"^")
+	if err != nil || len(parents) == 0 {
+		return fmt.Errorf("commit %.12s has no parent to reset the branch to", first)
+	}
+	cleanSHA := parents[0]
+
+	branch := strings.TrimPrefix(update.localRef, "refs/heads/")
+	quarantine := fmt.Sprintf("secretlint/quarantine/%s-%s", branch, time.Now().Format("20060102-150405"))
+
+	question := fmt.Sprintf("Move commits from %.12s onward to %s and reset %s to %.12s? [y/N] ", first, quarantine, branch, cleanSHA)
+	answer, ok := prompt(bufio.NewReader(tty), question)
+	if !ok || strings.ToLower(answer) != "y" {
+		return nil
+	}
+
+	if err := differ.SetBranch(quarantine, update.localSHA); err != nil {
+		return err
+	}
+	if differ.CurrentBranch() == branch {
+		// Same commit, so the working tree and index are untouched
+		if err := differ.Checkout(quarantine); err != nil {
+			return err
+		}
+	}
+	if err := differ.SetBranch(branch, cleanSHA); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n🔒 Your work is safe on %s\n", quarantine)
+	fmt.Printf("✅ %s now points at %.12s - push again to publish the clean commits\n", branch, cleanSHA)
+	fmt.Println("To remove the secret from the quarantined commits:")
+	fmt.Printf("  git rebase -i %.12s    # edit or drop the offending commits\n", cleanSHA)
+	fmt.Printf("  git branch -f %s %s && git checkout %s\n", branch, quarantine, branch)
+	return nil
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"secretlint/internal/config"
+)
+
+// takeProfileFlag removes --profile <name> (or --profile=<name>) from the
+// arguments, wherever it appears, and selects that profile for this process
+func takeProfileFlag() error {
+	args := os.Args[:1]
+	name := ""
+	rest := os.Args[1:]
+	for i := 0; i < len(rest); i++ {
+		switch arg := rest[i]; {
+		case arg == "--profile":
+			if i+1 == len(rest) {
+				return fmt.Errorf("--profile needs a profile name")
+			}
+			i++
+			name = rest[i]
+		case strings.HasPrefix(arg, "--profile="):
+			name = strings.TrimPrefix(arg, "--profile=")
+		default:
+			args = append(args, arg)
+		}
+	}
+	os.Args = args
+	if name == "" {
+		return nil
+	}
+	return os.Setenv(config.ProfileEnv, name)
+}
+
+// selectHookProfile applies the hook profile to scans run from git hooks,
+// unless a profile was chosen explicitly or the config defines none
+func selectHookProfile(cfg *config.Config) (*config.Config, error) {
+	if os.Getenv(config.ProfileEnv) != "" {
+		return cfg, nil
+	}
+	if _, ok := cfg.Profiles[config.HookProfile]; !ok {
+		return cfg, nil
+	}
+	// Set for the whole process so the scanner loads the same profile
+	if err := os.Setenv(config.ProfileEnv, config.HookProfile); err != nil {
+		return nil, err
+	}
+	return config.Load(config.DefaultConfigFile)
+}
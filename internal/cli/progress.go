@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// progressOff suppresses the progress line, whose timing varies between runs
+var progressOff bool
+
+// progressWidth is the width of the bar; the whole line stays within 100 columns
+const progressWidth = 20
+
+// progress draws a single, self-overwriting status line on stderr during
+// long scans, so they don't look hung. It only draws on a terminal.
+type progress struct {
+	enabled bool
+	unit    string
+	total   int
+	done    int
+	start   time.Time
+	drawn   time.Time
+}
+
+// newProgress starts a progress line counting total units, e.g. files
+func newProgress(unit string, total int) *progress {
+	info, err := os.Stderr.Stat()
+	return &progress{
+		enabled: err == nil && info.Mode()&os.ModeCharDevice != 0 && total > 0 && !progressOff,
+		unit:    unit,
+		total:   total,
+		start:   time.Now(),
+	}
+}
+
+// step counts one unit as done; current names what is being scanned now
+func (p *progress) step(current string) {
+	p.done++
+	if !p.enabled {
+		return
+	}
+	// Redrawing on every file would slow the scan down more than it helps
+	now := time.Now()
+	if now.Sub(p.drawn) < 100*time.Millisecond && p.done < p.total {
+		return
+	}
+	p.drawn = now
+
+	filled := progressWidth * p.done / p.total
+	line := fmt.Sprintf("[%s%s] %3d%% %d/%d %s", strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled), 100*p.done/p.total, p.done, p.total, p.unit)
+	if eta := p.eta(now); eta != "" {
+		line += "  ETA " + eta
+	}
+	if room := 100 - len(line) - 2; room > 10 {
+		line += "  " + shortenPath(current, room)
+	}
+	fmt.Fprintf(os.Stderr, "\r\033[K%s", line)
+}
+
+// eta estimates the time left from the average time per unit so far, once
+// there is enough to go on
+func (p *progress) eta(now time.Time) string {
+	elapsed := now.Sub(p.start)
+	if elapsed < time.Second || p.done == 0 {
+		return ""
+	}
+	left := elapsed / time.Duration(p.done) * time.Duration(p.total-p.done)
+	return left.Round(time.Second).String()
+}
+
+// finish clears the progress line so the report starts on a clean line
+func (p *progress) finish() {
+	if p.enabled && !p.drawn.IsZero() {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
+
+// shortenPath keeps the end of a path, which names the file, within width
+func shortenPath(path string, width int) string {
+	runes := []rune(path)
+	if len(runes) <= width {
+		return path
+	}
+	return "..." + string(runes[len(runes)-width+3:])
+}
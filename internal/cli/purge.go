@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"secretlint/internal/scanner"
+)
+
+// purgeReplacement is what the secret is rewritten to in history
+const purgeReplacement = "***REMOVED-BY-SECRETLINT***"
+
+func runPurge(args []string) error {
+	flags := flag.NewFlagSet("purge", flag.ContinueOnError)
+	fingerprint := flags.String("fingerprint", "", "Fingerprint of the secret to remove from history")
+	run := flags.Bool("run", false, "Run git filter-repo after confirmation instead of only printing the commands")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *fingerprint == "" {
+		return fmt.Errorf("usage: secretlint purge --fingerprint <fp> [--run]")
+	}
+
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	fmt.Printf("🔍 Searching history for secret %s...\n", *fingerprint)
+
+	lines, err := differ.GetHistoryChanges()
+	if err != nil {
+		return err
+	}
+
+	// Scan every historical line, including ignored paths and baselined
+	// findings: purging is about what is in the repo, not what blocks commits
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+	var matches []scanner.Finding
+	for _, line := range lines {
+		for _, finding := range secretScanner.ScanLine(line.FilePath, line.LineNum, line.Content) {
+			if finding.Fingerprint() == *fingerprint {
+				finding.Commit = line.Commit
+				matches = append(matches, finding)
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("no commit in history contains a secret with fingerprint %s", *fingerprint)
+	}
+
+	fmt.Printf("\n⛔ Secret %s (%s) was introduced in %d place(s):\n\n", *fingerprint, matches[0].RuleID, len(matches))
+	seenCommits := make(map[string]bool)
+	for _, finding := range matches {
+		commit := finding.Commit
+		fmt.Printf("Commit   : %s (%s, %s)\n", commit.SHA[:12], commit.Author, commit.Date.Format("2006-01-02"))
+		fmt.Printf("Subject  : %s\n", commit.Subject)
+		fmt.Printf("File     : %s:%d\n", finding.FilePath, finding.LineNum)
+		if !seenCommits[commit.SHA] {
+			seenCommits[commit.SHA] = true
+			if refs, err := differ.RefsContaining(commit.SHA); err == nil && len(refs) > 0 {
+				fmt.Printf("Refs     : %s\n", strings.Join(refs, ", "))
+			}
+		}
+		fmt.Println()
+	}
+
+	// The replacement file holds the plaintext secret, so keep it inside .git
+	gitDir, err := differ.GitDir()
+	if err != nil {
+		return err
+	}
+	replacePath := filepath.Join(gitDir, "secretlint", "purge-"+*fingerprint+".txt")
+	if err := os.MkdirAll(filepath.Dir(replacePath), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(replacePath), err)
+	}
+	replaceContent := matches[0].Secret + "==>" + purgeReplacement + "\n"
+	if err := os.WriteFile(replacePath, []byte(replaceContent), 0600); err != nil {
+		return fmt.Errorf("failed to write replacement file: %w", err)
+	}
+
+	fmt.Println("🧹 Rewrite history with one of:")
+	fmt.Println("")
+	fmt.Println("  # git-filter-repo (recommended, https://github.com/newren/git-filter-repo)")
+	fmt.Printf("  git filter-repo --replace-text %s --force\n", replacePath)
+	fmt.Println("")
+	fmt.Println("  # BFG Repo-Cleaner (https://rtyley.github.io/bfg-repo-cleaner/)")
+	fmt.Printf("  bfg --replace-text %s\n", replacePath)
+	fmt.Println("  git reflog expire --expire=now --all && git gc --prune=now --aggressive")
+	fmt.Println("")
+	fmt.Println("  # Then publish the rewritten history")
+	fmt.Println("  git push --force --all && git push --force --tags")
+	fmt.Println("")
+	fmt.Printf("⚠️  %s contains the plaintext secret - delete it when done\n", replacePath)
+
+	printRotationChecklist(matches[0])
+
+	if !*run {
+		return nil
+	}
+	return runFilterRepo(replacePath)
+}
+
+// printRotationChecklist reminds responders that rewriting history is not enough
+func printRotationChecklist(finding scanner.Finding) {
+	fmt.Println("")
+	fmt.Println("🔑 Rotation checklist:")
+	remediation := finding.Remediation
+	if remediation.RevokeURL != "" {
+		fmt.Printf("  [ ] Revoke the exposed %s at %s - assume it is compromised\n", finding.RuleName, remediation.RevokeURL)
+	} else {
+		fmt.Printf("  [ ] Revoke the exposed %s at the provider - assume it is compromised\n", finding.RuleName)
+	}
+	if remediation.RotateCommand != "" {
+		fmt.Printf("  [ ] Issue a replacement credential: %s\n", remediation.RotateCommand)
+		fmt.Println("  [ ] Update every consumer of the old credential")
+	} else {
+		fmt.Println("  [ ] Issue a replacement credential and update every consumer")
+	}
+	fmt.Printf("  [ ] %s\n", finding.Advice)
+	fmt.Println("  [ ] Review the provider's access logs for use since the first leaking commit")
+	fmt.Println("  [ ] Ask collaborators to re-clone after the force push; old clones still hold the secret")
+	fmt.Println("  [ ] Check forks, CI caches and mirrors that may have copied the history")
+	for _, link := range remediation.DocLinks {
+		fmt.Printf("  📚 %s\n", link)
+	}
+}
+
+// runFilterRepo drives git filter-repo after an explicit confirmation
+func runFilterRepo(replacePath string) error {
+	if _, err := exec.LookPath("git-filter-repo"); err != nil {
+		return fmt.Errorf("git filter-repo is not installed (pip install git-filter-repo)")
+	}
+
+	fmt.Println("")
+	fmt.Print("Rewrite ALL history of this repository now? This cannot be undone. [y/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		fmt.Println("Aborted - history left untouched.")
+		return nil
+	}
+
+	cmd := exec.Command("git", "filter-repo", "--replace-text", replacePath, "--force")
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git filter-repo failed: %w", err)
+	}
+
+	if err := os.Remove(replacePath); err != nil {
+		fmt.Printf("⚠️  Failed to remove %s: %v\n", replacePath, err)
+	}
+	fmt.Println("✅ History rewritten - force push and rotate the secret")
+	return nil
+}
@@ -0,0 +1,217 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+	"secretlint/internal/store"
+	"secretlint/internal/verify"
+)
+
+// recheckTarget is a previously detected secret to verify again
+type recheckTarget struct {
+	Fingerprint string        `json:"fingerprint"`
+	RuleID      string        `json:"rule_id"`
+	File        string        `json:"file"`
+	Line        int           `json:"line"`
+	Result      verify.Result `json:"result"`
+
+	secret string
+}
+
+// runRecheck re-verifies earlier findings with their providers so teams can
+// confirm that "rotated" secrets really stopped working
+func runRecheck(args []string) error {
+	flags := flag.NewFlagSet("recheck", flag.ContinueOnError)
+	reportPath := flags.String("report", "", "Recheck the findings of a JSON report instead of the findings database")
+	fingerprints := flags.String("fingerprint", "", "Comma-separated fingerprints to recheck (default: all)")
+	format := flags.String("format", "text", "Output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: text, json)", *format)
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if err := requireOnline(cfg, "recheck"); err != nil {
+		return err
+	}
+
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	var db *store.Store
+	targets := make(map[string]*recheckTarget)
+	if *reportPath != "" {
+		r, err := report.Load(*reportPath)
+		if err != nil {
+			return err
+		}
+		for _, finding := range r.Findings {
+			if _, ok := targets[finding.Fingerprint]; !ok {
+				targets[finding.Fingerprint] = &recheckTarget{Fingerprint: finding.Fingerprint, RuleID: finding.RuleID, File: finding.File, Line: finding.Line}
+			}
+		}
+	} else {
+		db, err = openStore(cfg, differ)
+		if err != nil {
+			return err
+		}
+		for fingerprint, record := range db.Findings {
+			targets[fingerprint] = &recheckTarget{Fingerprint: fingerprint, RuleID: record.RuleID, File: record.File, Line: record.Line}
+		}
+	}
+
+	if *fingerprints != "" {
+		wanted := splitList(*fingerprints)
+		for fingerprint := range targets {
+			if !containsString(wanted, fingerprint) {
+				delete(targets, fingerprint)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(os.Stderr, "📭 No previous findings to recheck (use --report or enable settings.store.enabled)")
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "🔁 Rechecking %d secret(s)...\n", len(targets))
+	if err := recoverSecrets(differ, targets); err != nil {
+		return err
+	}
+
+	ordered := make([]*recheckTarget, 0, len(targets))
+	for _, target := range targets {
+		ordered = append(ordered, target)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].File != ordered[j].File {
+			return ordered[i].File < ordered[j].File
+		}
+		return ordered[i].Fingerprint < ordered[j].Fingerprint
+	})
+
+	// Providers are called in parallel, rate limited and with backoff
+	var requests []verify.Request
+	var recovered []*recheckTarget
+	for _, target := range ordered {
+		if target.secret == "" {
+			target.Result = verify.Result{Status: verify.StatusUnknown, Detail: "secret no longer present in history or working tree"}
+			continue
+		}
+		requests = append(requests, verify.Request{RuleID: target.RuleID, Secret: target.secret})
+		recovered = append(recovered, target)
+	}
+	for i, result := range verify.NewBatch().CheckAll(requests) {
+		recovered[i].Result = result
+	}
+
+	live, unverified := 0, 0
+	now := time.Now().UTC()
+	for _, target := range ordered {
+		switch target.Result.Status {
+		case verify.StatusLive:
+			live++
+		case verify.StatusUnknown, verify.StatusUnsupported:
+			unverified++
+		}
+		if db != nil {
+			db.SetVerification(target.Fingerprint, string(target.Result.Status), now)
+		}
+	}
+	if db != nil && len(db.Scans) > 0 {
+		if err := db.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not record recheck results: %v\n", err)
+		}
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(ordered); err != nil {
+			return fmt.Errorf("failed to write results: %w", err)
+		}
+	} else {
+		for _, target := range ordered {
+			fmt.Printf("%s %s  %-16s %s:%d  %s\n", recheckIcon(target.Result.Status), target.Fingerprint, target.RuleID, target.File, target.Line, describeResult(target.Result))
+		}
+		fmt.Printf("\nVerifiable rules: %s\n", strings.Join(verify.Supported(), ", "))
+	}
+
+	if live > 0 {
+		fmt.Fprintf(os.Stderr, "\n⛔ %d secret(s) are still live - revoke them with the provider\n", live)
+		return fmt.Errorf("%d secret(s) still live", live)
+	}
+	if unverified > 0 {
+		fmt.Fprintf(os.Stderr, "\n⚠️  No live secrets confirmed, but %d could not be verified - check them with the provider\n", unverified)
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, "\n✅ All rechecked secrets are revoked")
+	return nil
+}
+
+// recoverSecrets finds the plaintext of each target by rescanning history and
+// the files they were last seen in; fingerprints alone can't be verified
+func recoverSecrets(differ *scanner.GitDiffer, targets map[string]*recheckTarget) error {
+	lines, err := differ.GetHistoryChanges()
+	if err != nil && differ.HeadSHA() != "" {
+		return err
+	}
+
+	seenFiles := make(map[string]bool)
+	for _, target := range targets {
+		if target.File == "" || seenFiles[target.File] {
+			continue
+		}
+		seenFiles[target.File] = true
+		if fileLines, err := scanner.ReadFileLines(target.File); err == nil {
+			lines = append(lines, fileLines...)
+		}
+	}
+
+	// Ignores and baselines don't apply: accepted secrets may still be live
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		for _, finding := range secretScanner.ScanLine(line.FilePath, line.LineNum, line.Content) {
+			if target, ok := targets[finding.Fingerprint()]; ok && target.secret == "" {
+				target.secret = finding.Secret
+			}
+		}
+	}
+	return nil
+}
+
+func recheckIcon(status verify.Status) string {
+	switch status {
+	case verify.StatusLive:
+		return "🔴"
+	case verify.StatusRevoked:
+		return "✅"
+	default:
+		return "❔"
+	}
+}
+
+func describeResult(result verify.Result) string {
+	if result.Detail == "" {
+		return string(result.Status)
+	}
+	return fmt.Sprintf("%s (%s)", result.Status, result.Detail)
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"secretlint/internal/redact"
+	"secretlint/internal/scanner"
+)
+
+func runRedact(args []string) error {
+	flags := flag.NewFlagSet("redact", flag.ContinueOnError)
+	diffOnly := flags.Bool("diff", false, "Print a patch instead of rewriting files")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+
+	// Explicit paths are always scanned; staged files respect .secretignore
+	paths := flags.Args()
+	if len(paths) == 0 {
+		staged, err := stagedFilePaths()
+		if err != nil {
+			return err
+		}
+		for _, filePath := range staged {
+			if !secretScanner.GetIgnoreChecker().ShouldIgnore(filePath) {
+				paths = append(paths, filePath)
+			}
+		}
+	}
+
+	total := 0
+	for _, filePath := range paths {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		lines, err := scanner.ReadFileLines(filePath)
+		if err != nil {
+			return err
+		}
+
+		var findings []scanner.Finding
+		for _, line := range lines {
+			findings = append(findings, secretScanner.ScanLine(line.FilePath, line.LineNum, line.Content)...)
+		}
+
+		redaction := redact.Redact(filePath, string(data), findings)
+		if redaction.Replacements == 0 {
+			continue
+		}
+		total += redaction.Replacements
+
+		if *diffOnly {
+			fmt.Print(redaction.Patch())
+			continue
+		}
+
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", filePath, err)
+		}
+		if err := os.WriteFile(filePath, []byte(redaction.Content()), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filePath, err)
+		}
+		fmt.Printf("✅ Redacted %d secret(s) in %s\n", redaction.Replacements, filePath)
+	}
+
+	// Keep stdout a clean patch in --diff mode
+	if total == 0 {
+		fmt.Fprintln(os.Stderr, "✅ No secrets to redact")
+	} else if *diffOnly {
+		fmt.Fprintf(os.Stderr, "💡 %d secret(s) masked - apply with 'git apply <patch>'\n", total)
+	}
+
+	return nil
+}
+
+// stagedFilePaths returns the distinct files touched by the staged diff
+func stagedFilePaths() ([]string, error) {
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		return nil, fmt.Errorf("not in a git repository")
+	}
+
+	lines, err := differ.GetStagedChanges()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged changes: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, line := range lines {
+		if !seen[line.FilePath] {
+			seen[line.FilePath] = true
+			paths = append(paths, line.FilePath)
+		}
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
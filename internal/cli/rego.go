@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"secretlint/internal/config"
+	"secretlint/internal/rego"
+	"secretlint/internal/scanner"
+)
+
+// applyRegoPolicy lets the org policy's Rego reclassify and waive findings.
+// A policy that can't be evaluated leaves the findings unchanged, so a
+// broken policy doesn't block every commit.
+func applyRegoPolicy(cfg *config.Config, differ *scanner.GitDiffer, scope string, findings []scanner.Finding) []scanner.Finding {
+	if cfg.Policy.Rego == "" || len(findings) == 0 {
+		return findings
+	}
+
+	input := &rego.Input{
+		Repo:  rego.Repo{Branch: differ.CurrentBranch()},
+		Scope: scope,
+	}
+	if root, err := differ.RepoRoot(); err == nil {
+		input.Repo.Name = filepath.Base(root)
+	}
+	if differ.ConfigValue("remote.origin.url") != "" {
+		input.Repo.Remote = repoIdentity(differ)
+	}
+	for i, finding := range findings {
+		entry := rego.Finding{
+			ID:          strconv.Itoa(i),
+			RuleID:      finding.RuleID,
+			RuleName:    finding.RuleName,
+			Severity:    finding.Severity,
+			File:        finding.FilePath,
+			Line:        finding.LineNum,
+			KeyPath:     finding.KeyPath,
+			Description: finding.Description,
+			Fingerprint: finding.Fingerprint(),
+			Owners:      finding.Owners,
+		}
+		if finding.Commit != nil {
+			entry.Commit = finding.Commit.SHA
+			entry.Author = finding.Commit.AuthorEmail
+		}
+		input.Findings = append(input.Findings, entry)
+	}
+
+	decisions, err := rego.Evaluate(cfg.Policy.Rego, input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Org policy not applied: %v\n", err)
+		return findings
+	}
+
+	var kept []scanner.Finding
+	for i, finding := range findings {
+		id := strconv.Itoa(i)
+		if reason, ok := decisions.Waive[id]; ok {
+			fmt.Fprintf(os.Stderr, "ℹ️  Waived by policy: %s at %s (%s)\n", finding.RuleID, finding.Location(), reason)
+			continue
+		}
+		if severity, ok := decisions.Severity[id]; ok && severity != finding.Severity {
+			finding.Description = fmt.Sprintf("%s (severity %s set by policy)", finding.Description, severity)
+			finding.Severity = severity
+		}
+		kept = append(kept, finding)
+	}
+	return kept
+}
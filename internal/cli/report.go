@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"secretlint/internal/config"
+	"secretlint/internal/report"
+	"secretlint/internal/tracker"
+)
+
+func runReport(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: secretlint report <subcommand>\n\nSubcommands:\n" +
+			"  issues    Create or update tracking issues from a JSON report\n" +
+			"  diff      Compare two JSON reports by fingerprint\n" +
+			"  evidence  Build a signed compliance evidence bundle\n" +
+			"  merge     Merge secretlint, gitleaks and trufflehog JSON reports\n" +
+			"  annotate  Record triage outcomes in a JSON report and the baseline")
+	}
+
+	switch args[0] {
+	case "issues":
+		return runReportIssues(args[1:])
+	case "diff":
+		return runReportDiff(args[1:])
+	case "evidence":
+		return runReportEvidence(args[1:])
+	case "merge":
+		return runReportMerge(args[1:])
+	case "annotate":
+		return runReportAnnotate(args[1:])
+	default:
+		return fmt.Errorf("unknown report subcommand: %s", args[0])
+	}
+}
+
+func runReportIssues(args []string) error {
+	flags := flag.NewFlagSet("report issues", flag.ContinueOnError)
+	github := flags.Bool("github", false, "File issues in GitHub (GITHUB_TOKEN)")
+	gitlab := flags.Bool("gitlab", false, "File issues in GitLab (GITLAB_TOKEN)")
+	jira := flags.Bool("jira", false, "File issues in Jira (JIRA_USER, JIRA_API_TOKEN)")
+	repo := flags.String("repo", "", "GitHub owner/name or GitLab project path/ID (defaults from CI environment)")
+	baseURL := flags.String("url", "", "API base URL (GitHub Enterprise, self-hosted GitLab, or Jira site)")
+	project := flags.String("project", "", "Jira project key")
+	issueType := flags.String("issue-type", "Task", "Jira issue type")
+	dryRun := flags.Bool("dry-run", false, "Show the issues that would be filed without contacting the tracker")
+	owner := flags.String("owner", "", "Only file issues for findings owned by this CODEOWNERS owner (e.g. @org/team)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: secretlint report issues --github|--gitlab|--jira [flags] <findings.json>")
+	}
+
+	r, err := report.Load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *owner != "" {
+		r = tracker.FilterByOwner(r, *owner)
+	}
+	issues := tracker.BuildIssues(r)
+	if len(issues) == 0 {
+		fmt.Println("✅ No findings in report - nothing to file")
+		return nil
+	}
+
+	if *dryRun {
+		for _, issue := range issues {
+			fmt.Printf("📝 %s (%s)\n", issue.Title, issue.Fingerprint)
+		}
+		return nil
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if err := requireOnline(cfg, "report issues"); err != nil {
+		return err
+	}
+
+	var t tracker.Tracker
+	switch {
+	case *github:
+		t, err = newGitHubTracker(*baseURL, *repo)
+	case *gitlab:
+		t, err = newGitLabTracker(*baseURL, *repo)
+	case *jira:
+		t, err = newJiraTracker(*baseURL, *project, *issueType)
+	default:
+		return fmt.Errorf("choose a tracker: --github, --gitlab or --jira")
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		result, err := t.Upsert(issue)
+		if err != nil {
+			return fmt.Errorf("failed to sync issue for %s: %w", issue.Fingerprint, err)
+		}
+		fmt.Printf("✅ %s %s - %s\n", result.Action, result.URL, issue.Title)
+	}
+	return nil
+}
+
+func newGitHubTracker(baseURL, repo string) (tracker.Tracker, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITHUB_TOKEN is not set")
+	}
+	if repo == "" {
+		repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if repo == "" {
+		return nil, fmt.Errorf("--repo owner/name is required")
+	}
+	if baseURL == "" {
+		baseURL = envOr("GITHUB_API_URL", "https://api.github.com")
+	}
+	return tracker.NewGitHub(baseURL, repo, token), nil
+}
+
+func newGitLabTracker(baseURL, project string) (tracker.Tracker, error) {
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITLAB_TOKEN is not set")
+	}
+	if project == "" {
+		project = os.Getenv("CI_PROJECT_ID")
+	}
+	if project == "" {
+		return nil, fmt.Errorf("--repo group/project is required")
+	}
+	if baseURL == "" {
+		baseURL = envOr("CI_SERVER_URL", "https://gitlab.com")
+	}
+	return tracker.NewGitLab(baseURL, project, token), nil
+}
+
+func newJiraTracker(baseURL, project, issueType string) (tracker.Tracker, error) {
+	user, token := os.Getenv("JIRA_USER"), os.Getenv("JIRA_API_TOKEN")
+	if user == "" || token == "" {
+		return nil, fmt.Errorf("JIRA_USER and JIRA_API_TOKEN must be set")
+	}
+	if baseURL == "" {
+		baseURL = os.Getenv("JIRA_URL")
+	}
+	if baseURL == "" || project == "" {
+		return nil, fmt.Errorf("--url and --project are required for Jira")
+	}
+	return tracker.NewJira(baseURL, project, issueType, user, token), nil
+}
+
+// envOr returns the environment variable's value, or fallback when unset
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// runReportDiff compares two reports; it fails when new findings were
+// introduced so CI can gate on "no new secrets" despite a legacy backlog
+func runReportDiff(args []string) error {
+	flags := flag.NewFlagSet("report diff", flag.ContinueOnError)
+	format := flags.String("format", "text", "Output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: text, json)", *format)
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("usage: secretlint report diff [--format text|json] <old.json> <new.json>")
+	}
+
+	old, err := report.Load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	current, err := report.Load(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	diff := report.Compare(old, current)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(diff); err != nil {
+			return fmt.Errorf("failed to write diff: %w", err)
+		}
+	} else {
+		printDiffSection("🆕 Introduced", diff.Introduced)
+		printDiffSection("✅ Resolved", diff.Resolved)
+		printDiffSection("📌 Persisting", diff.Persisting)
+	}
+
+	if len(diff.Introduced) > 0 {
+		return fmt.Errorf("%d new secret(s) introduced", len(diff.Introduced))
+	}
+	return nil
+}
+
+// runReportMerge combines the JSON output of secretlint and other scanners
+// into one deduplicated report, so the same secret is triaged once
+func runReportMerge(args []string) error {
+	flags := flag.NewFlagSet("report merge", flag.ContinueOnError)
+	output := flags.String("output", "", "Write the merged report to a file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < 2 {
+		return fmt.Errorf("usage: secretlint report merge [--output file] <report.json> <report.json>...")
+	}
+
+	var inputs []*report.Input
+	total := 0
+	for _, path := range flags.Args() {
+		input, err := report.LoadInput(path)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, input)
+		total += len(input.Findings)
+	}
+	merged, removed := report.Merge(inputs)
+
+	if *output == "" {
+		return merged.Write(os.Stdout)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *output, err)
+	}
+	defer file.Close()
+	if err := merged.Write(file); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Merged %d finding(s) from %d report(s) into %s: %d unique, %d duplicate(s) removed\n", total, len(inputs), *output, len(merged.Findings), removed)
+	for _, input := range inputs {
+		fmt.Printf("   %-10s %d finding(s)\n", input.Tool, len(input.Findings))
+	}
+	return nil
+}
+
+// printDiffSection lists one group of a report diff
+func printDiffSection(title string, findings []report.Finding) {
+	fmt.Printf("%s (%d)\n", title, len(findings))
+	for _, finding := range findings {
+		fmt.Printf("   %s  %-20s %s:%d  %s\n", finding.Fingerprint, finding.RuleID, finding.File, finding.Line, finding.Snippet)
+	}
+	fmt.Println()
+}
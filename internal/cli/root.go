@@ -1,30 +1,155 @@
 package cli
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/scanner"
+	"secretlint/internal/telemetry"
 )
 
+// Version is the secretlint release, set at build time with
+// -ldflags "-X secretlint/internal/cli.Version=v1.2.3"
+var Version = "dev"
+
 func Execute() error {
+	if err := takeOfflineFlag(); err != nil {
+		return err
+	}
+	if err := takeProfileFlag(); err != nil {
+		return err
+	}
+	if err := takePlainFlag(); err != nil {
+		return err
+	}
+	stopPlainOutput, err := startPlainOutput()
+	if err != nil {
+		return err
+	}
+	defer stopPlainOutput()
 	if len(os.Args) < 2 {
-		return fmt.Errorf("usage: secretlint <command>\n\nCommands:\n  init    Setup secretlint in current repository\n  scan    Scan staged changes for secrets")
+		return fmt.Errorf("usage: secretlint <command>\n\nCommands:\n" +
+			"  init         Setup secretlint in current repository\n" +
+			"  scan         Scan staged changes for secrets\n" +
+			"  audit        Scan the commit history for secrets committed in the past\n" +
+			"  fix          Move detected secrets to environment variables\n" +
+			"  envify       Rewrite a config file to read credentials from the environment\n" +
+			"  redact       Mask detected secrets in files (--diff for a patch)\n" +
+			"  purge        Help remove a secret from git history\n" +
+			"  migrate      Emit commands to move secrets into a secret manager\n" +
+			"  report       Work with JSON scan reports (issues, diff, evidence, merge, annotate)\n" +
+			"  stats        Show finding trends from the local findings database\n" +
+			"  audit-log    Show or ship the log of hook decisions\n" +
+			"  recheck      Verify that previously detected secrets were revoked\n" +
+			"  policy       Sync or show the org config inherited through extends:\n" +
+			"  rules        List, export and install rules and signed rule packs\n" +
+			"  fleet        Scan many repositories and aggregate one report\n" +
+			"  hook         Verify the installed hooks and config were not tampered with\n" +
+			"  self-update  Install the latest release (checksum verified)\n" +
+			"  explain      Describe a rule: examples, severity, remediation and links\n" +
+			"  import       Convert a gitleaks config or detect-secrets baseline")
 	}
 
 	command := os.Args[1]
+	if command != "self-update" && command != "--help" && command != "-h" {
+		defer noticeNewVersion()
+	}
 	
 	switch command {
 	case "init":
-		return runInit()
+		return runInit(os.Args[2:])
 	case "scan":
 		return runScan(os.Args[2:])
+	case "audit":
+		return runAudit(os.Args[2:])
+	case "fix":
+		return runFix(os.Args[2:])
+	case "envify":
+		return runEnvify(os.Args[2:])
+	case "redact":
+		return runRedact(os.Args[2:])
+	case "purge":
+		return runPurge(os.Args[2:])
+	case "migrate":
+		return runMigrate(os.Args[2:])
+	case "report":
+		return runReport(os.Args[2:])
+	case "stats":
+		return runStats(os.Args[2:])
+	case "audit-log":
+		return runAuditLog(os.Args[2:])
+	case "recheck":
+		return runRecheck(os.Args[2:])
+	case "policy":
+		return runPolicy(os.Args[2:])
+	case "rules":
+		return runRules(os.Args[2:])
+	case "fleet":
+		return runFleet(os.Args[2:])
+	case "hook":
+		return runHook(os.Args[2:])
+	case "self-update":
+		return runSelfUpdate(os.Args[2:])
+	case "explain":
+		return runExplain(os.Args[2:])
+	case "import":
+		return runImport(os.Args[2:])
 	case "--help", "-h":
 		fmt.Println("secretlint - Lightweight secret detection for Git")
 		fmt.Println("\nUsage: secretlint <command>")
 		fmt.Println("\nCommands:")
-		fmt.Println("  init    Setup secretlint in current repository")
-		fmt.Println("  scan    Scan staged changes for secrets")
+		fmt.Println("  init         Setup secretlint in current repository (--pre-push to block pushes too, --interactive for a guided setup)")
+		fmt.Println("  scan         Scan staged changes for secrets")
+		fmt.Println("  audit        Scan the commit history for secrets committed in the past")
+		fmt.Println("  fix          Move detected secrets to environment variables")
+		fmt.Println("  envify       Rewrite a config file to read credentials from the environment")
+		fmt.Println("  redact       Mask detected secrets in files (--diff for a patch)")
+		fmt.Println("  purge        Help remove a secret from git history")
+		fmt.Println("  migrate      Emit commands to move secrets into a secret manager")
+		fmt.Println("  report       Work with JSON scan reports (issues, diff, evidence, merge, annotate)")
+		fmt.Println("  stats        Show finding trends from the local findings database")
+		fmt.Println("  audit-log    Show or ship the log of hook decisions")
+		fmt.Println("  recheck      Verify that previously detected secrets were revoked")
+		fmt.Println("  policy       Sync or show the org config inherited through extends:")
+		fmt.Println("  rules        List, export and install rules and signed rule packs")
+		fmt.Println("  fleet        Scan many repositories and aggregate one report")
+		fmt.Println("  hook         Verify the installed hooks and config were not tampered with")
+		fmt.Println("  self-update  Install the latest release (checksum verified)")
+		fmt.Println("  explain      Describe a rule: examples, severity, remediation and links")
+		fmt.Println("  import       Convert a gitleaks config or detect-secrets baseline")
 		fmt.Println("\nOptions:")
-		fmt.Println("  --staged    Scan only staged changes (default for scan)")
+		fmt.Println("  --staged          Scan only staged changes (default for scan)")
+		fmt.Println("  --interactive     Triage each finding: unstage, allow inline, baseline or open")
+		fmt.Println("  --partial         Unstage files with secrets and commit the rest (hook mode)")
+		fmt.Println("  --pre-push        Scan the commits being pushed (used by the pre-push hook)")
+		fmt.Println("  --history         Scan all commits and report each secret's lifetime")
+		fmt.Println("  --repos           Scan the history of several repositories at once (with --history)")
+		fmt.Println("  --refs            Also scan ref namespaces such as notes with --history, e.g. --refs notes,pull")
+		fmt.Println("  --no-cache        Rescan file changes a previous --history scan found clean")
+		fmt.Println("  --first-parent    Scan only the mainline with --history; each merge is scanned as a whole")
+		fmt.Println("  --all             Scan every tracked file, with CODEOWNERS and blame attribution")
+		fmt.Println("  <paths...>        Scan the tracked files under these files or directories (implies --all)")
+		fmt.Println("  --image <ref>     Scan a container image's layers, ENV/LABEL metadata and build history")
+		fmt.Println("  --group-by        Group --all/--history output by owner")
+		fmt.Println("  --format          Output format for scan: text (default) or json")
+		fmt.Println("  --context N       Show N lines around each finding, with secrets masked")
+		fmt.Println("  --max-findings    Print at most N findings; --stop-at-max also stops --all/--history scans there")
+		fmt.Println("  --no-mask         Show secrets in full in terminal output; reports stay masked (trusted local use only)")
+		fmt.Println("  --dry-run         Show what a scan would cover (files, rules, ignores, config) without scanning")
+		fmt.Println("  --deterministic   Byte-identical output between runs: fixed report time (SOURCE_DATE_EPOCH), no progress line")
+		fmt.Println("  --verbose         Show how long each staged file took to scan (also settings.verbose)")
+		fmt.Println("  --strict-config   Fail when a rule or rule pack can't be loaded, instead of warning and scanning without it")
+		fmt.Println("  --root <dir>      Scan the repository containing dir; paths are always relative to the repository root")
+		fmt.Println("  --absolute-paths  Show finding paths as absolute paths (also settings.absolute_paths)")
+		fmt.Println("  --offline         Forbid all network access; fail if the config needs it (any command)")
+		fmt.Println("  --profile         Apply a named profile from the config, e.g. ci (any command)")
+		fmt.Println("  --plain           Text labels instead of emoji; automatic when output isn't a terminal (any command)")
 		return nil
 	default:
 		return fmt.Errorf("unknown command: %s\n\nRun 'secretlint --help' for usage", command)
@@ -32,9 +157,274 @@ func Execute() error {
 }
 
 
-func runScan(args []string) error {
-	fmt.Println("🔍 Scanning for secrets...")
+func runScan(args []string) (err error) {
+	flags := flag.NewFlagSet("scan", flag.ContinueOnError)
+	flags.Bool("staged", true, "Scan only staged changes (default)")
+	interactive := flags.Bool("interactive", false, "Walk through each finding and choose how to resolve it")
+	hook := flags.Bool("hook", false, "Run in pre-commit hook mode")
+	partial := flags.Bool("partial", false, "Unstage files with secrets and let the rest of the commit proceed")
+	prePush := flags.Bool("pre-push", false, "Scan the commits being pushed (refs are read from stdin)")
+	history := flags.Bool("history", false, "Scan every commit in history (optionally limited to the given revisions)")
+	repos := flags.String("repos", "", "Comma-separated repository paths to scan together with --history")
+	refs := flags.String("refs", "", "With --history, also scan these ref namespaces, e.g. notes,pull (or refs/... globs)")
+	noCache := flags.Bool("no-cache", false, "With --history, rescan file changes a previous scan found clean")
+	firstParent := flags.Bool("first-parent", false, "With --history, follow only the first parent of merges and scan each merge as a whole")
+	all := flags.Bool("all", false, "Scan every tracked file in the working tree")
+	imageRef := flags.String("image", "", "Scan the layers and config of a container image (ref or 'docker save' tarball)")
+	groupBy := flags.String("group-by", "", "Group text output of --all/--history scans: owner")
+	format := flags.String("format", "text", "Output format: text or json")
+	contextLines := flags.Int("context", 0, "Show N lines before and after each finding, with secrets masked")
+	maxFindings := flags.Int("max-findings", 0, "Print at most N findings (default settings.max_findings; 0 prints all)")
+	stopAtMax := flags.Bool("stop-at-max", false, "With --all or --history, stop scanning once --max-findings findings were found")
+	noMask := flags.Bool("no-mask", false, "Show secrets in full in terminal output; reports stay masked (trusted local use only)")
+	unmask := flags.Bool("unmask", false, "Deprecated spelling of --no-mask")
+	dryRun := flags.Bool("dry-run", false, "Show the files, rules, ignore patterns and config sources a scan would use, without scanning")
+	deterministic := flags.Bool("deterministic", false, "Make output identical between runs, for tests and diffing CI output")
+	verbose := flags.Bool("verbose", false, "Report how long each staged file took to scan")
+	strictConfig := flags.Bool("strict-config", false, "Fail instead of warning when a rule or rule pack can't be loaded")
+	rootDir := flags.String("root", "", "Scan the repository containing this directory, as if started there")
+	absolutePaths := flags.Bool("absolute-paths", false, "Show finding paths as absolute paths instead of relative to the repository root")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	// Paths limit a full scan, so 'scan <paths...>' is 'scan --all <paths...>'.
+	// The arguments of a pre-push scan are the hook's remote name and URL.
+	var paths []string
+	if !*history && !*prePush {
+		paths = flags.Args()
+	}
+	if len(paths) > 0 {
+		if *hook || *imageRef != "" {
+			return fmt.Errorf("paths can't be given to --hook or --image scans")
+		}
+		*all = true
+	}
+	// In a hook, secretlint failing is decided by settings.hook.on_error
+	// rather than by whatever error happens to surface
+	var cfg *config.Config
+	if *hook || *prePush {
+		defer func() { err = hookFailure(cfg, recover(), err) }()
+	}
+	if *groupBy != "" && *groupBy != "owner" {
+		return fmt.Errorf("unsupported grouping %q (supported: owner)", *groupBy)
+	}
+	if *contextLines < 0 {
+		return fmt.Errorf("--context must not be negative")
+	}
+	if *maxFindings < 0 {
+		return fmt.Errorf("--max-findings must not be negative")
+	}
+	if *stopAtMax && !*all && !*history {
+		return fmt.Errorf("--stop-at-max supports --all and --history scans")
+	}
+	if *unmask {
+		fmt.Fprintln(os.Stderr, "⚠️  --unmask is deprecated; use --no-mask")
+		*noMask = true
+	}
+	if *noMask && (*hook || *prePush) {
+		return fmt.Errorf("--no-mask is for trusted local use and can't be used from git hooks")
+	}
+	if *refs != "" && !*history {
+		return fmt.Errorf("--refs supports --history scans")
+	}
+	if *noCache && !*history {
+		return fmt.Errorf("--no-cache supports --history scans")
+	}
+	if *firstParent && !*history {
+		return fmt.Errorf("--first-parent supports --history scans")
+	}
+	if *contextLines > 0 && (*prePush || *imageRef != "" || *history) {
+		return fmt.Errorf("--context supports staged and --all scans")
+	}
+	if *imageRef != "" && (*rootDir != "" || *absolutePaths) {
+		return fmt.Errorf("--root and --absolute-paths don't apply to --image scans")
+	}
+	// Staged, history and pre-push scans read git's objects, or a Mercurial
+	// or Jujutsu repository's; --all can walk the filesystem instead and
+	// images don't need git at all
+	if err := scanner.CheckGit(); err != nil && !*all && *imageRef == "" && (*prePush || scanner.DetectVCS() == nil) {
+		return fmt.Errorf("%v: staged, --history and pre-push scans need git (https://git-scm.com); 'secretlint scan --all' still works without it", err)
+	}
+	
+	// Everything but an image scan runs from the repository root, so paths in
+	// findings, .secretignore and the baseline don't depend on where the
+	// scan was started
+	var root string
+	if *imageRef == "" {
+		repoPaths := splitList(*repos)
+		for i, repo := range repoPaths {
+			if repoPaths[i], err = filepath.Abs(repo); err != nil {
+				return err
+			}
+		}
+		*repos = strings.Join(repoPaths, ",")
+		for i, path := range paths {
+			if paths[i], err = filepath.Abs(path); err != nil {
+				return err
+			}
+		}
+		if root, err = enterRepoRoot(*rootDir); err != nil {
+			return err
+		}
+		if paths, err = repoRelativePaths(root, paths); err != nil {
+			return err
+		}
+	}
+	
+	cfg, err = config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if *hook || *prePush {
+		if cfg, err = selectHookProfile(cfg); err != nil {
+			return err
+		}
+	}
+	if len(cfg.ExpiredReportOnly) > 0 {
+		fmt.Fprintf(os.Stderr, "ℹ️  report_only of profile %s ended on %s; %s block(s) again (remove them from the profile)\n", cfg.Profile, cfg.ReportOnlyUntil, strings.Join(cfg.ExpiredReportOnly, ", "))
+	}
+	if *strictConfig {
+		if errs := scanner.CheckRules(cfg); len(errs) > 0 {
+			var problems []string
+			for _, e := range errs {
+				problems = append(problems, e.Error())
+			}
+			return fmt.Errorf("%d rule(s) or pack(s) failed to load (--strict-config):\n  %s", len(errs), strings.Join(problems, "\n  "))
+		}
+	}
 	
-	// Import and use the git differ
-	return scanStagedChanges()
-}
\ No newline at end of file
+	revs := historyRevs(flags.Args(), splitList(*refs))
+	if *firstParent {
+		revs = firstParentRevs(revs)
+	}
+	if *dryRun {
+		switch {
+		case *prePush || *imageRef != "" || *repos != "":
+			return fmt.Errorf("--dry-run supports staged, --all and --history scans")
+		case *all:
+			return printDryRun(cfg, "all", paths)
+		case *history:
+			return printDryRun(cfg, "history", revs)
+		default:
+			return printDryRun(cfg, "staged", nil)
+		}
+	}
+	
+	// The profile's format and the config's limits apply unless overridden
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if !set["format"] && cfg.Format != "" {
+		*format = cfg.Format
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: text, json)", *format)
+	}
+	
+	// Keep stdout clean for machine-readable output
+	var status io.Writer = os.Stdout
+	if *format == "json" {
+		status = os.Stderr
+	}
+	if cfg.Settings.Events.URL != "" {
+		if err := requireOnline(cfg, "settings.events.url"); err != nil {
+			return err
+		}
+	}
+	if cfg.Settings.Telemetry.Enabled && telemetry.CompiledIn {
+		if err := requireOnline(cfg, "settings.telemetry"); err != nil {
+			return err
+		}
+	}
+	
+	if *deterministic {
+		if err := makeDeterministic(); err != nil {
+			return err
+		}
+	}
+	if *absolutePaths || (cfg.Settings.AbsolutePaths && !set["absolute-paths"]) {
+		// Findings of several repositories are labeled by repository instead
+		if *repos == "" {
+			scanner.ShowAbsolutePaths(root)
+		}
+	}
+	if *noMask {
+		scanner.Unmask()
+		fmt.Fprintln(status, "⚠️  --no-mask: secrets are shown in full; don't share this output")
+	}
+	
+	if cfg.Profile != "" {
+		fmt.Fprintf(status, "🔍 Scanning for secrets (profile %s)...\n", cfg.Profile)
+	} else {
+		fmt.Fprintln(status, "🔍 Scanning for secrets...")
+	}
+	
+	options := scanOptions{
+		interactive: *interactive,
+		hook:        *hook,
+		partial:     *partial,
+		format:      *format,
+		groupBy:     *groupBy,
+		verbose:     *verbose || cfg.Settings.Verbose,
+		status:      status,
+		stats:       &scanStats{},
+		noCache:     *noCache,
+		paths:       paths,
+	}
+	if *contextLines > 0 && *format == "text" {
+		options.output.context = newSourceContext(*contextLines, !*all)
+	}
+	options.output.maxFindings = cfg.Settings.MaxFindings
+	options.output.summary = *hook
+	if set["max-findings"] {
+		options.output.maxFindings = *maxFindings
+	}
+	if *stopAtMax {
+		if options.output.maxFindings == 0 {
+			return fmt.Errorf("--stop-at-max needs --max-findings or settings.max_findings")
+		}
+		options.stopAt = options.output.maxFindings
+	}
+	
+	mode := "staged"
+	start := time.Now()
+	switch {
+	case *prePush:
+		mode = "pre-push"
+		err = scanPrePush(cfg, os.Stdin, options.stats)
+	case *imageRef != "":
+		mode = "image"
+		err = scanImage(options, *imageRef, !cfg.Offline())
+	case *all:
+		mode = "all"
+		err = scanAll(cfg, options)
+	case *history:
+		mode = "history"
+		err = scanHistory(options, revs, splitList(*repos))
+	default:
+		err = scanStagedChanges(cfg, options)
+	}
+	
+	duration := time.Since(start)
+	sendScanEvent(cfg, mode, *hook || *prePush, duration, options.stats, err)
+	sendTelemetry(cfg, mode, *hook || *prePush, duration, options.stats)
+	return err
+}
+
+// enterRepoRoot switches to dir (--root), then to the top level of the
+// repository or worktree it is in, and returns the directory scans run from.
+// Outside a repository the directory stays as it is.
+func enterRepoRoot(dir string) (string, error) {
+	if dir != "" {
+		if err := os.Chdir(dir); err != nil {
+			return "", fmt.Errorf("failed to enter %s: %w", dir, err)
+		}
+	}
+	if vcs := scanner.DetectVCS(); vcs != nil {
+		if root, err := vcs.RepoRoot(); err == nil {
+			if err := os.Chdir(root); err != nil {
+				return "", fmt.Errorf("failed to enter %s: %w", root, err)
+			}
+		}
+	}
+	return os.Getwd()
+}
@@ -0,0 +1,330 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/regexcache"
+	"secretlint/internal/rulepack"
+	"secretlint/internal/scanner"
+	"secretlint/internal/signature"
+)
+
+func runRules(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: secretlint rules <subcommand>\n\nSubcommands:\n  list     List every rule with its pattern, tags and references\n  install  Download, verify and enable a versioned rule pack\n  export   Print the rules as a gitleaks config or trufflehog detectors\n  coverage Measure each rule's precision and recall on a labeled corpus")
+	}
+
+	switch args[0] {
+	case "list":
+		return runRulesList(args[1:])
+	case "install":
+		return runRulesInstall(args[1:])
+	case "export":
+		return runRulesExport(args[1:])
+	case "coverage":
+		return runRulesCoverage(args[1:])
+	default:
+		return fmt.Errorf("unknown rules subcommand: %s", args[0])
+	}
+}
+
+// ruleListing is a rule in 'rules list --format json'
+type ruleListing struct {
+	scanner.RuleInfo
+	Enabled bool `json:"enabled"`
+}
+
+// runRulesList prints every rule secretlint knows and whether this
+// repository's config enables it; the JSON form lets security portals
+// render detection coverage
+func runRulesList(args []string) error {
+	flags := flag.NewFlagSet("rules list", flag.ContinueOnError)
+	format := flags.String("format", "text", "Output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: text, json)", *format)
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("usage: secretlint rules list [--format text|json]")
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	rules := listRules(cfg)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		listing := struct {
+			Tool        string        `json:"tool"`
+			Version     string        `json:"version"`
+			GeneratedAt time.Time     `json:"generated_at"`
+			Rules       []ruleListing `json:"rules"`
+		}{"secretlint", Version, time.Now().UTC(), rules}
+		if err := encoder.Encode(listing); err != nil {
+			return fmt.Errorf("failed to write rules: %w", err)
+		}
+		return nil
+	}
+
+	// Columns fit the longest ID and name, which packs and custom rules set
+	idWidth, nameWidth := 0, 0
+	for _, rule := range rules {
+		if len(rule.ID) > idWidth {
+			idWidth = len(rule.ID)
+		}
+		if len(rule.Name) > nameWidth {
+			nameWidth = len(rule.Name)
+		}
+	}
+	for _, rule := range rules {
+		status := "enabled"
+		if !rule.Enabled {
+			status = "disabled"
+		}
+		source := "built-in"
+		if rule.Pack != "" {
+			source = rule.Pack + " pack"
+		} else if rule.Custom {
+			source = "custom"
+		}
+		fmt.Printf("  %-*s %-*s %-8s %-9s %s\n", idWidth, rule.ID, nameWidth, rule.Name, rule.Severity, status, source)
+	}
+	fmt.Printf("\n💡 Run 'secretlint explain RULE' for details, or 'secretlint rules list --format json' for patterns, tags and references\n")
+	return nil
+}
+
+// listRules returns every rule secretlint knows, sorted by ID, and
+// whether cfg enables it
+func listRules(cfg *config.Config) []ruleListing {
+	var rules []ruleListing
+	for _, info := range scanner.Catalog(cfg, Version) {
+		enabled := cfg.RuleEnabled(info.ID) && (info.Pack == "" || packEnabled(cfg.Packs, info.Pack))
+		switch info.ID {
+		case scanner.LiteralSecretRule:
+			enabled = enabled && cfg.Settings.References.Enabled
+		case scanner.SensitiveFileRule:
+			enabled = enabled && cfg.Settings.Filenames.Severity != "off"
+		}
+		rules = append(rules, ruleListing{RuleInfo: info, Enabled: enabled})
+	}
+	return rules
+}
+
+// runRulesInstall installs a signed rule-pack bundle into the shared packs
+// directory and pins it under packs: in the config
+func runRulesInstall(args []string) error {
+	flags := flag.NewFlagSet("rules install", flag.ContinueOnError)
+	publicKey := flags.String("public-key", "", "Minisign public key the pack must be signed with (default: extends_public_key)")
+	noRegister := flags.Bool("no-register", false, "Install the pack without adding it to packs: in the config")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: secretlint rules install [flags] <path|https://...|oci://...>")
+	}
+	source := flags.Arg(0)
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if rulepack.IsRemote(source) {
+		if err := requireOnline(cfg, "installing "+source); err != nil {
+			return err
+		}
+	}
+	if *publicKey == "" {
+		*publicKey = cfg.ExtendsPublicKey
+	}
+	if *publicKey == "" {
+		return fmt.Errorf("rule packs must be signed: pass --public-key or set extends_public_key in %s", config.DefaultConfigFile)
+	}
+	key, err := signature.ParsePublicKey(*publicKey)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📦 Fetching %s...\n", source)
+	pack, data, err := rulepack.Fetch(source, key)
+	if err != nil {
+		return err
+	}
+	path, err := rulepack.Install(pack, data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Installed %s (%d rule(s), signature verified) to %s\n", pack.Ref(), len(pack.Rules), path)
+
+	if *noRegister {
+		return nil
+	}
+	if err := registerPack(config.DefaultConfigFile, pack.Name, pack.Ref()); err != nil {
+		return err
+	}
+	fmt.Printf("📝 Enabled %s in %s\n", pack.Ref(), config.DefaultConfigFile)
+	return nil
+}
+
+// registerPack pins ref under packs: in the config, replacing other versions
+// of the same pack. The file is edited line by line to keep its comments.
+func registerPack(configPath, name, ref string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	lines := strings.Split(string(data), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "packs:") {
+			start = i
+			break
+		}
+	}
+
+	var packs []string
+	end := start + 1
+	if start >= 0 {
+		value := strings.TrimSpace(strings.TrimPrefix(lines[start], "packs:"))
+		if i := strings.Index(value, "#"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		if strings.HasPrefix(value, "[") {
+			packs = splitList(strings.Trim(value, "[]"))
+		} else {
+			// Block sequence: collect the "- item" lines that follow
+			for ; end < len(lines); end++ {
+				item := strings.TrimSpace(lines[end])
+				if !strings.HasPrefix(item, "- ") {
+					break
+				}
+				packs = append(packs, strings.TrimSpace(strings.TrimPrefix(item, "- ")))
+			}
+		}
+	}
+
+	var kept []string
+	for _, pack := range packs {
+		pack = strings.Trim(pack, `"'`)
+		if pack != name && !strings.HasPrefix(pack, name+"@") {
+			kept = append(kept, pack)
+		}
+	}
+	entry := "packs: [" + strings.Join(append(kept, ref), ", ") + "]"
+
+	if start >= 0 {
+		lines = append(lines[:start], append([]string{entry}, lines[end:]...)...)
+	} else {
+		if len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		lines = append(lines, entry, "")
+	}
+	if err := os.WriteFile(configPath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to update %s: %w", configPath, err)
+	}
+	return nil
+}
+
+// runRulesExport prints the rules of the installed packs, and optionally the
+// built-in rules, in another scanner's format, so custom rules are defined
+// once for every scanner an organization runs
+func runRulesExport(args []string) error {
+	flags := flag.NewFlagSet("rules export", flag.ContinueOnError)
+	format := flags.String("format", "", "Output format: "+strings.Join(rulepack.ExportFormats, ", "))
+	builtin := flags.Bool("builtin", false, "Include the built-in rules and optional packs, not only installed packs")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !containsString(rulepack.ExportFormats, *format) || flags.NArg() > 0 {
+		return fmt.Errorf("usage: secretlint rules export --format %s [--builtin]", strings.Join(rulepack.ExportFormats, "|"))
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	var rules []rulepack.Rule
+	if *builtin {
+		for _, rule := range scanner.BuiltinRules() {
+			rules = append(rules, rulepack.Rule{
+				ID:          rule.ID,
+				Name:        rule.Name,
+				Pattern:     rule.Pattern.String(),
+				Description: rule.Description,
+				Advice:      rule.Advice,
+				Severity:    rule.Severity,
+			})
+		}
+	}
+	for _, ref := range cfg.Packs {
+		// Optional packs are named without a version and are built in
+		if !strings.Contains(ref, "@") {
+			continue
+		}
+		pack, err := rulepack.Load(ref)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, pack.Rules...)
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("no installed rule packs in %s to export (use --builtin to export the built-in rules)", config.DefaultConfigFile)
+	}
+
+	// The other scanners run Go's regexp or similar, without lookarounds
+	var backtracking []string
+	supported := rules[:0]
+	for _, rule := range rules {
+		if rule.Engine == regexcache.EngineRegexp2 {
+			backtracking = append(backtracking, rule.ID)
+			continue
+		}
+		supported = append(supported, rule)
+	}
+	rules = supported
+	if len(backtracking) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipped %s: their patterns need the regexp2 engine, which %s can't run\n", strings.Join(backtracking, ", "), *format)
+	}
+
+	// Keep stdout redirectable into the other scanner's config file
+	exported := len(rules)
+	switch *format {
+	case "gitleaks":
+		fmt.Print(rulepack.ExportGitleaks(rules))
+	case "trufflehog":
+		output, skipped, err := rulepack.ExportTrufflehog(rules)
+		if err != nil {
+			return err
+		}
+		fmt.Print(output)
+		exported -= len(skipped)
+		if len(skipped) > 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipped %s: trufflehog needs a keyword, and these patterns have no literal of 3+ characters\n", strings.Join(skipped, ", "))
+		}
+	case "github":
+		output, skipped, err := rulepack.ExportGitHub(rules)
+		if err != nil {
+			return err
+		}
+		fmt.Print(output)
+		exported -= len(skipped)
+		if len(skipped) > 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipped %s: warning rules would block pushes under push protection\n", strings.Join(skipped, ", "))
+		}
+	}
+	fmt.Fprintf(os.Stderr, "✅ Exported %d rule(s) as %s\n", exported, *format)
+	return nil
+}
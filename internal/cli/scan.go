@@ -2,18 +2,106 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
 
+	"secretlint/internal/auditlog"
+	"secretlint/internal/config"
+	"secretlint/internal/report"
 	"secretlint/internal/scanner"
+	"secretlint/internal/structured"
 )
 
-func scanStagedChanges() error {
+// scanOptions controls how staged changes are scanned and reported
+type scanOptions struct {
+	interactive bool
+	hook        bool
+	partial     bool
+	format      string
+	groupBy     string
+	
+	// verbose reports per-file scan durations (--verbose or settings.verbose)
+	verbose bool
+	
+	// output controls how findings are printed as text
+	output printOptions
+	
+	// stopAt ends --all and --history scans once that many findings were found; 0 scans everything
+	stopAt int
+	
+	// noCache rescans file changes of --history scans that the history cache knows are clean
+	noCache bool
+	
+	// paths limits --all scans to the tracked files under them, relative to the repository root
+	paths []string
+	
+	// status receives progress messages; it is stderr when stdout carries a report
+	status io.Writer
+	
+	// stats collects counts for the scan-completed event
+	stats *scanStats
+}
+
+// scanStats summarizes a scan run for the scan-completed event and telemetry
+type scanStats struct {
+	files    int
+	findings int
+	rules    map[string]int
+}
+
+// addFindings counts findings, in total and per rule
+func (s *scanStats) addFindings(findings []scanner.Finding) {
+	s.findings += len(findings)
+	for _, finding := range findings {
+		if s.rules == nil {
+			s.rules = make(map[string]int)
+		}
+		s.rules[finding.RuleID]++
+	}
+}
+
+func scanStagedChanges(cfg *config.Config, options scanOptions) (err error) {
+	status := options.status
 	differ := scanner.NewGitDiffer()
 	
 	// Check if we're in a git repository
 	if !differ.IsInGitRepo() {
+		if vcs := otherVCS(); vcs != nil {
+			return scanPendingChanges(options, vcs)
+		}
 		return fmt.Errorf("not in a git repository")
 	}
 	
+	// A hook with a time budget leaves archives to a background scan, whose
+	// results arrive with the next commit
+	started := time.Now()
+	var budget time.Duration
+	if options.hook {
+		if budget, err = hookTimeBudget(cfg); err != nil {
+			return err
+		}
+		reportDeferredScans(status, differ)
+	}
+	
+	// Record the hook's final decision, after triage and partial unstaging
+	var lines []scanner.DiffLine
+	var findings []scanner.Finding
+	if options.hook {
+		defer func() {
+			auditHook(cfg, differ, auditlog.Entry{
+				Hook:     "pre-commit",
+				Branch:   differ.CurrentBranch(),
+				Head:     differ.HeadSHA(),
+				Files:    countFiles(lines),
+				Decision: hookDecision(findings, err),
+			}, findings)
+		}()
+	}
+	
 	// Check if there are staged changes
 	hasChanges, err := differ.HasStagedChanges()
 	if err != nil {
@@ -21,25 +109,40 @@ func scanStagedChanges() error {
 	}
 	
 	if !hasChanges {
-		fmt.Println("✅ No staged changes to scan")
-		return nil
+		fmt.Fprintln(status, "✅ No staged changes to scan")
+		return writeReport(options, nil)
 	}
 	
 	// Get the staged changes
-	lines, err := differ.GetStagedChanges()
+	lines, err = differ.GetStagedChanges()
 	if err != nil {
 		return fmt.Errorf("failed to get staged changes: %w", err)
 	}
 	
-	if len(lines) == 0 {
-		fmt.Println("✅ No new lines to scan")
-		return nil
+	// Initialize the secret scanner
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+	secretScanner.UseIndex()
+	stagedFiles, err := differ.StagedFiles()
+	if err != nil {
+		return err
 	}
+	archives := stagedArchives(cfg, secretScanner, stagedFiles)
+	lines = append(lines, encodedStagedLines(secretScanner, differ, stagedFiles, lines, archives)...)
+	pathFindings := scanSensitivePaths(cfg, secretScanner, stagedFiles)
 	
-	fmt.Printf("📄 Found %d added lines to scan\n", len(lines))
+	if len(lines) == 0 && len(archives) == 0 && len(pathFindings) == 0 {
+		fmt.Fprintln(status, "✅ No new lines to scan")
+		return writeReport(options, nil)
+	}
 	
-	// Initialize the secret scanner
-	secretScanner := scanner.NewSecretScanner()
+	fmt.Fprintf(status, "📄 Found %d added lines to scan\n", len(lines))
+	options.stats.files = countFiles(lines)
+	if len(archives) > 0 {
+		fmt.Fprintf(status, "📦 Scanning %d staged archive(s)\n", len(archives))
+	}
 	
 	// Show ignored files for debugging
 	ignoredFiles := make(map[string]int)
@@ -49,30 +152,425 @@ func scanStagedChanges() error {
 		}
 	}
 	if len(ignoredFiles) > 0 {
-		fmt.Printf("🚫 Ignored files:\n")
-		for filePath, lineCount := range ignoredFiles {
-			fmt.Printf("   %s (%d lines)\n", filePath, lineCount)
+		fmt.Fprintf(status, "🚫 Ignored files:\n")
+		var ignoredPaths []string
+		for filePath := range ignoredFiles {
+			ignoredPaths = append(ignoredPaths, filePath)
+		}
+		sort.Strings(ignoredPaths)
+		for _, filePath := range ignoredPaths {
+			fmt.Fprintf(status, "   %s (%d lines)\n", filePath, ignoredFiles[filePath])
 		}
 	}
 	
 	// Scan all lines for secrets
-	findings := secretScanner.ScanLines(lines)
+	var verbose io.Writer
+	if options.verbose {
+		verbose = status
+	}
+	findings = scanStagedLines(secretScanner, differ, lines, verbose)
+	if budget > 0 {
+		archiveFindings, deferred := scanStagedArchivesWithin(cfg, secretScanner, differ, archives, started.Add(budget))
+		findings = append(findings, archiveFindings...)
+		if len(deferred) > 0 {
+			queueDeferredScan(status, differ, deferred)
+		}
+	} else {
+		findings = append(findings, scanStagedArchives(cfg, secretScanner, differ, archives)...)
+	}
+	findings = append(findings, pathFindings...)
+	findings = applyRegoPolicy(cfg, differ, "staged", findings)
+	recordScan(cfg, differ, "staged", lines, findings)
+	
+	if len(findings) > 0 && options.interactive {
+		findings, err = triageFindings(findings, secretScanner, differ)
+		if err != nil {
+			return err
+		}
+	}
+	
+	options.stats.addFindings(findings)
 	
 	if len(findings) == 0 {
-		fmt.Println("✅ No secrets detected in staged changes")
-		return nil
+		fmt.Fprintln(status, "✅ No secrets detected in staged changes")
+		return writeReport(options, nil)
+	}
+	
+	blocking := scanner.BlockingFindings(findings)
+	if len(blocking) == 0 {
+		fmt.Fprintf(status, "\n⚠️  %d warning(s) in staged changes (not blocking):\n\n", len(findings))
+		if options.format != "json" {
+			printFindings(findings, options.output)
+		}
+		return writeReport(options, findings)
 	}
 	
 	// Report findings
-	fmt.Printf("\n⛔ %d secret(s) detected in staged changes:\n\n", len(findings))
+	fmt.Fprintf(status, "\n⛔ %d secret(s) detected in staged changes:\n\n", len(findings))
+	
+	if options.format == "json" {
+		if err := writeReport(options, findings); err != nil {
+			return err
+		}
+	} else {
+		printFindings(findings, options.output)
+	}
 	
+	if options.hook && (options.partial || cfg.Settings.Hook.AutoUnstage) {
+		return unstageOffendingFiles(status, differ, blocking, options.partial)
+	}
+	
+	fmt.Fprintln(status, "Commit aborted.")
+	return errCommitBlocked
+}
+
+// stagedFileScan is the outcome of scanning one staged file
+type stagedFileScan struct {
+	filePath string
+	lines    int
+	findings []scanner.Finding
+	duration time.Duration
+}
+
+// scanStagedLines scans diff lines, giving structured config files their key
+// paths by parsing the staged version of each file. Files are scanned in
+// parallel, so a large vendored directory doesn't go through one goroutine;
+// findings keep the order of the files. With verbose set, the time each
+// file took is written to it, slowest first.
+func scanStagedLines(secretScanner *scanner.SecretScanner, differ *scanner.GitDiffer, lines []scanner.DiffLine, verbose io.Writer) []scanner.Finding {
+	var order []string
+	byFile := make(map[string][]scanner.DiffLine)
+	for _, line := range lines {
+		if _, ok := byFile[line.FilePath]; !ok {
+			order = append(order, line.FilePath)
+		}
+		byFile[line.FilePath] = append(byFile[line.FilePath], line)
+	}
+
+	results := make([]stagedFileScan, len(order))
+	var wg sync.WaitGroup
+	var once sync.Once
+	var crash interface{}
+	slots := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, filePath := range order {
+		wg.Add(1)
+		go func(i int, filePath string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			// A panic is raised again below, where the hook's on_error
+			// policy can recover it
+			defer func() {
+				if r := recover(); r != nil {
+					once.Do(func() { crash = r })
+				}
+			}()
+
+			start := time.Now()
+			results[i] = stagedFileScan{
+				filePath: filePath,
+				lines:    len(byFile[filePath]),
+				findings: scanStagedFile(secretScanner, differ, filePath, byFile[filePath]),
+			}
+			results[i].duration = time.Since(start)
+		}(i, filePath)
+	}
+	wg.Wait()
+	if crash != nil {
+		panic(crash)
+	}
+
+	var findings []scanner.Finding
+	for _, result := range results {
+		findings = append(findings, result.findings...)
+	}
+	if verbose != nil {
+		printScanDurations(verbose, results)
+	}
+	return findings
+}
+
+// scanStagedFile scans the added lines of one file, parsing its staged blob
+// when it is a structured config file
+func scanStagedFile(secretScanner *scanner.SecretScanner, differ *scanner.GitDiffer, filePath string, fileLines []scanner.DiffLine) []scanner.Finding {
+	if !structured.Supported(filePath) {
+		return secretScanner.ScanLines(fileLines)
+	}
+	content, err := differ.StagedContent(filePath)
+	if err == nil && scanner.IsLFSPointer(content) {
+		object, ok := secretScanner.LFSObject(content)
+		if !ok {
+			return secretScanner.ScanLines(fileLines)
+		}
+		// The whole object is new as far as the scan is concerned
+		return secretScanner.ScanStructured(scanner.ContentLines(filePath, object), object)
+	}
+	if err != nil {
+		return secretScanner.ScanLines(fileLines)
+	}
+	content, _, _ = scanner.DecodeText(content)
+	return secretScanner.ScanStructured(fileLines, content)
+}
+
+// printScanDurations lists how long each staged file took, slowest first
+func printScanDurations(w io.Writer, results []stagedFileScan) {
+	sorted := append([]stagedFileScan(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].duration > sorted[j].duration })
+	fmt.Fprintf(w, "⏱️  Scanned %d file(s) with up to %d at a time:\n", len(sorted), runtime.GOMAXPROCS(0))
+	for _, result := range sorted {
+		fmt.Fprintf(w, "   %8s  %s (%d lines)\n", result.duration.Round(time.Microsecond), result.filePath, result.lines)
+	}
+}
+
+// encodedStagedLines recovers the added lines of staged UTF-16 files, which
+// git diffs as binary, by decoding the staged and HEAD versions
+func encodedStagedLines(secretScanner *scanner.SecretScanner, differ *scanner.GitDiffer, files []string, lines []scanner.DiffLine, archives []string) []scanner.DiffLine {
+	skip := make(map[string]bool)
+	for _, line := range lines {
+		skip[line.FilePath] = true
+	}
+	for _, filePath := range archives {
+		skip[filePath] = true
+	}
+
+	var added []scanner.DiffLine
+	for _, filePath := range files {
+		if skip[filePath] || secretScanner.GetIgnoreChecker().ShouldIgnore(filePath) {
+			continue
+		}
+		data, err := differ.StagedContent(filePath)
+		if err != nil {
+			continue
+		}
+		text, encoding, ok := scanner.DecodeText(data)
+		if !ok || (encoding != scanner.EncodingUTF16LE && encoding != scanner.EncodingUTF16BE) {
+			continue
+		}
+
+		// Lines already at HEAD aren't new; a new file has no HEAD version
+		existing := make(map[string]int)
+		if head, err := differ.HeadContent(filePath); err == nil {
+			headText, _, _ := scanner.DecodeText(head)
+			for _, line := range scanner.ContentLines(filePath, headText) {
+				existing[line.Content]++
+			}
+		}
+		for _, line := range scanner.ContentLines(filePath, text) {
+			if existing[line.Content] > 0 {
+				existing[line.Content]--
+				continue
+			}
+			added = append(added, line)
+		}
+	}
+	return added
+}
+
+// countFiles returns the number of distinct files among diff lines
+func countFiles(lines []scanner.DiffLine) int {
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		seen[line.FilePath] = true
+	}
+	return len(seen)
+}
+
+// unstageOffendingFiles removes files with findings from the index. In partial
+// mode the commit then proceeds with whatever safe changes remain staged.
+func unstageOffendingFiles(status io.Writer, differ *scanner.GitDiffer, findings []scanner.Finding, partial bool) error {
+	seen := make(map[string]bool)
+	var files []string
 	for _, finding := range findings {
-		fmt.Printf("Rule     : %s\n", finding.RuleID)
-		fmt.Printf("File     : %s:%d\n", finding.FilePath, finding.LineNum)
-		fmt.Printf("Snippet  : %s\n", finding.MaskSecret())
-		fmt.Printf("Advice   : %s\n\n", finding.Advice)
+		if !seen[finding.FilePath] {
+			seen[finding.FilePath] = true
+			files = append(files, finding.FilePath)
+		}
+	}
+	sort.Strings(files)
+	
+	if err := differ.UnstageFiles(files...); err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(status, "🚫 Unstaged %d file(s) containing secrets (your working tree changes are kept):\n", len(files))
+	for _, filePath := range files {
+		fmt.Fprintf(status, "   %s\n", filePath)
+	}
+	fmt.Fprintln(status, "Remove the secrets, then 'git add' these files again.")
+	fmt.Fprintln(status, "")
+	
+	if !partial {
+		fmt.Fprintln(status, "Commit aborted.")
+		return errCommitBlocked
+	}
+	
+	remaining, err := differ.HasStagedChanges()
+	if err != nil {
+		return err
+	}
+	if !remaining {
+		fmt.Fprintln(status, "Nothing safe left to commit - commit aborted.")
+		return errCommitBlocked
+	}
+	
+	fmt.Fprintln(status, "⚠️  Partial mode: committing the remaining staged files only.")
+	return nil
+}
+
+// writeReport prints the JSON report when requested; text output is handled inline
+func writeReport(options scanOptions, findings []scanner.Finding) error {
+	if options.format != "json" {
+		return nil
+	}
+	return annotateReport(report.New("staged", findings)).Write(os.Stdout)
+}
+
+// printOptions controls how findings are printed as text
+type printOptions struct {
+	// context prints the lines around each finding (--context); nil disables it
+	context *sourceContext
+	
+	// maxFindings caps the findings printed (--max-findings); 0 prints all
+	maxFindings int
+	
+	// summary leads with a count per rule and gives each rule's advice once
+	// rather than under every finding, to keep hook messages short
+	summary bool
+}
+
+// printFindings renders findings once per secret, listing repeat locations
+func printFindings(findings []scanner.Finding, output printOptions) {
+	findings = append([]scanner.Finding(nil), findings...)
+	scanner.SortFindings(findings)
+	output.context.related(findings)
+	groups := report.GroupByFingerprint(findings)
+	shown := limitGroups(groups, output.maxFindings)
+	if !output.summary || len(groups) < 2 {
+		for _, group := range shown {
+			printFinding(group[0], output.context, group[1:]...)
+		}
+		printTruncation(len(shown), len(groups))
+		return
 	}
 	
-	fmt.Println("Commit aborted.")
-	return fmt.Errorf("secrets detected - commit blocked")
-}
\ No newline at end of file
+	printRuleSummary(groups)
+	for _, group := range shown {
+		printFindingLocation(group[0], output.context, group[1:]...)
+		fmt.Println()
+	}
+	printTruncation(len(shown), len(groups))
+	printRuleAdvice(shown)
+}
+
+// limitGroups keeps the first max groups of findings; 0 keeps all
+func limitGroups(groups [][]scanner.Finding, max int) [][]scanner.Finding {
+	if max > 0 && len(groups) > max {
+		return groups[:max]
+	}
+	return groups
+}
+
+// printTruncation says that findings were left out of the text output, so a
+// short report isn't mistaken for a complete one
+func printTruncation(shown, total int) {
+	if shown < total {
+		fmt.Printf("⚠️  Output truncated: showing %d of %d finding(s) (--max-findings or settings.max_findings).\n   Use --format json for the full list.\n\n", shown, total)
+	}
+}
+
+// printFinding renders a single finding in the standard report layout, plus
+// any other places the same secret was found
+func printFinding(finding scanner.Finding, context *sourceContext, duplicates ...scanner.Finding) {
+	printFindingLocation(finding, context, duplicates...)
+	fmt.Printf("Advice   : %s\n", finding.Advice)
+	printRemediation(finding.Remediation)
+	fmt.Printf("More     : run 'secretlint explain %s'\n", finding.RuleID)
+	fmt.Println()
+}
+
+// printFindingLocation renders what is particular to a finding: where it
+// is, the masked secret and who owns it, without the rule's advice
+func printFindingLocation(finding scanner.Finding, context *sourceContext, duplicates ...scanner.Finding) {
+	fmt.Printf("Rule     : %s\n", finding.RuleID)
+	if commit := finding.Commit; commit != nil {
+		fmt.Printf("Commit   : %.12s %s (%s)\n", commit.SHA, commit.Subject, commit.Author)
+	}
+	fmt.Printf("File     : %s\n", finding.Location())
+	seen := map[string]bool{finding.Location(): true}
+	for _, duplicate := range duplicates {
+		if location := duplicate.Location(); !seen[location] {
+			seen[location] = true
+			fmt.Printf("Also in  : %s\n", location)
+		}
+	}
+	if finding.KeyPath != "" {
+		fmt.Printf("Key      : %s\n", finding.KeyPath)
+	}
+	if finding.Cell != "" {
+		fmt.Printf("Cell     : %s\n", finding.Cell)
+	}
+	if !finding.Blocking() {
+		fmt.Printf("Severity : %s (not blocking)\n", finding.Severity)
+	}
+	if finding.PolicyViolation {
+		fmt.Printf("Policy   : inline suppression ignored; %s is mandatory under org policy\n", finding.RuleID)
+	}
+	fmt.Printf("Snippet  : %s\n", finding.DisplaySecret())
+	context.print(finding)
+	fmt.Printf("FP       : %s\n", finding.Fingerprint())
+	printOwnership(finding.Owners, finding.LastTouchedBy)
+}
+
+// printRemediation renders the provider-specific revoke/rotate guidance of a
+// finding. Doc links are left to 'secretlint explain' to keep reports short.
+func printRemediation(remediation scanner.Remediation) {
+	if remediation.RevokeURL != "" {
+		fmt.Printf("Revoke   : %s\n", remediation.RevokeURL)
+	}
+	if remediation.RotateCommand != "" {
+		fmt.Printf("Rotate   : %s\n", remediation.RotateCommand)
+	}
+}
+
+
+// collectStagedFindings scans the staged changes without printing a report,
+// for commands that act on findings rather than display them
+func collectStagedFindings() (*scanner.GitDiffer, []scanner.Finding, error) {
+	differ := scanner.NewGitDiffer()
+	if !differ.IsInGitRepo() {
+		return nil, nil, fmt.Errorf("not in a git repository")
+	}
+	
+	lines, err := differ.GetStagedChanges()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get staged changes: %w", err)
+	}
+	
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return nil, nil, err
+	}
+	secretScanner.UseIndex()
+	return differ, scanStagedLines(secretScanner, differ, lines, nil), nil
+}
+
+// scanSensitivePaths flags credential files by name at the configured severity
+func scanSensitivePaths(cfg *config.Config, secretScanner *scanner.SecretScanner, files []string) []scanner.Finding {
+	severity, ok := filenameSeverity(cfg)
+	if !ok {
+		return nil
+	}
+	return secretScanner.ScanPaths(files, severity)
+}
+
+// filenameSeverity is the severity of SENSITIVE_FILE findings; ok is false
+// when filenames.severity turns them off
+func filenameSeverity(cfg *config.Config) (severity string, ok bool) {
+	switch cfg.Settings.Filenames.Severity {
+	case "off":
+		return "", false
+	case "":
+		return scanner.SeverityError, true
+	}
+	return cfg.Settings.Filenames.Severity, true
+}
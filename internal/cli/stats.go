@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/fleet"
+	"secretlint/internal/scanner"
+	"secretlint/internal/store"
+)
+
+// recordScan adds a diff scan to the findings database when the store is
+// enabled. Recording is best effort: a broken database never blocks a commit.
+func recordScan(cfg *config.Config, differ *scanner.GitDiffer, scope string, lines []scanner.DiffLine, findings []scanner.Finding) {
+	saveScan(cfg, differ, func(db *store.Store) {
+		db.Record(scope, countFiles(lines), findings, time.Now().UTC())
+	})
+}
+
+// recordFullScan adds a scan of whole files to the findings database, which
+// resolves the open findings in them that weren't detected again
+func recordFullScan(cfg *config.Config, differ *scanner.GitDiffer, scope string, files []string, findings []scanner.Finding) {
+	saveScan(cfg, differ, func(db *store.Store) {
+		db.RecordFiles(scope, files, findings, time.Now().UTC())
+	})
+}
+
+// saveScan opens the findings database when the store is enabled, lets
+// record add to it and saves it, warning rather than failing
+func saveScan(cfg *config.Config, differ *scanner.GitDiffer, record func(db *store.Store)) {
+	if !cfg.Settings.Store.Enabled {
+		return
+	}
+
+	db, err := openStore(cfg, differ)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not record scan: %v\n", err)
+		return
+	}
+	record(db)
+	if err := db.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not record scan: %v\n", err)
+	}
+}
+
+// openStore opens the findings database configured by settings.store: the
+// repository's .git/secretlint/findings.json, or its rows in a SQLite or
+// Postgres database
+func openStore(cfg *config.Config, differ *scanner.GitDiffer) (*store.Store, error) {
+	settings := cfg.Settings.Store
+	if settings.Backend == "" || settings.Backend == "file" {
+		gitDir, err := differ.GitDir()
+		if err != nil {
+			return nil, err
+		}
+		return store.Open(store.Path(gitDir))
+	}
+	repo := settings.Repo
+	if repo == "" {
+		repo = fleet.RepoName(repoIdentity(differ))
+	}
+	backend, err := sharedStore(settings, repo)
+	if err != nil {
+		return nil, err
+	}
+	return store.OpenBackend(backend)
+}
+
+// sharedStore returns repo's rows in the SQLite or Postgres findings
+// database; the default SQLite file lives in the repository's git directory
+func sharedStore(settings config.StoreSettings, repo string) (store.Backend, error) {
+	dsn := settings.ConnString()
+	if settings.Backend == "postgres" {
+		return store.NewPostgres(dsn, repo), nil
+	}
+	if dsn == "" {
+		gitDir, err := scanner.NewGitDiffer().GitDir()
+		if err != nil {
+			return nil, fmt.Errorf("settings.store.dsn is needed outside a git repository: %w", err)
+		}
+		dsn = filepath.Join(gitDir, "secretlint", "findings.db")
+	}
+	return store.NewSQLite(dsn, repo), nil
+}
+
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	days := flags.Int("days", 90, "Only include activity from the last N days")
+	period := flags.String("period", "week", "Trend bucket size: day, week or month")
+	format := flags.String("format", "text", "Output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	periods := map[string]time.Duration{
+		"day":   24 * time.Hour,
+		"week":  7 * 24 * time.Hour,
+		"month": 30 * 24 * time.Hour,
+	}
+	bucket, ok := periods[*period]
+	if !ok {
+		return fmt.Errorf("unsupported period %q (supported: day, week, month)", *period)
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: text, json)", *format)
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	db, err := openStore(cfg, scanner.NewGitDiffer())
+	if err != nil {
+		return err
+	}
+	if len(db.Scans) == 0 {
+		fmt.Println("📭 No scans recorded yet.")
+		fmt.Println("Enable 'settings.store.enabled' in .secretlintrc.yml to track findings over time.")
+		return nil
+	}
+
+	stats := db.Stats(time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -*days), bucket)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	}
+
+	fmt.Printf("📊 Secretlint stats (last %d days)\n\n", *days)
+	fmt.Printf("Scans      : %d (%d with findings)\n", stats.Scans, stats.Blocked)
+	fmt.Printf("Open       : %d\n", stats.Open)
+	fmt.Printf("Resolved   : %d\n", stats.Resolved)
+	fmt.Printf("Recurring  : %d\n", stats.Recurring)
+	if stats.Resolved > 0 {
+		fmt.Printf("Mean time to resolve: %.1fh\n", stats.MeanHoursToResolve)
+	}
+
+	if len(stats.Periods) > 0 {
+		fmt.Printf("\n%-12s %5s %9s %10s\n", *period+" of", "new", "resolved", "recurring")
+		for _, p := range stats.Periods {
+			fmt.Printf("%-12s %5d %9d %10d\n", p.Start.Format("2006-01-02"), p.New, p.Resolved, p.Recurring)
+		}
+	}
+
+	if len(stats.TopRules) > 0 {
+		fmt.Println("\nTop rules:")
+		for _, rule := range stats.TopRules {
+			fmt.Printf("   %-24s %d\n", rule.RuleID, rule.Count)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"secretlint/internal/scanner"
+)
+
+// ruleCount is how many distinct secrets one rule found at one severity
+type ruleCount struct {
+	ruleID   string
+	name     string
+	blocking bool
+	count    int
+}
+
+// countByRule tallies groups of findings per rule, blocking rules first and
+// then the most frequent
+func countByRule(groups [][]scanner.Finding) []ruleCount {
+	var counts []ruleCount
+	index := make(map[string]int)
+	for _, group := range groups {
+		finding := group[0]
+		key := fmt.Sprintf("%s/%t", finding.RuleID, finding.Blocking())
+		i, ok := index[key]
+		if !ok {
+			i = len(counts)
+			index[key] = i
+			counts = append(counts, ruleCount{ruleID: finding.RuleID, name: finding.RuleName, blocking: finding.Blocking()})
+		}
+		counts[i].count++
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].blocking != counts[j].blocking {
+			return counts[i].blocking
+		}
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].ruleID < counts[j].ruleID
+	})
+	return counts
+}
+
+// printRuleSummary leads a hook report with one line per rule, so a commit
+// with dozens of findings shows at a glance what kinds of secrets it holds
+func printRuleSummary(groups [][]scanner.Finding) {
+	fmt.Println("Summary by rule:")
+	for _, rule := range countByRule(groups) {
+		if rule.blocking {
+			fmt.Printf("  ⛔ %-24s %3d  %s\n", rule.ruleID, rule.count, rule.name)
+		} else {
+			fmt.Printf("  ⚠️  %-24s %3d  %s (not blocking)\n", rule.ruleID, rule.count, rule.name)
+		}
+	}
+	fmt.Println()
+}
+
+// printRuleAdvice gives the advice and remediation of each rule among the
+// findings once, in place of repeating them under every finding
+func printRuleAdvice(groups [][]scanner.Finding) {
+	fmt.Println("Advice by rule:")
+	seen := make(map[string]bool)
+	for _, rule := range countByRule(groups) {
+		if seen[rule.ruleID] {
+			continue
+		}
+		seen[rule.ruleID] = true
+		for _, group := range groups {
+			if finding := group[0]; finding.RuleID == rule.ruleID {
+				fmt.Printf("Rule     : %s\n", rule.ruleID)
+				fmt.Printf("Advice   : %s\n", finding.Advice)
+				printRemediation(finding.Remediation)
+				fmt.Printf("More     : run 'secretlint explain %s'\n", finding.RuleID)
+				fmt.Println()
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"secretlint/internal/config"
+	"secretlint/internal/update"
+)
+
+// updateCheckTimeout keeps the weekly version check from delaying commits
+const updateCheckTimeout = 3 * time.Second
+
+// releaseSource returns the API and repository releases come from
+func releaseSource(cfg *config.Config) (string, string) {
+	apiURL, repo := cfg.Settings.Update.APIURL, cfg.Settings.Update.Repo
+	if apiURL == "" {
+		apiURL = update.DefaultAPIURL
+	}
+	if repo == "" {
+		repo = update.DefaultRepo
+	}
+	return apiURL, repo
+}
+
+// runSelfUpdate replaces the running binary with the latest release after
+// verifying its checksum
+func runSelfUpdate(args []string) error {
+	flags := flag.NewFlagSet("self-update", flag.ContinueOnError)
+	check := flags.Bool("check", false, "Only report whether a newer release exists")
+	force := flags.Bool("force", false, "Install the latest release even if it isn't newer (e.g. over a development build)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if err := requireOnline(cfg, "self-update"); err != nil {
+		return err
+	}
+
+	apiURL, repo := releaseSource(cfg)
+	release, err := update.Latest(apiURL, repo, 30*time.Second)
+	if err != nil {
+		return err
+	}
+	newer := update.Newer(release.Tag, Version)
+	if !newer && !*force {
+		if Version == "dev" {
+			fmt.Printf("ℹ️  This is a development build; the latest release is %s (use --force to install it)\n", release.Tag)
+		} else {
+			fmt.Printf("✅ secretlint %s is up to date\n", Version)
+		}
+		return nil
+	}
+	if *check {
+		fmt.Printf("💡 secretlint %s is available (you have %s): %s\n", release.Tag, Version, release.URL)
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+
+	fmt.Printf("📦 Downloading secretlint %s for %s/%s...\n", release.Tag, runtime.GOOS, runtime.GOARCH)
+	data, err := release.Download(runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+	if err := update.Replace(executable, data); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Updated %s from %s to %s (checksum verified)\n", executable, Version, release.Tag)
+	fmt.Println("   Hooks that run another copy of secretlint need 'secretlint init' to point at this one.")
+	return nil
+}
+
+// noticeNewVersion runs the opt-in version check once a week and prints a
+// single upgrade hint. It never fails the command it follows.
+func noticeNewVersion() {
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil || !cfg.Settings.Update.Check || cfg.Offline() {
+		return
+	}
+	statePath, err := update.StatePath()
+	if err != nil {
+		return
+	}
+	state := update.LoadState(statePath)
+	now := time.Now()
+	if !state.Due(now) {
+		return
+	}
+
+	// Record the attempt even if it fails, so an unreachable API is retried
+	// next week rather than on every commit
+	state.CheckedAt = now
+	apiURL, repo := releaseSource(cfg)
+	if release, err := update.Latest(apiURL, repo, updateCheckTimeout); err == nil {
+		state.Latest = release.Tag
+	}
+	state.Save(statePath)
+
+	if update.Newer(state.Latest, Version) {
+		fmt.Fprintf(os.Stderr, "💡 secretlint %s is available (you have %s); run 'secretlint self-update'\n", state.Latest, Version)
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"secretlint/internal/scanner"
+)
+
+// scanPendingChanges is the staged scan of a Mercurial or Jujutsu
+// repository. Neither has an index, so it scans the working copy changes
+// the next commit would record, and run from their commit hooks it blocks
+// the commit like the git pre-commit hook.
+func scanPendingChanges(options scanOptions, vcs scanner.VCS) error {
+	status := options.status
+	if options.interactive || options.partial {
+		return fmt.Errorf("--interactive and --partial need a git repository (this is a %s repository)", vcs.Name())
+	}
+
+	lines, err := vcs.PendingChanges()
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		fmt.Fprintln(status, "✅ No uncommitted changes to scan")
+		return writeReport(options, nil)
+	}
+	fmt.Fprintf(status, "📄 Found %d added lines to scan in the %s working copy\n", len(lines), vcs.Name())
+	options.stats.files = countFiles(lines)
+
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return err
+	}
+	findings := secretScanner.ScanLines(lines)
+	options.stats.addFindings(findings)
+	if len(findings) == 0 {
+		fmt.Fprintln(status, "✅ No secrets detected in uncommitted changes")
+		return writeReport(options, nil)
+	}
+
+	if len(scanner.BlockingFindings(findings)) == 0 {
+		fmt.Fprintf(status, "\n⚠️  %d warning(s) in uncommitted changes (not blocking):\n\n", len(findings))
+		if options.format != "json" {
+			printFindings(findings, options.output)
+		}
+		return writeReport(options, findings)
+	}
+
+	fmt.Fprintf(status, "\n⛔ %d secret(s) detected in uncommitted changes:\n\n", len(findings))
+	if options.format == "json" {
+		if err := writeReport(options, findings); err != nil {
+			return err
+		}
+	} else {
+		printFindings(findings, options.output)
+	}
+	fmt.Fprintln(status, "Commit aborted.")
+	return errCommitBlocked
+}
+
+// scanVCSHistory is scanRepoHistory for a Mercurial or Jujutsu repository.
+// revs are revsets; the history cache, ownership and the Rego policy, which
+// read git, are left out, and the working copy stands in for HEAD.
+func scanVCSHistory(status io.Writer, stats *scanStats, vcs scanner.VCS, revs []string, repo string, stopAt int) ([]scanner.Finding, map[string][]string, error) {
+	for _, rev := range revs {
+		if strings.HasPrefix(rev, "-") {
+			return nil, nil, fmt.Errorf("--first-parent and --refs need a git repository (this is a %s repository)", vcs.Name())
+		}
+	}
+
+	lines, err := vcs.HistoryChanges(revs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	label := vcs.Name() + " history"
+	if repo != "" {
+		label = repo + " history"
+	}
+	fmt.Fprintf(status, "📜 Found %d added lines across %s\n", len(lines), label)
+
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return nil, nil, err
+	}
+	findings, stopped := scanHistoryLines(secretScanner, lines, stopAt, nil)
+	if stopped {
+		fmt.Fprintf(status, "⚠️  Stopped scanning %s after %d finding(s) (--stop-at-max); older commits were not scanned\n", label, len(findings))
+	}
+	stats.files += countFiles(lines)
+	stats.addFindings(findings)
+	for i := range findings {
+		findings[i].Repo = repo
+	}
+	return findings, workingCopyLocations(findings, repo), nil
+}
+
+// workingCopyLocations reports where each finding's secret still appears
+// in the working copy, as "file:line" entries keyed by fingerprint. Only
+// the files the secrets were added to are searched, so a secret that moved
+// to another file counts as removed.
+func workingCopyLocations(findings []scanner.Finding, repo string) map[string][]string {
+	locations := make(map[string][]string)
+	contents := make(map[string][]string)
+	for _, finding := range findings {
+		fingerprint := finding.Fingerprint()
+		if _, ok := locations[fingerprint]; ok {
+			continue
+		}
+		lines, ok := contents[finding.FilePath]
+		if !ok {
+			if data, err := os.ReadFile(finding.FilePath); err == nil {
+				lines = strings.Split(string(data), "\n")
+			}
+			contents[finding.FilePath] = lines
+		}
+		for i, line := range lines {
+			if strings.Contains(line, finding.Secret) {
+				location := fmt.Sprintf("%s:%d", finding.Path(), i+1)
+				if repo != "" {
+					location = repo + ":" + location
+				}
+				locations[fingerprint] = append(locations[fingerprint], location)
+			}
+		}
+	}
+	return locations
+}
+
+// otherVCS returns the Mercurial or Jujutsu repository around the working
+// directory, or nil in a git repository or outside any
+func otherVCS() scanner.VCS {
+	if vcs := scanner.DetectVCS(); vcs != nil && vcs.Name() != "git" {
+		return vcs
+	}
+	return nil
+}
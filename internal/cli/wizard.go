@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// initAnswers are the choices 'secretlint init' sets up; the wizard asks for
+// them, plain 'init' uses the defaults
+type initAnswers struct {
+	preCommit bool
+	prePush   bool
+
+	// strictness picks a config template: strict, balanced or relaxed
+	strictness string
+
+	// ci is the platform to generate a pipeline for: github, gitlab or empty
+	ci string
+}
+
+func defaultInitAnswers() initAnswers {
+	return initAnswers{preCommit: true, strictness: "balanced"}
+}
+
+// initChoice is one numbered answer to a wizard question
+type initChoice struct {
+	value       string
+	description string
+}
+
+// askInitQuestions runs the 'init --interactive' wizard
+func askInitQuestions(input io.Reader) (initAnswers, error) {
+	reader := bufio.NewReader(input)
+	answers := defaultInitAnswers()
+
+	fmt.Println("\n🧭 A few questions to tailor the setup (press Enter for the default)")
+
+	hooks, err := askChoice(reader, "Which git hooks should scan for secrets?", []initChoice{
+		{"pre-commit", "pre-commit only: scan staged changes (recommended)"},
+		{"both", "pre-commit and pre-push: also block pushes of commits with secrets"},
+		{"pre-push", "pre-push only: scan commits before they leave the machine"},
+		{"none", "no hooks; run 'secretlint scan' yourself or in CI"},
+	})
+	if err != nil {
+		return answers, err
+	}
+	answers.preCommit = hooks == "both" || hooks == "pre-commit"
+	answers.prePush = hooks == "both" || hooks == "pre-push"
+
+	if answers.strictness, err = askChoice(reader, "How strict should scans be?", []initChoice{
+		{"balanced", "balanced: errors block, warnings are reported (recommended)"},
+		{"strict", "strict: warnings block too; audit log and push quarantine on"},
+		{"relaxed", "relaxed: hooks only report, so nobody is blocked locally; CI still fails"},
+	}); err != nil {
+		return answers, err
+	}
+
+	ci, err := askChoice(reader, "Which CI platform should run a full scan?", []initChoice{
+		{"none", "none"},
+		{"github", "GitHub Actions"},
+		{"gitlab", "GitLab CI"},
+	})
+	if err != nil {
+		return answers, err
+	}
+	if ci != "none" {
+		answers.ci = ci
+	}
+	fmt.Println()
+	return answers, nil
+}
+
+// askChoice prompts until one of the numbered choices is picked; the first
+// choice is the default
+func askChoice(reader *bufio.Reader, question string, choices []initChoice) (string, error) {
+	fmt.Printf("\n%s\n", question)
+	for i, choice := range choices {
+		fmt.Printf("  %d) %s\n", i+1, choice.description)
+	}
+	for {
+		answer, ok := prompt(reader, fmt.Sprintf("Choice [1-%d, default 1] > ", len(choices)))
+		if !ok {
+			return "", fmt.Errorf("setup cancelled")
+		}
+		if answer == "" {
+			return choices[0].value, nil
+		}
+		for i, choice := range choices {
+			if answer == fmt.Sprint(i+1) || answer == choice.value {
+				return choice.value, nil
+			}
+		}
+		fmt.Printf("Please enter a number from 1 to %d\n", len(choices))
+	}
+}
+
+// tailorConfig adjusts the default config to the chosen strictness
+func (a initAnswers) tailorConfig(content string) string {
+	switch a.strictness {
+	case "strict":
+		content = strings.Replace(content, "    quarantine: false", "    quarantine: true", 1)
+		content = strings.Replace(content, "  audit:\n    enabled: false", "  audit:\n    enabled: true", 1)
+		content += `
+# Strict setup from 'secretlint init --interactive': warnings block too
+fail_on: warning
+`
+	case "relaxed":
+		content = strings.Replace(content, "  filenames:\n    severity: error", "  filenames:\n    severity: warning", 1)
+		content = strings.Replace(content, "    on_error: block", "    on_error: allow", 1)
+		content += `
+# Relaxed setup from 'secretlint init --interactive': git hooks report
+# findings without blocking, and let commits through if secretlint fails;
+# CI and manual scans still fail on errors
+profiles:
+  hook:
+    fail_on: never
+`
+	}
+	return content
+}
+
+// ciFile returns where the pipeline for the chosen CI platform goes and its content
+func (a initAnswers) ciFile() (string, string) {
+	switch a.ci {
+	case "github":
+		return filepath.Join(".github", "workflows", "secretlint.yml"), `# Generated by 'secretlint init --interactive'
+name: secretlint
+
+on: [push, pull_request]
+
+jobs:
+  secretlint:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+      - run: go install github.com/ZichenYuan/secretlint/cmd/secretlint@latest
+      - run: secretlint scan --all
+`
+	case "gitlab":
+		return filepath.Join(".gitlab", "secretlint.yml"), `# Generated by 'secretlint init --interactive'. Add to .gitlab-ci.yml:
+#   include:
+#     - local: .gitlab/secretlint.yml
+secretlint:
+  image: golang:latest
+  script:
+    - go install github.com/ZichenYuan/secretlint/cmd/secretlint@latest
+    - secretlint scan --all
+`
+	}
+	return "", ""
+}
+
+// writeCIFile generates the pipeline for the chosen CI platform
+func writeCIFile(answers initAnswers) (string, error) {
+	path, content := answers.ciFile()
+	if path == "" {
+		return "", nil
+	}
+	if err := initJournal.mkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := writeFileIfNotExists(path, content); err != nil {
+		return "", err
+	}
+	if answers.ci == "gitlab" {
+		fmt.Printf("💡 Include %s from your .gitlab-ci.yml to run it\n", path)
+	}
+	return path, nil
+}
@@ -0,0 +1,680 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"secretlint/internal/regexcache"
+	"secretlint/internal/signature"
+)
+
+// DefaultConfigFile is the configuration file created by 'secretlint init'
+const DefaultConfigFile = ".secretlintrc.yml"
+
+// OfflineEnv turns on offline mode when set to any value; --offline sets it
+const OfflineEnv = "SECRETLINT_OFFLINE"
+
+// ProfileEnv names the profile to apply; --profile sets it
+const ProfileEnv = "SECRETLINT_PROFILE"
+
+// HookProfile is applied in git hooks when no profile is selected explicitly
+const HookProfile = "hook"
+
+// ruleIDPattern is the form of rule IDs, which inline suppressions and
+// rules: entries name
+var ruleIDPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// Config mirrors the structure of .secretlintrc.yml. It is plain data,
+// safe to share between goroutines as long as none of them modifies it.
+type Config struct {
+	// Extends names a base config (file path or http(s) URL), typically an
+	// org-wide policy; settings here override it
+	Extends string `yaml:"extends"`
+	
+	// ExtendsPublicKey is a minisign public key; when set, every extended
+	// config must come with a valid <source>.minisig signature
+	ExtendsPublicKey string `yaml:"extends_public_key"`
+	
+	// ExtendsTTL is how long a fetched remote config is used before refetching
+	ExtendsTTL string `yaml:"extends_ttl"`
+	
+	// Origins lists the files the config was merged from, base first
+	Origins []Origin `yaml:"-"`
+	
+	// Rules enables or disables rules by ID; rules not listed stay enabled
+	Rules map[string]bool `yaml:"rules"`
+	
+	// RuleOptions tune the token length and charset of built-in rules by ID
+	RuleOptions map[string]RuleOptions `yaml:"rule_options"`
+	
+	// CustomRules are org-specific detectors combining a pattern with
+	// keyword, entropy and file conditions
+	CustomRules []CustomRule `yaml:"custom_rules"`
+	
+	Settings Settings `yaml:"settings"`
+	
+	// Packs enables optional rule packs, e.g. "pii"
+	Packs []string `yaml:"packs"`
+	
+	// Allowlist drops known false positives, e.g. tuning imported from gitleaks
+	Allowlist []AllowlistEntry `yaml:"allowlist"`
+	
+	// Policy is only honored from extended configs, so a repository can't relax it
+	Policy Policy `yaml:"policy"`
+	
+	// FailOn is the lowest severity that fails a scan: error (default),
+	// warning, or never to only report
+	FailOn string `yaml:"fail_on"`
+	
+	// Profiles are named overlays, e.g. a lenient hook and a strict ci profile
+	Profiles map[string]Profile `yaml:"profiles"`
+	
+	// Profile names the applied profile and Format is its output format
+	Profile string `yaml:"-"`
+	Format  string `yaml:"-"`
+
+	// ReportOnly are the rules the applied profile only reports; when its
+	// report_only_until has passed they are ExpiredReportOnly instead
+	ReportOnly        []string `yaml:"-"`
+	ExpiredReportOnly []string `yaml:"-"`
+	ReportOnlyUntil   string   `yaml:"-"`
+}
+
+// Profile adjusts the config for one context (hook, ci, audit, ...)
+type Profile struct {
+	// FailOn overrides the top-level fail_on
+	FailOn string `yaml:"fail_on"`
+	
+	// Rules are merged over the top-level rules
+	Rules map[string]bool `yaml:"rules"`
+	
+	// Packs are enabled in addition to the top-level packs
+	Packs []string `yaml:"packs"`
+	
+	// Format is the scan output format unless --format is given: text or json
+	Format string `yaml:"format"`
+	
+	// ReportOnly rules are reported as warnings under this profile, e.g. new
+	// rules baking in hooks while CI keeps blocking on them. From the day
+	// after ReportOnlyUntil (YYYY-MM-DD), if set, they block again.
+	ReportOnly      []string `yaml:"report_only"`
+	ReportOnlyUntil string   `yaml:"report_only_until"`
+}
+
+// Policy holds org-wide constraints on local configuration
+type Policy struct {
+	// MandatoryRules can't be disabled in config or suppressed inline
+	MandatoryRules []string `yaml:"mandatory_rules"`
+	
+	// Rego is a policy (package secretlint) evaluated with opa over each
+	// scan's findings; it can reclassify severities and waive findings
+	Rego string `yaml:"rego"`
+	
+	// RuleOverrides change the severity, advice and remediation links of
+	// rules by ID, e.g. to point at the internal vault runbook, without
+	// redefining them
+	RuleOverrides map[string]RuleOverride `yaml:"rule_overrides"`
+	
+	// Source is the extends: location the policy came from
+	Source string `yaml:"-"`
+}
+
+// RuleOptions tighten or loosen a built-in rule without replacing its
+// pattern; zero fields keep the rule's own values
+type RuleOptions struct {
+	// MinLength and MaxLength bound the length of the token after its prefix
+	MinLength int `yaml:"min_length"`
+	MaxLength int `yaml:"max_length"`
+	
+	// Charset replaces the token's character class, e.g. "A-Za-z0-9_-"
+	Charset string `yaml:"charset"`
+}
+
+// CustomRule is a detector that reports a pattern's matches only when all of
+// its conditions hold
+type CustomRule struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Pattern     string `yaml:"pattern"`
+	Description string `yaml:"description"`
+	Advice      string `yaml:"advice"`
+	
+	// Severity is error (default) or warning
+	Severity string `yaml:"severity"`
+	
+	// Keywords must appear, case-insensitively, within Within lines of the
+	// match; Within 0 means on the matched line
+	Keywords []string `yaml:"keywords"`
+	Within   int      `yaml:"within"`
+	
+	// MinEntropy is the least Shannon entropy of the secret, in bits per
+	// character; random 32-character tokens score around 4.5
+	MinEntropy float64 `yaml:"min_entropy"`
+	
+	// Paths limit the rule to files whose name or path matches a glob, e.g. "*.yml"
+	Paths []string `yaml:"paths"`
+	
+	// NotPrecededBy and NotFollowedBy drop matches with one of these
+	// strings right before or after them, in place of lookarounds
+	NotPrecededBy []string `yaml:"not_preceded_by"`
+	NotFollowedBy []string `yaml:"not_followed_by"`
+	
+	// Engine "regexp2" runs the pattern with a backtracking engine that
+	// supports lookarounds and backreferences; each search of a line is cut
+	// off after 100ms. The default, "re2", is Go's regexp.
+	Engine string `yaml:"engine"`
+}
+
+// AllowlistEntry drops findings matching any of its regexes, stopwords or paths
+type AllowlistEntry struct {
+	Description string `yaml:"description,omitempty"`
+	
+	// Rules limits the entry to these rule IDs; empty applies it to all rules
+	Rules []string `yaml:"rules,omitempty"`
+	
+	// Regexes are matched against the target: secret (default), match or line
+	Regexes []string `yaml:"regexes,omitempty"`
+	Target  string   `yaml:"target,omitempty"`
+	
+	// Stopwords drop findings whose secret contains one of them, ignoring case
+	Stopwords []string `yaml:"stopwords,omitempty"`
+	
+	// Paths are regexes matched against the file path
+	Paths []string `yaml:"paths,omitempty"`
+}
+
+// RuleOverride replaces parts of a rule's output; empty fields keep the
+// rule's own
+type RuleOverride struct {
+	// Severity is error or warning
+	Severity string `yaml:"severity"`
+	Advice   string `yaml:"advice"`
+	
+	RevokeURL     string   `yaml:"revoke_url"`
+	RotateCommand string   `yaml:"rotate_command"`
+	DocLinks      []string `yaml:"doc_links"`
+}
+
+// IsMandatory reports whether the policy protects a rule
+func (p Policy) IsMandatory(ruleID string) bool {
+	for _, id := range p.MandatoryRules {
+		if id == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleEnabled reports whether a rule is enabled; unlisted rules are
+func (c *Config) RuleEnabled(ruleID string) bool {
+	enabled, listed := c.Rules[ruleID]
+	return !listed || enabled
+}
+
+// Settings holds the global settings block
+type Settings struct {
+	// Verbose reports more detail, such as how long each staged file took to scan
+	Verbose bool `yaml:"verbose"`
+	
+	// AbsolutePaths shows finding paths as absolute paths rather than
+	// relative to the repository root; matching is unaffected
+	AbsolutePaths bool `yaml:"absolute_paths"`
+	
+	// FailOnDetection false reports findings without failing the scan, like
+	// fail_on: never; unset means true, and fail_on takes precedence
+	FailOnDetection *bool `yaml:"fail_on_detection"`
+	
+	// MinLength drops detected values shorter than this many characters
+	MinLength int `yaml:"min_length"`
+	
+	Hook  HookSettings  `yaml:"hook"`
+	Store StoreSettings `yaml:"store"`
+	Audit AuditSettings `yaml:"audit"`
+	
+	// Events posts a scan-completed event to URL after every scan
+	Events EventSettings `yaml:"events"`
+	
+	// Archives unpacks zip/tar/gz files in memory and scans their text entries
+	Archives ArchiveSettings `yaml:"archives"`
+	
+	// Filenames flags committed credential files (id_rsa, *.pem, .npmrc, ...)
+	Filenames FilenameSettings `yaml:"filenames"`
+	
+	// Symlinks sets which symlinks --all scans follow
+	Symlinks SymlinkSettings `yaml:"symlinks"`
+	
+	// Offline forbids every network access; features that need it fail
+	Offline bool `yaml:"offline"`
+	
+	// Telemetry sends anonymous usage counts; off unless enabled
+	Telemetry TelemetrySettings `yaml:"telemetry"`
+	
+	// Masking sets how secrets are hidden in output and reports
+	Masking MaskingSettings `yaml:"masking"`
+	
+	// MaxFindings caps the findings printed as text, e.g. in hook output; 0 prints all
+	MaxFindings int `yaml:"max_findings"`
+	
+	// Update configures 'secretlint self-update' and the opt-in version check
+	Update UpdateSettings `yaml:"update"`
+	
+	// References warns about credentials assigned literals in source files
+	References ReferenceSettings `yaml:"references"`
+	
+	// LFS scans the content of Git LFS objects instead of skipping their pointers
+	LFS LFSSettings `yaml:"lfs"`
+}
+
+// HookSettings controls behavior when running from the pre-commit hook
+type HookSettings struct {
+	// AutoUnstage unstages files with blocking findings
+	AutoUnstage bool `yaml:"auto_unstage"`
+	
+	// Quarantine offers to move commits with secrets off the branch being pushed
+	Quarantine bool `yaml:"quarantine"`
+	
+	// OnError decides a hook run where secretlint itself fails (a crash, a
+	// broken config, git errors): block (fail closed, the default) or allow
+	// (fail open, with a warning)
+	OnError string `yaml:"on_error"`
+	
+	// TimeBudget bounds how long the pre-commit hook scans, e.g. 2s. The
+	// diff is always scanned; staged archives that don't fit are scanned in
+	// the background and reported on the next commit. Empty means no limit.
+	TimeBudget string `yaml:"time_budget"`
+}
+
+// StoreSettings controls the findings database used by 'secretlint stats'
+type StoreSettings struct {
+	// Enabled records every scan in .git/secretlint/findings.json
+	Enabled bool `yaml:"enabled"`
+	
+	// Backend is file (default), sqlite or postgres; the SQL backends can
+	// be shared by many repositories and by fleet scans
+	Backend string `yaml:"backend"`
+	
+	// DSN is the SQLite database path (default .git/secretlint/findings.db)
+	// or the Postgres connection string; SECRETLINT_STORE_DSN overrides it
+	DSN string `yaml:"dsn"`
+	
+	// Repo names this repository's rows in a shared database; default the
+	// owner/name of the origin remote
+	Repo string `yaml:"repo"`
+}
+
+// StoreDSNEnv overrides settings.store.dsn, keeping database credentials
+// out of the committed config
+const StoreDSNEnv = "SECRETLINT_STORE_DSN"
+
+// ConnString returns the database DSN, from SECRETLINT_STORE_DSN if set
+func (s StoreSettings) ConnString() string {
+	if dsn := os.Getenv(StoreDSNEnv); dsn != "" {
+		return dsn
+	}
+	return s.DSN
+}
+
+// AuditSettings controls the hook decision log used to evidence control execution
+type AuditSettings struct {
+	// Enabled appends every hook run to .git/secretlint/audit.log
+	Enabled bool `yaml:"enabled"`
+	
+	// Endpoint receives the log from 'secretlint audit-log ship'
+	Endpoint string `yaml:"endpoint"`
+}
+
+// EventSettings configures scan-completed events for adoption metrics
+type EventSettings struct {
+	URL string `yaml:"url"`
+}
+
+// TelemetrySettings configures opt-in usage counts for internal rollouts
+type TelemetrySettings struct {
+	Enabled  bool   `yaml:"enabled"`
+	Endpoint string `yaml:"endpoint"`
+}
+
+// ArchiveSettings bounds archive scanning; zero limits use the defaults
+type ArchiveSettings struct {
+	Enabled bool `yaml:"enabled"`
+	
+	// MaxSizeMB caps the uncompressed bytes read from one archive
+	MaxSizeMB int `yaml:"max_size_mb"`
+	
+	// MaxDepth caps how deeply nested archives are opened
+	MaxDepth int `yaml:"max_depth"`
+}
+
+// FilenameSettings sets how sensitive file names are reported
+type FilenameSettings struct {
+	// Severity is "error" (default, blocks), "warning" (reported only) or "off"
+	Severity string `yaml:"severity"`
+}
+
+// SymlinkSettings controls how --all scans treat tracked symlinks
+type SymlinkSettings struct {
+	// Follow is "repo" (default: only links whose target is inside the
+	// repository), "never" or "always"
+	Follow string `yaml:"follow"`
+}
+
+// ReferenceSettings controls reference mode, which treats secret manager
+// references (op://, vault:) as the approved way to name a secret
+type ReferenceSettings struct {
+	// Enabled reports credential-looking names set to literals in source
+	// files as LITERAL_SECRET warnings
+	Enabled bool `yaml:"enabled"`
+}
+
+// LFSSettings controls scanning of files tracked with Git LFS
+type LFSSettings struct {
+	// Enabled reads the object behind an LFS pointer, from the local LFS
+	// store or with 'git lfs smudge', and scans it if it is text
+	Enabled bool `yaml:"enabled"`
+	
+	// MaxSizeMB skips larger objects; 0 uses the default of 10
+	MaxSizeMB int `yaml:"max_size_mb"`
+}
+
+// UpdateSettings locates secretlint releases; empty values use GitHub
+type UpdateSettings struct {
+	// Check looks for a newer release once a week and prints a hint
+	Check bool `yaml:"check"`
+	
+	// APIURL and Repo name a GitHub (Enterprise) API and repository with releases
+	APIURL string `yaml:"api_url"`
+	Repo   string `yaml:"repo"`
+}
+
+// MaskingSettings selects how secrets are shown
+type MaskingSettings struct {
+	// Strategy is "partial" (default), "full" or "hash"
+	Strategy string `yaml:"strategy"`
+	
+	// Reveal caps the characters partial masking shows (default 8); it never
+	// shows more than a quarter of a secret
+	Reveal int `yaml:"reveal"`
+}
+
+// Default returns the configuration used when no config file exists
+func Default() *Config {
+	return &Config{}
+}
+
+// OfflineForced reports whether offline mode was requested for this process
+// with --offline or SECRETLINT_OFFLINE
+func OfflineForced() bool {
+	return os.Getenv(OfflineEnv) != ""
+}
+
+// Offline reports whether network access is forbidden, by the config or the caller
+func (c *Config) Offline() bool {
+	return c.Settings.Offline || OfflineForced()
+}
+
+// Load reads the configuration file, falling back to defaults if it doesn't
+// exist. Configs named by extends: are loaded first and overridden by the
+// file itself; their policy is enforced on the result.
+func Load(configPath string) (*Config, error) {
+	return LoadWith(configPath, LoadOptions{})
+}
+
+// LoadOptions tune how extended configs are fetched
+type LoadOptions struct {
+	// Refresh re-fetches remote configs even when the cached copy is fresh,
+	// failing instead of falling back to the cache
+	Refresh bool
+}
+
+// LoadWith is Load with control over the remote config cache. The profile
+// named by SECRETLINT_PROFILE, if any, is applied before the policy check.
+func LoadWith(configPath string, options LoadOptions) (*Config, error) {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		// Repositories that also use the npm secretlint can share its config
+		jsPath := filepath.Join(filepath.Dir(configPath), JSConfigFile)
+		if _, statErr := os.Stat(jsPath); statErr == nil {
+			if data, err = readJSConfig(jsPath); err != nil {
+				return nil, err
+			}
+			configPath = jsPath
+		}
+	}
+	if err != nil {
+		if os.IsNotExist(err) && os.Getenv(ProfileEnv) == "" {
+			return Default(), nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+
+	local := Default()
+	if err := yaml.Unmarshal(data, local); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+
+	cfg := Default()
+	var origins []Origin
+	if local.Extends != "" {
+		loader := &extendsLoader{options: options, ttl: defaultExtendsTTL, offline: local.Offline()}
+		// The trust anchor only ever comes from the local file
+		if local.ExtendsPublicKey != "" {
+			if loader.key, err = signature.ParsePublicKey(local.ExtendsPublicKey); err != nil {
+				return nil, fmt.Errorf("invalid extends_public_key in %s: %w", configPath, err)
+			}
+		}
+		if local.ExtendsTTL != "" {
+			if loader.ttl, err = time.ParseDuration(local.ExtendsTTL); err != nil {
+				return nil, fmt.Errorf("invalid extends_ttl in %s: %w", configPath, err)
+			}
+		}
+		if cfg, err = loader.load(resolveExtends(configPath, local.Extends), 1); err != nil {
+			return nil, err
+		}
+		origins = loader.origins
+	}
+	// Overlay the local file: maps merge key by key, other set fields replace
+	policy := cfg.Policy
+	cfg.Policy = Policy{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+	cfg.Policy = policy
+	cfg.Origins = append(origins, Origin{Source: configPath})
+	
+	if name := os.Getenv(ProfileEnv); name != "" {
+		if err := cfg.applyProfile(name); err != nil {
+			return nil, fmt.Errorf("%w in %s", err, configPath)
+		}
+	}
+
+	for _, ruleID := range cfg.Policy.MandatoryRules {
+		if !cfg.RuleEnabled(ruleID) {
+			return nil, fmt.Errorf("policy violation: %s disables %s, which is mandatory under the policy from %s", configPath, ruleID, cfg.Policy.Source)
+		}
+	}
+
+	switch cfg.Settings.Filenames.Severity {
+	case "", "error", "warning", "off":
+	default:
+		return nil, fmt.Errorf("invalid filenames.severity %q in %s (use error, warning or off)", cfg.Settings.Filenames.Severity, configPath)
+	}
+	
+	for ruleID, options := range cfg.RuleOptions {
+		if cfg.Policy.IsMandatory(ruleID) {
+			return nil, fmt.Errorf("policy violation: %s tunes rule_options.%s, which is mandatory under the policy from %s", configPath, ruleID, cfg.Policy.Source)
+		}
+		if options.MinLength < 0 || options.MaxLength < 0 {
+			return nil, fmt.Errorf("invalid rule_options.%s in %s: lengths must not be negative", ruleID, configPath)
+		}
+		if options.MaxLength > 0 && options.MinLength > options.MaxLength {
+			return nil, fmt.Errorf("invalid rule_options.%s in %s: min_length %d is above max_length %d", ruleID, configPath, options.MinLength, options.MaxLength)
+		}
+		if options.Charset != "" {
+			if _, err := regexp.Compile("[" + options.Charset + "]"); err != nil || strings.Contains(options.Charset, "]") {
+				return nil, fmt.Errorf("invalid rule_options.%s.charset %q in %s: use the inside of a character class, e.g. A-Za-z0-9_-", ruleID, options.Charset, configPath)
+			}
+		}
+	}
+	
+	customIDs := make(map[string]bool)
+	for i, rule := range cfg.CustomRules {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("custom_rules[%d] in %s needs an id", i, configPath)
+		}
+		if !ruleIDPattern.MatchString(rule.ID) {
+			return nil, fmt.Errorf("invalid id %q for custom_rules[%d] in %s: use capital letters, digits and underscores, starting with a letter (e.g. ACME_TOKEN)", rule.ID, i, configPath)
+		}
+		if customIDs[rule.ID] {
+			return nil, fmt.Errorf("custom rule %s is defined twice in %s", rule.ID, configPath)
+		}
+		customIDs[rule.ID] = true
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("custom rule %s in %s needs a pattern", rule.ID, configPath)
+		}
+		if err := regexcache.ValidateEngine(rule.Engine, rule.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern for custom rule %s (custom_rules[%d]) in %s: %w", rule.ID, i, configPath, err)
+		}
+		switch rule.Severity {
+		case "", "error", "warning":
+		default:
+			return nil, fmt.Errorf("invalid severity %q for custom rule %s in %s (use error or warning)", rule.Severity, rule.ID, configPath)
+		}
+		if rule.Within < 0 || rule.MinEntropy < 0 {
+			return nil, fmt.Errorf("invalid custom rule %s in %s: within and min_entropy must not be negative", rule.ID, configPath)
+		}
+		for _, glob := range rule.Paths {
+			if _, err := path.Match(glob, ""); err != nil {
+				return nil, fmt.Errorf("invalid path %q for custom rule %s in %s: %w", glob, rule.ID, configPath, err)
+			}
+		}
+		for _, context := range append(append([]string{}, rule.NotPrecededBy...), rule.NotFollowedBy...) {
+			if context == "" {
+				return nil, fmt.Errorf("invalid custom rule %s in %s: not_preceded_by and not_followed_by entries must not be empty", rule.ID, configPath)
+			}
+		}
+	}
+	
+	switch cfg.Settings.Hook.OnError {
+	case "", "block", "allow":
+	default:
+		return nil, fmt.Errorf("invalid hook.on_error %q in %s (use block or allow)", cfg.Settings.Hook.OnError, configPath)
+	}
+	
+	switch cfg.Settings.Symlinks.Follow {
+	case "", "repo", "never", "always":
+	default:
+		return nil, fmt.Errorf("invalid symlinks.follow %q in %s (use repo, never or always)", cfg.Settings.Symlinks.Follow, configPath)
+	}
+	
+	switch cfg.Settings.Masking.Strategy {
+	case "", "partial", "full", "hash":
+	default:
+		return nil, fmt.Errorf("invalid masking.strategy %q in %s (use partial, full or hash)", cfg.Settings.Masking.Strategy, configPath)
+	}
+	if cfg.Settings.LFS.MaxSizeMB < 0 {
+		return nil, fmt.Errorf("lfs.max_size_mb must not be negative in %s", configPath)
+	}
+	if cfg.Settings.MaxFindings < 0 {
+		return nil, fmt.Errorf("max_findings must not be negative in %s", configPath)
+	}
+	if cfg.Settings.Masking.Reveal < 0 {
+		return nil, fmt.Errorf("masking.reveal must not be negative in %s", configPath)
+	}
+	
+	for i, entry := range cfg.Allowlist {
+		switch entry.Target {
+		case "", "secret", "match", "line":
+		default:
+			return nil, fmt.Errorf("invalid allowlist[%d].target %q in %s (use secret, match or line)", i, entry.Target, configPath)
+		}
+		for _, pattern := range append(append([]string{}, entry.Regexes...), entry.Paths...) {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("invalid regex %q in allowlist[%d] of %s: %w", pattern, i, configPath, err)
+			}
+		}
+	}
+	
+	switch cfg.Settings.Store.Backend {
+	case "", "file", "sqlite":
+	case "postgres":
+		if cfg.Settings.Store.ConnString() == "" {
+			return nil, fmt.Errorf("settings.store.backend postgres needs settings.store.dsn or %s in %s", StoreDSNEnv, configPath)
+		}
+	default:
+		return nil, fmt.Errorf("invalid settings.store.backend %q in %s (use file, sqlite or postgres)", cfg.Settings.Store.Backend, configPath)
+	}
+	
+	if cfg.Settings.Telemetry.Enabled && cfg.Settings.Telemetry.Endpoint == "" {
+		return nil, fmt.Errorf("settings.telemetry.enabled needs settings.telemetry.endpoint in %s", configPath)
+	}
+	
+	if cfg.Settings.MinLength < 0 {
+		return nil, fmt.Errorf("invalid settings.min_length %d in %s (must not be negative)", cfg.Settings.MinLength, configPath)
+	}
+	if failOn := cfg.Settings.FailOnDetection; failOn != nil && !*failOn && cfg.FailOn == "" {
+		cfg.FailOn = "never"
+	}
+	
+	switch cfg.FailOn {
+	case "", "error", "warning", "never":
+	default:
+		return nil, fmt.Errorf("invalid fail_on %q in %s (use error, warning or never)", cfg.FailOn, configPath)
+	}
+	switch cfg.Format {
+	case "", "text", "json":
+	default:
+		return nil, fmt.Errorf("invalid format %q in profile %s of %s (use text or json)", cfg.Format, cfg.Profile, configPath)
+	}
+
+	return cfg, nil
+}
+
+// applyProfile overlays a named profile on the config
+func (c *Config) applyProfile(name string) error {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return fmt.Errorf("unknown profile %q: no profiles are defined", name)
+		}
+		var names []string
+		for defined := range c.Profiles {
+			names = append(names, defined)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown profile %q (defined: %s)", name, strings.Join(names, ", "))
+	}
+	
+	c.Profile = name
+	if profile.FailOn != "" {
+		c.FailOn = profile.FailOn
+	}
+	if len(profile.Rules) > 0 && c.Rules == nil {
+		c.Rules = make(map[string]bool)
+	}
+	for ruleID, enabled := range profile.Rules {
+		c.Rules[ruleID] = enabled
+	}
+	c.Packs = appendMissing(c.Packs, profile.Packs)
+	c.Format = profile.Format
+
+	c.ReportOnly = profile.ReportOnly
+	if profile.ReportOnlyUntil != "" {
+		until, err := time.Parse("2006-01-02", profile.ReportOnlyUntil)
+		if err != nil {
+			return fmt.Errorf("invalid report_only_until %q in profile %s (use YYYY-MM-DD)", profile.ReportOnlyUntil, name)
+		}
+		c.ReportOnlyUntil = profile.ReportOnlyUntil
+		if !time.Now().Before(until.AddDate(0, 0, 1)) {
+			c.ReportOnly, c.ExpiredReportOnly = nil, profile.ReportOnly
+		}
+	}
+	return nil
+}
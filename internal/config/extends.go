@@ -0,0 +1,256 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"secretlint/internal/signature"
+)
+
+// maxExtendsDepth bounds chains of configs extending each other
+const maxExtendsDepth = 5
+
+// defaultExtendsTTL is how long a fetched remote config is trusted to be current
+const defaultExtendsTTL = time.Hour
+
+// Origin records where one config in the extends chain was read from
+type Origin struct {
+	Source string
+
+	// CachedAt is when a remote config served from the cache was fetched;
+	// zero for local files and fresh downloads
+	CachedAt time.Time
+
+	// Stale is set when the cache was used because a refresh failed
+	Stale bool
+}
+
+// extendsLoader resolves an extends: chain, verifying signatures against the
+// local trust anchor and caching remote configs
+type extendsLoader struct {
+	key     *signature.PublicKey
+	options LoadOptions
+	ttl     time.Duration
+	offline bool
+	origins []Origin
+}
+
+// load loads a base config and, recursively, the configs it extends.
+// Policies accumulate: a base can add mandatory rules but never remove them.
+func (l *extendsLoader) load(source string, depth int) (*Config, error) {
+	if depth > maxExtendsDepth {
+		return nil, fmt.Errorf("extends: chain is deeper than %d configs at %s", maxExtendsDepth, source)
+	}
+	data, origin, err := l.fetch(source)
+	if err != nil {
+		return nil, err
+	}
+	if l.key != nil {
+		minisig, _, err := l.fetch(source + ".minisig")
+		if err != nil {
+			return nil, fmt.Errorf("refusing unsigned extended config %s: %w", source, err)
+		}
+		if _, err := l.key.Verify(data, minisig); err != nil {
+			return nil, fmt.Errorf("refusing extended config %s: %w", source, err)
+		}
+	}
+
+	own := Default()
+	if err := yaml.Unmarshal(data, own); err != nil {
+		return nil, fmt.Errorf("failed to parse extended config %s: %w", source, err)
+	}
+
+	cfg := Default()
+	if own.Extends != "" {
+		if cfg, err = l.load(resolveExtends(source, own.Extends), depth+1); err != nil {
+			return nil, err
+		}
+	}
+	l.origins = append(l.origins, origin)
+
+	// Cleared first so the overlay can't write into the inherited maps
+	inherited := cfg.Policy
+	cfg.Policy = Policy{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse extended config %s: %w", source, err)
+	}
+	cfg.Policy.MandatoryRules = appendMissing(inherited.MandatoryRules, own.Policy.MandatoryRules)
+	cfg.Policy.RuleOverrides = mergeOverrides(inherited.RuleOverrides, own.Policy.RuleOverrides)
+	for ruleID, override := range own.Policy.RuleOverrides {
+		switch override.Severity {
+		case "", "error", "warning":
+		default:
+			return nil, fmt.Errorf("invalid severity %q for %s in policy.rule_overrides of %s (use error or warning)", override.Severity, ruleID, source)
+		}
+	}
+	cfg.Policy.Source = source
+	if inherited.Source != "" {
+		cfg.Policy.Source = inherited.Source + " via " + source
+	}
+	return cfg, nil
+}
+
+// fetch reads an extended config from disk, or over http(s) through the
+// cache: a fresh cached copy is used as is, and a stale one only when the
+// refresh fails (e.g. offline). In offline mode remote configs are refused
+// outright, cached or not.
+func (l *extendsLoader) fetch(source string) ([]byte, Origin, error) {
+	origin := Origin{Source: source}
+	if !isURL(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, origin, fmt.Errorf("failed to read extended config: %w", err)
+		}
+		return data, origin, nil
+	}
+	if l.offline {
+		return nil, origin, fmt.Errorf("extends: %s is remote, which offline mode forbids; point extends: at a local copy", source)
+	}
+
+	cachePath := cachePath(source)
+	cached, cacheErr := os.ReadFile(cachePath)
+	var fetchedAt time.Time
+	if info, err := os.Stat(cachePath); err == nil {
+		fetchedAt = info.ModTime()
+	}
+	if cacheErr == nil && !l.options.Refresh && time.Since(fetchedAt) < l.ttl {
+		origin.CachedAt = fetchedAt
+		return cached, origin, nil
+	}
+
+	data, err := download(source)
+	if err != nil {
+		if cacheErr != nil || l.options.Refresh {
+			return nil, origin, err
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  %v; using the copy cached %s\n", err, fetchedAt.Format(time.RFC3339))
+		origin.CachedAt = fetchedAt
+		origin.Stale = true
+		return cached, origin, nil
+	}
+
+	if err := writeCache(cachePath, data); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not cache %s: %v\n", source, err)
+	}
+	return data, origin, nil
+}
+
+// cachePath names the cache file for a remote config URL
+func cachePath(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "secretlint", "policy", hex.EncodeToString(sum[:])[:32])
+}
+
+func writeCache(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Write then rename so a concurrent scan never reads a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// download fetches a remote config
+func download(source string) ([]byte, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch extended config %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch extended config %s: %s", source, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch extended config %s: %w", source, err)
+	}
+	return data, nil
+}
+
+// resolveExtends makes a relative extends: path relative to the config naming it
+func resolveExtends(from, extends string) string {
+	if isURL(extends) || filepath.IsAbs(extends) {
+		return extends
+	}
+	if isURL(from) {
+		if base, err := url.Parse(from); err == nil {
+			if ref, err := base.Parse(extends); err == nil {
+				return ref.String()
+			}
+		}
+		return extends
+	}
+	return filepath.Join(filepath.Dir(from), extends)
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+// mergeOverrides lays the overrides of a config over those it extends,
+// field by field, so a team config can change a rule's advice and keep the
+// org's severity for it
+func mergeOverrides(inherited, own map[string]RuleOverride) map[string]RuleOverride {
+	if len(inherited) == 0 && len(own) == 0 {
+		return nil
+	}
+	merged := make(map[string]RuleOverride, len(inherited)+len(own))
+	for ruleID, override := range inherited {
+		merged[ruleID] = override
+	}
+	for ruleID, override := range own {
+		base := merged[ruleID]
+		if override.Severity != "" {
+			base.Severity = override.Severity
+		}
+		if override.Advice != "" {
+			base.Advice = override.Advice
+		}
+		if override.RevokeURL != "" {
+			base.RevokeURL = override.RevokeURL
+		}
+		if override.RotateCommand != "" {
+			base.RotateCommand = override.RotateCommand
+		}
+		if len(override.DocLinks) > 0 {
+			base.DocLinks = override.DocLinks
+		}
+		merged[ruleID] = base
+	}
+	return merged
+}
+
+// appendMissing appends the values of extra not already in list
+func appendMissing(list, extra []string) []string {
+	for _, value := range extra {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
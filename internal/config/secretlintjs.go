@@ -0,0 +1,164 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSConfigFile is the config of the npm secretlint. It is read when a
+// repository has no .secretlintrc.yml, so both tools can share one file.
+const JSConfigFile = ".secretlintrc.json"
+
+// jsRules maps npm secretlint rules onto ours; packs are enabled with them
+var jsRules = map[string]struct {
+	rules []string
+	packs []string
+}{
+	"aws":        {rules: []string{"AWS_ACCESS_KEY", "AWS_SECRET_KEY"}},
+	"gcp":        {rules: []string{"PRIVATE_KEY"}},
+	"github":     {rules: []string{"GITHUB_PAT"}},
+	"privatekey": {rules: []string{"PRIVATE_KEY", "OPENSSH_PRIVATE_KEY", "PGP_PRIVATE_KEY"}},
+	"npm":        {rules: []string{"NPM_TOKEN", "NPMRC_AUTH_TOKEN"}, packs: []string{"npm"}},
+	"slack":      {rules: []string{"SLACK_TOKEN"}},
+	"openai":     {rules: []string{"OPENAI_API_KEY"}},
+}
+
+// jsPresets enable every rule, as their npm counterparts enable all common rules
+var jsPresets = map[string]bool{
+	"preset-recommend": true,
+	"preset-canary":    true,
+}
+
+// jsWarned keeps the unknown-rules warning to once per process, as the
+// config is loaded by several steps of a command
+var jsWarned bool
+
+// jsConfig is the part of .secretlintrc.json we understand
+type jsConfig struct {
+	Rules []jsRule `json:"rules"`
+}
+
+type jsRule struct {
+	ID       string `json:"id"`
+	Disabled bool   `json:"disabled"`
+	Options  struct {
+		// Allows are exact values or /regex/flags strings never to report
+		Allows []string `json:"allows"`
+	} `json:"options"`
+
+	// Rules override the rules of a preset
+	Rules []jsRule `json:"rules"`
+}
+
+// readJSConfig converts .secretlintrc.json into the YAML of an equivalent
+// .secretlintrc.yml. Like the npm secretlint, only the listed rules run: a
+// preset enables all rules, otherwise rules without a listed counterpart are
+// disabled. Unknown rules are reported once and skipped.
+func readJSConfig(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var js jsConfig
+	if err := json.Unmarshal(data, &js); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	enabled := make(map[string]bool)
+	preset := false
+	var packs, unknown []string
+	var allowlist []AllowlistEntry
+	var apply func(rules []jsRule)
+	apply = func(rules []jsRule) {
+		for _, rule := range rules {
+			name := strings.TrimPrefix(strings.TrimPrefix(rule.ID, "@secretlint/"), "secretlint-rule-")
+			if jsPresets[name] {
+				if !rule.Disabled {
+					preset = true
+				}
+				apply(rule.Rules)
+				continue
+			}
+			mapped, ok := jsRules[name]
+			if !ok {
+				unknown = append(unknown, rule.ID)
+				continue
+			}
+			for _, ruleID := range mapped.rules {
+				enabled[ruleID] = !rule.Disabled
+			}
+			if rule.Disabled {
+				continue
+			}
+			packs = appendMissing(packs, mapped.packs)
+			if entry, ok := jsAllowlist(rule.ID, mapped.rules, rule.Options.Allows); ok {
+				allowlist = append(allowlist, entry)
+			}
+		}
+	}
+	apply(js.Rules)
+
+	if preset {
+		for _, mapped := range jsRules {
+			packs = appendMissing(packs, mapped.packs)
+		}
+	} else {
+		// Rules with no npm counterpart only run under a preset
+		for _, ruleID := range nativeRules {
+			if _, listed := enabled[ruleID]; !listed {
+				enabled[ruleID] = false
+			}
+		}
+	}
+	if len(unknown) > 0 && !jsWarned {
+		jsWarned = true
+		sort.Strings(unknown)
+		fmt.Fprintf(os.Stderr, "Warning: %s: rules without a secretlint equivalent are skipped: %s\n", path, strings.Join(unknown, ", "))
+	}
+
+	local := map[string]interface{}{"rules": enabled}
+	if len(packs) > 0 {
+		local["packs"] = packs
+	}
+	if len(allowlist) > 0 {
+		local["allowlist"] = allowlist
+	}
+	return yaml.Marshal(local)
+}
+
+// nativeRules are the built-in rules; without a preset, those no listed npm
+// rule maps to are turned off
+var nativeRules = []string{
+	"OPENAI_API_KEY", "GITHUB_PAT", "AWS_ACCESS_KEY", "AWS_SECRET_KEY",
+	"STRIPE_LIVE_PK", "STRIPE_LIVE_SK", "SLACK_TOKEN", "JWT_TOKEN",
+	"GENERIC_API_KEY", "PRIVATE_KEY", "OPENSSH_PRIVATE_KEY", "PGP_PRIVATE_KEY",
+	"AGE_SECRET_KEY", "NETRC_CREDENTIAL", "NPMRC_AUTH_TOKEN", "PYPI_TOKEN",
+	"PGPASS_CREDENTIAL", "HARDCODED_CREDENTIAL", "SENSITIVE_FILE", "LITERAL_SECRET",
+}
+
+// jsRegexAllow matches the "/regex/flags" form of an allowed value
+var jsRegexAllow = regexp.MustCompile(`^/(.*)/([a-z]*)$`)
+
+// jsAllowlist converts a rule's "allows" option: "/regex/flags" strings are
+// regexes, anything else an exact value
+func jsAllowlist(id string, rules []string, allows []string) (AllowlistEntry, bool) {
+	entry := AllowlistEntry{Description: "allows of " + id + " in " + JSConfigFile, Rules: rules}
+	for _, allow := range allows {
+		if m := jsRegexAllow.FindStringSubmatch(allow); m != nil {
+			pattern := m[1]
+			if strings.Contains(m[2], "i") {
+				pattern = "(?i)" + pattern
+			}
+			entry.Regexes = append(entry.Regexes, pattern)
+		} else {
+			entry.Regexes = append(entry.Regexes, "^"+regexp.QuoteMeta(allow)+"$")
+		}
+	}
+	return entry, len(entry.Regexes) > 0
+}
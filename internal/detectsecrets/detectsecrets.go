@@ -0,0 +1,101 @@
+package detectsecrets
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"secretlint/internal/scanner"
+)
+
+// DefaultBaselineFile is where detect-secrets keeps a repository's baseline
+const DefaultBaselineFile = ".secrets.baseline"
+
+// Baseline is a detect-secrets .secrets.baseline file
+type Baseline struct {
+	Version string              `json:"version"`
+	Results map[string][]Secret `json:"results"`
+}
+
+// Secret is one audited or unaudited result of the baseline
+type Secret struct {
+	Type         string `json:"type"`
+	Filename     string `json:"filename"`
+	HashedSecret string `json:"hashed_secret"`
+	LineNumber   int    `json:"line_number"`
+
+	// IsSecret is set by 'detect-secrets audit': false marks a false
+	// positive, true a real secret
+	IsSecret *bool `json:"is_secret"`
+}
+
+// pluginRules maps detect-secrets plugin types to the rules detecting the
+// same secrets. Types without a rule, such as the high entropy plugins, are
+// matched by secret hash alone.
+var pluginRules = map[string][]string{
+	"AWS Access Key":    {"AWS_ACCESS_KEY", "AWS_SECRET_KEY"},
+	"GitHub Token":      {"GITHUB_PAT"},
+	"JSON Web Token":    {"JWT_TOKEN"},
+	"OpenAI Token":      {"OPENAI_API_KEY"},
+	"Private Key":       {"PRIVATE_KEY"},
+	"Secret Keyword":    {scanner.SensitiveKeyRule, "GENERIC_API_KEY"},
+	"Slack Token":       {"SLACK_TOKEN"},
+	"Stripe Access Key": {"STRIPE_LIVE_SK", "STRIPE_LIVE_PK"},
+}
+
+// Load reads a .secrets.baseline file
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	baseline := &Baseline{}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if baseline.Results == nil {
+		return nil, fmt.Errorf("%s is not a detect-secrets baseline (no results)", path)
+	}
+	return baseline, nil
+}
+
+// Files returns the files with results, sorted
+func (b *Baseline) Files() []string {
+	var files []string
+	for file := range b.Results {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Match finds the finding a result refers to among the findings of its file.
+// The secret hash identifies it even if the line moved; otherwise a finding
+// of a corresponding rule on the recorded line is taken.
+func (s Secret) Match(findings []scanner.Finding) (scanner.Finding, bool) {
+	for _, finding := range findings {
+		if hashSecret(finding.Secret) == s.HashedSecret {
+			return finding, true
+		}
+	}
+	for _, finding := range findings {
+		if finding.LineNum != s.LineNumber {
+			continue
+		}
+		for _, ruleID := range pluginRules[s.Type] {
+			if finding.RuleID == ruleID {
+				return finding, true
+			}
+		}
+	}
+	return scanner.Finding{}, false
+}
+
+// hashSecret is the SHA-1 detect-secrets stores instead of the secret
+func hashSecret(secret string) string {
+	sum := sha1.Sum([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TokenEnv holds the bearer token sent with scan events
+const TokenEnv = "SECRETLINT_EVENTS_TOKEN"
+
+// ScanCompleted is the event type sent after every scan
+const ScanCompleted = "scan.completed"
+
+// Timeout keeps a slow collector from delaying commits and pushes
+const Timeout = 2 * time.Second
+
+// Event is a small, secret-free summary of one scan run. Platform teams use
+// it to measure adoption and spot repositories where hooks stopped running.
+type Event struct {
+	Event       string    `json:"event"`
+	Time        time.Time `json:"time"`
+	Tool        string    `json:"tool"`
+	ToolVersion string    `json:"tool_version"`
+	Repo        string    `json:"repo"`
+	Ref         string    `json:"ref,omitempty"`
+	Head        string    `json:"head,omitempty"`
+	Operation   string    `json:"operation,omitempty"`
+	Mode        string    `json:"mode"`
+	Hook        bool      `json:"hook"`
+	DurationMS  int64     `json:"duration_ms"`
+	Files       int       `json:"files"`
+	Findings    int       `json:"findings"`
+	Blocked     bool      `json:"blocked"`
+	Error       bool      `json:"error,omitempty"`
+}
+
+// Send posts an event as JSON
+func Send(url, token string, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode scan event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid events URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "secretlint")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: Timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send scan event: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("events endpoint returned %s", resp.Status)
+	}
+	return nil
+}
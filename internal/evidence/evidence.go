@@ -0,0 +1,264 @@
+package evidence
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SchemaVersion is bumped whenever the bundle layout changes incompatibly
+const SchemaVersion = 1
+
+// Names of the signature files inside a bundle
+const (
+	ManifestFile  = "manifest.json"
+	SignatureFile = "manifest.sig"
+	PublicKeyFile = "signing-key.pub"
+)
+
+// File is one document in the bundle
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Digest records a bundled file's checksum in the manifest
+type Digest struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+}
+
+// Manifest describes the bundle. It is what gets signed, and it lists the
+// digest of every other file so tampering with any of them is detectable.
+type Manifest struct {
+	Version     int       `json:"version"`
+	Tool        string    `json:"tool"`
+	ToolVersion string    `json:"tool_version"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Repo        string    `json:"repo"`
+	Branch      string    `json:"branch,omitempty"`
+	Head        string    `json:"head,omitempty"`
+	Scope       string    `json:"scope"`
+	KeyID       string    `json:"key_id"`
+	Files       []Digest  `json:"files"`
+}
+
+// Write produces a gzipped tar bundle with the manifest, its signature and
+// the public key needed to check it
+func Write(w io.Writer, manifest Manifest, files []File, key ed25519.PrivateKey) error {
+	publicKey := key.Public().(ed25519.PublicKey)
+	manifest.Version = SchemaVersion
+	manifest.KeyID = KeyID(publicKey)
+	manifest.Files = nil
+	for _, file := range files {
+		sum := sha256.Sum256(file.Data)
+		manifest.Files = append(manifest.Files, Digest{Name: file.Name, SHA256: hex.EncodeToString(sum[:]), Size: len(file.Data)})
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	manifestData = append(manifestData, '\n')
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, manifestData)) + "\n"
+
+	publicKeyData, err := encodePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	entries := append([]File{
+		{Name: ManifestFile, Data: manifestData},
+		{Name: SignatureFile, Data: []byte(signature)},
+		{Name: PublicKeyFile, Data: publicKeyData},
+	}, files...)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:    entry.Name,
+			Mode:    0644,
+			Size:    int64(len(entry.Data)),
+			ModTime: manifest.GeneratedAt,
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", entry.Name, err)
+		}
+		if _, err := archive.Write(entry.Data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", entry.Name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return nil
+}
+
+// Verify checks a bundle's signature and every file digest, returning the
+// manifest. trustedKeyID, when set, must match the signing key. Files the
+// manifest doesn't list are rejected, since nothing vouches for them.
+func Verify(r io.Reader, trustedKeyID string) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not an evidence bundle: %w", err)
+	}
+	archive := tar.NewReader(gz)
+
+	contents := make(map[string][]byte)
+	var names []string
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		if _, ok := contents[header.Name]; ok {
+			return nil, fmt.Errorf("bundle has %s twice", header.Name)
+		}
+		contents[header.Name] = data
+		names = append(names, header.Name)
+	}
+
+	manifestData, ok := contents[ManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("bundle has no %s", ManifestFile)
+	}
+	publicKey, err := decodePublicKey(contents[PublicKeyFile])
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(contents[SignatureFile])))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if !ed25519.Verify(publicKey, manifestData, signature) {
+		return nil, fmt.Errorf("signature does not match manifest")
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(manifestData, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.KeyID != KeyID(publicKey) {
+		return nil, fmt.Errorf("manifest key ID does not match the bundled public key")
+	}
+	if trustedKeyID != "" && manifest.KeyID != trustedKeyID {
+		return nil, fmt.Errorf("bundle was signed by key %s, not the trusted key %s", manifest.KeyID, trustedKeyID)
+	}
+
+	signed := map[string]bool{ManifestFile: true, SignatureFile: true, PublicKeyFile: true}
+	for _, digest := range manifest.Files {
+		signed[digest.Name] = true
+	}
+	for _, name := range names {
+		if !signed[name] {
+			return nil, fmt.Errorf("%s is not listed in the manifest", name)
+		}
+	}
+
+	for _, digest := range manifest.Files {
+		data, ok := contents[digest.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s is listed in the manifest but missing", digest.Name)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != digest.SHA256 {
+			return nil, fmt.Errorf("%s was modified after signing", digest.Name)
+		}
+	}
+
+	return manifest, nil
+}
+
+// KeyID is a short identifier for a signing key
+func KeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// LoadOrCreateKey reads a PEM-encoded Ed25519 private key, generating one
+// (readable only by the current user) if the file doesn't exist
+func LoadOrCreateKey(keyPath string) (ed25519.PrivateKey, bool, error) {
+	data, err := os.ReadFile(keyPath)
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, false, fmt.Errorf("%s is not a PEM file", keyPath)
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse %s: %w", keyPath, err)
+		}
+		key, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, false, fmt.Errorf("%s is not an Ed25519 key", keyPath)
+		}
+		return key, false, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, false, fmt.Errorf("failed to read %s: %w", keyPath, err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, false, fmt.Errorf("failed to create %s: %w", filepath.Dir(keyPath), err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, false, fmt.Errorf("failed to write %s: %w", keyPath, err)
+	}
+	return key, true, nil
+}
+
+func encodePublicKey(publicKey ed25519.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+func decodePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("bundle has no valid %s", PublicKeyFile)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", PublicKeyFile, err)
+	}
+	publicKey, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 key", PublicKeyFile)
+	}
+	return publicKey, nil
+}
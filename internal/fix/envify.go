@@ -0,0 +1,94 @@
+package fix
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"secretlint/internal/scanner"
+)
+
+// credentialKeyRegex matches an assignment to a sensitive-looking key and captures the value
+var credentialKeyRegex = regexp.MustCompile(`(?i)["']?([A-Za-z0-9_.\-]*(?:password|passwd|pwd|secret|token|api[_\-]?key|apikey|auth[_\-]?key|credential|private[_\-]?key|access[_\-]?key)[A-Za-z0-9_.\-]*)["']?\s*(?::=|=|:)\s*(["'` + "`" + `]?)([^"'` + "`" + `\s#,;]+)(["'` + "`" + `]?)`)
+
+// FindHardcodedCredentials flags sensitive keys assigned literal values, which
+// token-specific rules miss (e.g. database passwords)
+func FindHardcodedCredentials(lines []scanner.DiffLine) []scanner.Finding {
+	var findings []scanner.Finding
+	kind := kindUnsupported
+	if len(lines) > 0 {
+		kind = classifyFile(lines[0].FilePath)
+	}
+
+	for _, line := range lines {
+		for _, match := range credentialKeyRegex.FindAllStringSubmatchIndex(line.Content, -1) {
+			openQuote := line.Content[match[4]:match[5]]
+			closeQuote := line.Content[match[8]:match[9]]
+			value := line.Content[match[6]:match[7]]
+
+			// Source code must assign a string literal, not a variable or call
+			if kind == kindSource && (openQuote == "" || openQuote != closeQuote) {
+				continue
+			}
+			if !scanner.LooksLikeLiteralCredential(value) {
+				continue
+			}
+
+			findings = append(findings, scanner.Finding{
+				RuleID:      scanner.SensitiveKeyRule,
+				RuleName:    "Hardcoded Credential",
+				FilePath:    line.FilePath,
+				LineNum:     line.LineNum,
+				Content:     line.Content,
+				Match:       line.Content[match[0]:match[1]],
+				Secret:      value,
+				StartPos:    match[6],
+				EndPos:      match[7],
+				Description: "Sensitive key assigned a literal value",
+				Advice:      "Read the value from an environment variable instead",
+				Severity:    scanner.SeverityError,
+			})
+		}
+	}
+
+	return findings
+}
+
+// AppendExample adds variable names (without values) to an example env file,
+// returning how many were added
+func AppendExample(exampleFile string, names []string) (int, error) {
+	existing, err := readEnvFile(exampleFile)
+	if err != nil {
+		return 0, err
+	}
+
+	var b strings.Builder
+	if data, err := os.ReadFile(exampleFile); err == nil && len(data) > 0 && data[len(data)-1] != '\n' {
+		b.WriteString("\n")
+	}
+
+	added := 0
+	for _, name := range names {
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		existing[name] = ""
+		fmt.Fprintf(&b, "%s=\n", name)
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+
+	file, err := os.OpenFile(exampleFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", exampleFile, err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(b.String()); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", exampleFile, err)
+	}
+	return added, nil
+}
@@ -0,0 +1,443 @@
+package fix
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"secretlint/internal/scanner"
+)
+
+// fileKind describes how a secret can be referenced from a given file type
+type fileKind int
+
+const (
+	kindUnsupported fileKind = iota
+	kindDotenvFile           // the .env file itself, which must stay untracked
+	kindDotenv               // committed .env-style files such as .env.production
+	kindYAML
+	kindJSON
+	kindSource
+)
+
+// sourceReferences maps source file extensions to an env var lookup expression
+var sourceReferences = map[string]string{
+	".js":   "process.env.%s",
+	".jsx":  "process.env.%s",
+	".ts":   "process.env.%s",
+	".tsx":  "process.env.%s",
+	".mjs":  "process.env.%s",
+	".cjs":  "process.env.%s",
+	".py":   `os.environ["%s"]`,
+	".go":   `os.Getenv("%s")`,
+	".rb":   `ENV["%s"]`,
+	".java": `System.getenv("%s")`,
+	".kt":   `System.getenv("%s")`,
+	".php":  `getenv('%s')`,
+	".cs":   `Environment.GetEnvironmentVariable("%s")`,
+	".sh":   `"${%s}"`,
+	".bash": `"${%s}"`,
+}
+
+// assignmentKeyRegex captures the key of an assignment that ends right before the secret
+var assignmentKeyRegex = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_.\-]*)['"]?\s*(?::=|=|:)\s*['"]?$`)
+
+// Change describes a secret that was moved to an environment variable
+type Change struct {
+	FilePath  string
+	LineNum   int
+	RuleID    string
+	EnvVar    string
+	Reference string
+}
+
+// Skipped describes a finding that could not be fixed automatically
+type Skipped struct {
+	FilePath string
+	LineNum  int
+	RuleID   string
+	Reason   string
+}
+
+// Result summarizes the outcome of applying fixes
+type Result struct {
+	Changes          []Change
+	Skipped          []Skipped
+	ModifiedFiles    []string
+	UnstageFiles     []string
+	EnvFile          string
+	GitignoreFile    string
+	GitignoreUpdated bool
+}
+
+// Fixer replaces literal secrets with environment variable references
+type Fixer struct {
+	envFile       string
+	gitignoreFile string
+}
+
+// EnvFile is where the fixes write the secrets they move out of the code
+const EnvFile = ".env"
+
+// NewFixer creates a Fixer writing variables to .env and ignoring it in .gitignore
+func NewFixer() *Fixer {
+	return &Fixer{
+		envFile:       EnvFile,
+		gitignoreFile: ".gitignore",
+	}
+}
+
+// Apply rewrites every fixable finding and records the secret values in the .env file
+func (f *Fixer) Apply(findings []scanner.Finding) (*Result, error) {
+	result := &Result{
+		EnvFile:       f.envFile,
+		GitignoreFile: f.gitignoreFile,
+	}
+
+	envVars, err := readEnvFile(f.envFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group findings per file so each file is rewritten once
+	var order []string
+	byFile := make(map[string][]scanner.Finding)
+	for _, finding := range findings {
+		if _, ok := byFile[finding.FilePath]; !ok {
+			order = append(order, finding.FilePath)
+		}
+		byFile[finding.FilePath] = append(byFile[finding.FilePath], finding)
+	}
+
+	var newVars []string
+	for _, filePath := range order {
+		kind := classifyFile(filePath)
+		if kind == kindUnsupported {
+			for _, finding := range byFile[filePath] {
+				result.Skipped = append(result.Skipped, skip(finding, "unsupported file type"))
+			}
+			continue
+		}
+		if kind == kindDotenvFile {
+			result.UnstageFiles = append(result.UnstageFiles, filePath)
+			continue
+		}
+
+		changes, skipped, err := f.fixFile(filePath, kind, byFile[filePath], envVars, &newVars)
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = append(result.Changes, changes...)
+		result.Skipped = append(result.Skipped, skipped...)
+		if len(changes) > 0 {
+			result.ModifiedFiles = append(result.ModifiedFiles, filePath)
+		}
+	}
+
+	if err := appendEnvVars(f.envFile, envVars, newVars); err != nil {
+		return nil, err
+	}
+
+	if len(result.Changes) > 0 || len(result.UnstageFiles) > 0 {
+		updated, err := ensureGitignored(f.gitignoreFile, f.envFile)
+		if err != nil {
+			return nil, err
+		}
+		result.GitignoreUpdated = updated
+	}
+
+	return result, nil
+}
+
+// fixFile rewrites the findings of a single file in place
+func (f *Fixer) fixFile(filePath string, kind fileKind, findings []scanner.Finding, envVars map[string]string, newVars *[]string) ([]Change, []Skipped, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to stat %s: %w", filePath, err)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	lines := strings.Split(string(data), "\n")
+
+	var changes []Change
+	var skipped []Skipped
+	seen := make(map[string]bool)
+
+	for _, finding := range findings {
+		if finding.Secret == "" || strings.Contains(finding.Secret, "PRIVATE KEY") {
+			skipped = append(skipped, skip(finding, "multi-line secrets must be moved manually"))
+			continue
+		}
+
+		// Several rules can flag the same value; fix it only once
+		key := fmt.Sprintf("%d:%s", finding.LineNum, finding.Secret)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		idx := locateLine(lines, finding.LineNum, finding.Secret)
+		if idx < 0 {
+			skipped = append(skipped, skip(finding, "secret no longer present in working tree file"))
+			continue
+		}
+
+		line := lines[idx]
+		start := strings.Index(line, finding.Secret)
+		end := start + len(finding.Secret)
+
+		envVar := uniqueEnvVar(envVarName(line[:start], finding.RuleID), finding.Secret, envVars)
+
+		reference, start, end, ok := replacement(kind, filePath, line, start, end, envVar)
+		if !ok {
+			skipped = append(skipped, skip(finding, "secret is not a standalone string literal"))
+			continue
+		}
+
+		lines[idx] = line[:start] + reference + line[end:]
+
+		if _, exists := envVars[envVar]; !exists {
+			envVars[envVar] = finding.Secret
+			*newVars = append(*newVars, envVar)
+		}
+
+		changes = append(changes, Change{
+			FilePath:  filePath,
+			LineNum:   idx + 1,
+			RuleID:    finding.RuleID,
+			EnvVar:    envVar,
+			Reference: reference,
+		})
+	}
+
+	if len(changes) == 0 {
+		return nil, skipped, nil
+	}
+
+	if err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return nil, nil, fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+
+	return changes, skipped, nil
+}
+
+// replacement returns the reference to envVar that replaces the secret at
+// line[start:end], and the range it replaces. In source code the whole
+// string literal is replaced, so ok is false when the secret isn't one.
+func replacement(kind fileKind, filePath, line string, start, end int, envVar string) (reference string, replaceStart, replaceEnd int, ok bool) {
+	if kind != kindSource {
+		return "${" + envVar + "}", start, end, true
+	}
+	if start == 0 || end >= len(line) || !isQuote(line[start-1]) || line[end] != line[start-1] {
+		return "", 0, 0, false
+	}
+	return fmt.Sprintf(sourceReferences[strings.ToLower(filepath.Ext(filePath))], envVar), start - 1, end + 1, true
+}
+
+// classifyFile determines how secrets in the given file can be referenced
+func classifyFile(filePath string) fileKind {
+	base := filepath.Base(filePath)
+	ext := strings.ToLower(filepath.Ext(filePath))
+
+	switch {
+	case base == ".env":
+		return kindDotenvFile
+	case strings.HasPrefix(base, ".env.") || ext == ".env":
+		return kindDotenv
+	case ext == ".yml" || ext == ".yaml":
+		return kindYAML
+	case ext == ".json":
+		return kindJSON
+	}
+
+	if _, ok := sourceReferences[ext]; ok {
+		return kindSource
+	}
+	return kindUnsupported
+}
+
+// locateLine finds the line holding the secret, preferring the reported line number
+func locateLine(lines []string, lineNum int, secret string) int {
+	if lineNum >= 1 && lineNum <= len(lines) && strings.Contains(lines[lineNum-1], secret) {
+		return lineNum - 1
+	}
+	for i, line := range lines {
+		if strings.Contains(line, secret) {
+			return i
+		}
+	}
+	return -1
+}
+
+// envVarName derives a variable name from the assignment preceding the secret
+func envVarName(prefix, ruleID string) string {
+	matches := assignmentKeyRegex.FindStringSubmatch(prefix)
+	if matches == nil {
+		return ruleID
+	}
+
+	// Use the last path segment of dotted keys (config.openai.apiKey -> API_KEY)
+	key := matches[1]
+	if i := strings.LastIndex(key, "."); i >= 0 && i < len(key)-1 {
+		key = key[i+1:]
+	}
+
+	var b strings.Builder
+	for i, r := range key {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if i > 0 && key[i-1] >= 'a' && key[i-1] <= 'z' {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+
+	// Throwaway names like "k" or "s" say nothing about the credential
+	name := strings.Trim(b.String(), "_")
+	if len(name) < 3 {
+		return ruleID
+	}
+	return name
+}
+
+// SuggestEnvVar proposes an environment variable name for a finding's secret
+func SuggestEnvVar(finding scanner.Finding) string {
+	prefix := finding.Content
+	if i := strings.Index(finding.Content, finding.Secret); i >= 0 {
+		prefix = finding.Content[:i]
+	}
+	return envVarName(prefix, finding.RuleID)
+}
+
+// Reference returns the expression code in the given file uses to read an
+// environment variable, falling back to shell-style interpolation
+func Reference(filePath, name string) string {
+	if format, ok := sourceReferences[strings.ToLower(filepath.Ext(filePath))]; ok {
+		return fmt.Sprintf(format, name)
+	}
+	return "${" + name + "}"
+}
+
+// uniqueEnvVar avoids clobbering an existing variable that holds a different value
+func uniqueEnvVar(name, secret string, envVars map[string]string) string {
+	candidate := name
+	for i := 2; ; i++ {
+		existing, ok := envVars[candidate]
+		if !ok || existing == secret {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+}
+
+func isQuote(c byte) bool {
+	return c == '"' || c == '\'' || c == '`'
+}
+
+func skip(finding scanner.Finding, reason string) Skipped {
+	return Skipped{
+		FilePath: finding.FilePath,
+		LineNum:  finding.LineNum,
+		RuleID:   finding.RuleID,
+		Reason:   reason,
+	}
+}
+
+// readEnvFile loads existing KEY=VALUE pairs from a .env file
+func readEnvFile(envFile string) (map[string]string, error) {
+	vars := make(map[string]string)
+
+	file, err := os.Open(envFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return vars, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", envFile, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		eq := strings.Index(line, "=")
+		if eq <= 0 {
+			continue
+		}
+		value := strings.TrimSpace(line[eq+1:])
+		value = strings.Trim(value, `"'`)
+		vars[strings.TrimSpace(line[:eq])] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", envFile, err)
+	}
+	return vars, nil
+}
+
+// appendEnvVars writes newly introduced variables to the end of the .env file
+func appendEnvVars(envFile string, envVars map[string]string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	if existing, err := os.ReadFile(envFile); err == nil && len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		b.WriteString("\n")
+	}
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s=%s\n", name, envVars[name])
+	}
+
+	file, err := os.OpenFile(envFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", envFile, err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(b.String()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", envFile, err)
+	}
+	return nil
+}
+
+// ensureGitignored appends the env file to .gitignore unless it is already listed
+func ensureGitignored(gitignoreFile, envFile string) (bool, error) {
+	data, err := os.ReadFile(gitignoreFile)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read %s: %w", gitignoreFile, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == envFile || line == "/"+envFile || line == envFile+"*" || line == "*"+envFile {
+			return false, nil
+		}
+	}
+
+	content := string(data)
+	if len(content) > 0 && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += envFile + "\n"
+
+	if err := os.WriteFile(gitignoreFile, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", gitignoreFile, err)
+	}
+	return true, nil
+}
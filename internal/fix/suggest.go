@@ -0,0 +1,71 @@
+package fix
+
+import (
+	"strings"
+
+	"secretlint/internal/archive"
+	"secretlint/internal/scanner"
+)
+
+// Suggestion is the fix 'secretlint fix' would apply to one finding, for
+// editors to offer as a quick fix. Offsets are byte offsets in the
+// finding's line.
+type Suggestion struct {
+	// EnvVar is the suggested environment variable name
+	EnvVar string
+
+	// Start and End delimit the text Replacement replaces: the secret, or
+	// in source code the string literal holding it
+	Start, End  int
+	Replacement string
+
+	// ValueStart and ValueEnd delimit the secret, the value of EnvVar
+	ValueStart, ValueEnd int
+}
+
+// Suggest returns the quick fix for a finding in a working tree file, or
+// nil when the secret has to be moved by hand: it spans lines, sits in
+// history, an archive, a notebook cell or a library FileSource, is in .env
+// itself, or the file type has no way to read an environment variable
+func Suggest(finding scanner.Finding) *Suggestion {
+	if finding.Commit != nil || finding.Metadata != nil || finding.Cell != "" || strings.Contains(finding.FilePath, archive.Separator) {
+		return nil
+	}
+	if finding.Secret == "" || strings.Contains(finding.Secret, "\n") || strings.Contains(finding.Secret, "PRIVATE KEY") {
+		return nil
+	}
+	kind := classifyFile(finding.FilePath)
+	if kind == kindUnsupported || kind == kindDotenvFile {
+		return nil
+	}
+
+	// The secret is the rule's match or a group of it
+	line := finding.Content
+	from := finding.StartPos
+	if from < 0 || from > len(line) {
+		from = 0
+	}
+	start := strings.Index(line[from:], finding.Secret)
+	if start < 0 {
+		from, start = 0, strings.Index(line, finding.Secret)
+		if start < 0 {
+			return nil
+		}
+	}
+	start += from
+	end := start + len(finding.Secret)
+
+	envVar := envVarName(line[:start], finding.RuleID)
+	reference, replaceStart, replaceEnd, ok := replacement(kind, finding.FilePath, line, start, end, envVar)
+	if !ok {
+		return nil
+	}
+	return &Suggestion{
+		EnvVar:      envVar,
+		Start:       replaceStart,
+		End:         replaceEnd,
+		Replacement: reference,
+		ValueStart:  start,
+		ValueEnd:    end,
+	}
+}
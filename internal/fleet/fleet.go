@@ -0,0 +1,159 @@
+package fleet
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Repo is one repository of a fleet scan
+type Repo struct {
+	// Name labels findings, e.g. "org/api"
+	Name string
+
+	// Source is a clone URL, or a local checkout scanned in place
+	Source string
+}
+
+// IsRemote reports whether the repository has to be cloned
+func (r Repo) IsRemote() bool {
+	return strings.Contains(r.Source, "://") || strings.HasPrefix(r.Source, "git@")
+}
+
+// ParseList reads a repository list: one clone URL or local path per line,
+// with blank lines and # comments ignored
+func ParseList(path string) ([]Repo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+	defer file.Close()
+
+	var repos []Repo
+	seen := make(map[string]bool)
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		repo := Repo{Name: RepoName(line), Source: line}
+		if seen[repo.Name] {
+			return nil, fmt.Errorf("repository list names %s twice", repo.Name)
+		}
+		seen[repo.Name] = true
+		repos = append(repos, repo)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+	return repos, nil
+}
+
+// RepoName derives owner/name from a clone URL, or the directory name of a
+// local path
+func RepoName(source string) string {
+	source = strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git")
+	if !strings.Contains(source, "://") && !strings.HasPrefix(source, "git@") {
+		return filepath.Base(filepath.Clean(source))
+	}
+	// git@host:owner/name and https://host/owner/name
+	source = strings.Replace(source, ":", "/", -1)
+	parts := strings.Split(source, "/")
+	if len(parts) >= 2 {
+		return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+	}
+	return parts[len(parts)-1]
+}
+
+// githubRepo is the part of the GitHub repository resource we use
+type githubRepo struct {
+	FullName string `json:"full_name"`
+	CloneURL string `json:"clone_url"`
+	Archived bool   `json:"archived"`
+}
+
+// GitHubOrg lists the non-archived repositories of a GitHub organization.
+// baseURL is the API root (https://api.github.com, or https://HOST/api/v3).
+func GitHubOrg(baseURL, org, token string) ([]Repo, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	var repos []Repo
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/orgs/%s/repos?per_page=100&page=%d", strings.TrimSuffix(baseURL, "/"), org, page)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request: %w", err)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", org, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list repositories of %s: %s", org, resp.Status)
+		}
+		var batch []githubRepo
+		err = json.NewDecoder(resp.Body).Decode(&batch)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode repositories of %s: %w", org, err)
+		}
+
+		for _, repo := range batch {
+			if !repo.Archived {
+				repos = append(repos, Repo{Name: repo.FullName, Source: repo.CloneURL})
+			}
+		}
+		if len(batch) < 100 {
+			return repos, nil
+		}
+	}
+}
+
+// Sync clones a remote repository into dir, or updates an earlier clone to
+// the remote's default branch. Only the latest commit is fetched unless
+// fullHistory is set.
+func Sync(repo Repo, dir string, fullHistory bool) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(dir), err)
+		}
+		args := []string{"clone", "--quiet"}
+		if !fullHistory {
+			args = append(args, "--depth", "1")
+		}
+		return git("", append(args, repo.Source, dir)...)
+	}
+
+	args := []string{"fetch", "--quiet"}
+	if !fullHistory {
+		args = append(args, "--depth", "1")
+	} else if output, err := exec.Command("git", "-C", dir, "rev-parse", "--is-shallow-repository").Output(); err == nil && strings.TrimSpace(string(output)) == "true" {
+		args = append(args, "--unshallow")
+	}
+	if err := git(dir, append(args, "origin", "HEAD")...); err != nil {
+		return err
+	}
+	return git(dir, "reset", "--quiet", "--hard", "FETCH_HEAD")
+}
+
+// git runs a git command in dir (the working directory if empty)
+func git(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(output)))
+	}
+	return nil
+}
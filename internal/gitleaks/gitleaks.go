@@ -0,0 +1,454 @@
+package gitleaks
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"secretlint/internal/config"
+	"secretlint/internal/regexcache"
+	"secretlint/internal/rulepack"
+)
+
+// DefaultConfigFile is where gitleaks looks for a repository's config
+const DefaultConfigFile = ".gitleaks.toml"
+
+// Config is the part of a gitleaks config that has a secretlint equivalent
+type Config struct {
+	Extend     Extend
+	Rules      []Rule
+	Allowlists []Allowlist
+}
+
+// Extend is the [extend] table, which builds on gitleaks' default rules
+type Extend struct {
+	UseDefault    bool
+	Path          string
+	DisabledRules []string
+}
+
+// Rule is a [[rules]] entry
+type Rule struct {
+	ID          string
+	Description string
+	Regex       string
+	SecretGroup int
+	Entropy     float64
+	Path        string
+	Allowlists  []Allowlist
+}
+
+// Allowlist is an [allowlist], [[allowlists]] or per-rule allowlist table
+type Allowlist struct {
+	Description string
+	Condition   string
+	RegexTarget string
+	Regexes     []string
+	Paths       []string
+	Stopwords   []string
+	Commits     []string
+	TargetRules []string
+}
+
+// Load reads a gitleaks config file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes a gitleaks config
+func Parse(data []byte) (*Config, error) {
+	root, err := parseTOML(string(data))
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if extend, ok := root["extend"].(table); ok {
+		cfg.Extend = Extend{
+			UseDefault:    boolValue(extend, "useDefault"),
+			Path:          stringValue(extend, "path"),
+			DisabledRules: stringsValue(extend, "disabledRules"),
+		}
+	}
+	for _, t := range tables(root, "rules") {
+		rule := Rule{
+			ID:          stringValue(t, "id"),
+			Description: stringValue(t, "description"),
+			Regex:       stringValue(t, "regex"),
+			SecretGroup: int(numberValue(t, "secretGroup")),
+			Entropy:     numberValue(t, "entropy"),
+			Path:        stringValue(t, "path"),
+		}
+		// Older configs have a single [rules.allowlist], newer ones [[rules.allowlists]]
+		for _, a := range append(tables(t, "allowlist"), tables(t, "allowlists")...) {
+			rule.Allowlists = append(rule.Allowlists, allowlist(a))
+		}
+		if rule.ID == "" {
+			return nil, fmt.Errorf("a [[rules]] entry has no id")
+		}
+		cfg.Rules = append(cfg.Rules, rule)
+	}
+	for _, a := range append(tables(root, "allowlist"), tables(root, "allowlists")...) {
+		cfg.Allowlists = append(cfg.Allowlists, allowlist(a))
+	}
+	return cfg, nil
+}
+
+func allowlist(t table) Allowlist {
+	return Allowlist{
+		Description: stringValue(t, "description"),
+		Condition:   strings.ToUpper(stringValue(t, "condition")),
+		RegexTarget: stringValue(t, "regexTarget"),
+		Regexes:     stringsValue(t, "regexes"),
+		Paths:       stringsValue(t, "paths"),
+		Stopwords:   stringsValue(t, "stopwords"),
+		Commits:     stringsValue(t, "commits"),
+		TargetRules: stringsValue(t, "targetRules"),
+	}
+}
+
+// Import is a gitleaks config converted to secretlint
+type Import struct {
+	// Pack holds the custom rules; nil if there are none
+	Pack *rulepack.Pack
+
+	// Allowlist entries go under allowlist: in .secretlintrc.yml
+	Allowlist []config.AllowlistEntry
+
+	// Ignore holds path excludes expressible as .secretignore globs
+	Ignore []string
+
+	// Notes explain what was adapted or couldn't be converted
+	Notes []string
+}
+
+// Convert maps a gitleaks config onto a rule pack, allowlist entries and
+// .secretignore patterns. known holds the rule IDs secretlint already has,
+// which imported rules must not shadow.
+func Convert(cfg *Config, packName, packVersion string, known map[string]bool) *Import {
+	result := &Import{}
+	if cfg.Extend.UseDefault {
+		result.note("[extend] useDefault: secretlint's built-in rules are always on; gitleaks' default rules aren't imported")
+	}
+	if cfg.Extend.Path != "" {
+		result.note("[extend] path %s: import that file separately", cfg.Extend.Path)
+	}
+	if len(cfg.Extend.DisabledRules) > 0 {
+		result.note("[extend] disabledRules %s: these are gitleaks default rules; disable the matching secretlint rules under rules: if needed", strings.Join(cfg.Extend.DisabledRules, ", "))
+	}
+
+	ids := make(map[string]string)
+	pack := &rulepack.Pack{Name: packName, Version: packVersion}
+	for _, rule := range cfg.Rules {
+		id := ruleID(rule.ID)
+		if known[id] {
+			id += "_CUSTOM"
+		}
+		ids[rule.ID] = id
+
+		if rule.Regex == "" {
+			result.note("rule %s: path-only rules aren't supported; SENSITIVE_FILE flags common credential files by name", rule.ID)
+			continue
+		}
+		// Patterns Go's regexp can't compile, usually for their lookarounds
+		// or backreferences, run on the backtracking engine instead
+		engine := ""
+		if _, err := regexp.Compile(rule.Regex); err != nil {
+			if _, err := regexcache.CompileRegexp2(rule.Regex); err != nil {
+				result.note("rule %s: skipped, neither Go's regexp nor regexp2 compiles its regex: %v", rule.ID, err)
+				continue
+			}
+			engine = regexcache.EngineRegexp2
+			result.note("rule %s: uses engine regexp2, since Go's regexp can't compile its regex; searches are slower and time out after %s a line", rule.ID, regexcache.MatchTimeout)
+		}
+		pattern := rule.Regex
+		if engine == regexcache.EngineRegexp2 && numberedBackreference.MatchString(pattern) && !strings.Contains(pattern, "<secret>") {
+			// regexp2 numbers named groups after the others, so naming one
+			// would change what \1 and the like refer to
+			result.note("rule %s: the whole match is reported as the secret, since naming its secret group would renumber the groups its backreferences use", rule.ID)
+		} else {
+			var err error
+			if pattern, err = secretPattern(rule.Regex, rule.SecretGroup, engine); err != nil {
+				result.note("rule %s: skipped, %v", rule.ID, err)
+				continue
+			}
+		}
+		if rule.Entropy > 0 {
+			result.note("rule %s: entropy %.1f isn't checked, so the rule may report more matches than in gitleaks", rule.ID, rule.Entropy)
+		}
+		if rule.Path != "" {
+			result.note("rule %s: the path restriction %q isn't supported; the rule applies to all files", rule.ID, rule.Path)
+		}
+		name := rule.Description
+		if name == "" {
+			name = id
+		}
+		pack.Rules = append(pack.Rules, rulepack.Rule{
+			ID:          id,
+			Name:        name,
+			Pattern:     pattern,
+			Description: fmt.Sprintf("%s detected (imported from gitleaks rule %s)", name, rule.ID),
+			Advice:      "Move this secret to an environment variable or secret manager and rotate it",
+			Engine:      engine,
+		})
+		if id != ruleID(rule.ID) {
+			result.note("rule %s: imported as %s because secretlint has a rule named %s", rule.ID, id, ruleID(rule.ID))
+		}
+
+		for _, a := range rule.Allowlists {
+			result.addAllowlist(a, []string{id})
+		}
+	}
+	if len(pack.Rules) > 0 {
+		result.Pack = pack
+	}
+
+	for _, a := range cfg.Allowlists {
+		var rules []string
+		for _, target := range a.TargetRules {
+			if id, ok := ids[target]; ok {
+				rules = append(rules, id)
+			} else {
+				rules = append(rules, ruleID(target))
+			}
+		}
+		if len(rules) == 0 {
+			// Path excludes that apply to every rule can skip files entirely
+			var paths []string
+			for _, path := range a.Paths {
+				if glob, ok := globFor(path); ok {
+					result.Ignore = append(result.Ignore, glob...)
+				} else {
+					paths = append(paths, path)
+				}
+			}
+			a.Paths = paths
+		}
+		result.addAllowlist(a, rules)
+	}
+	return result
+}
+
+func (r *Import) addAllowlist(a Allowlist, rules []string) {
+	if a.Condition == "AND" {
+		r.note("allowlist %q: condition AND isn't supported (entries match on any criterion); not imported", a.Description)
+		return
+	}
+	if len(a.Commits) > 0 {
+		r.note("allowlist %q: %d commit(s) not imported; baseline those findings with 'secretlint scan --interactive' instead", a.Description, len(a.Commits))
+	}
+	target := ""
+	switch a.RegexTarget {
+	case "match", "line":
+		target = a.RegexTarget
+	}
+	entry := config.AllowlistEntry{
+		Description: a.Description,
+		Rules:       rules,
+		Regexes:     a.Regexes,
+		Target:      target,
+		Stopwords:   a.Stopwords,
+		Paths:       a.Paths,
+	}
+	if len(entry.Regexes) == 0 {
+		entry.Target = ""
+	}
+	if len(entry.Regexes)+len(entry.Stopwords)+len(entry.Paths) > 0 {
+		r.Allowlist = append(r.Allowlist, entry)
+	}
+}
+
+func (r *Import) note(format string, args ...interface{}) {
+	r.Notes = append(r.Notes, fmt.Sprintf(format, args...))
+}
+
+// ruleID turns a gitleaks ID such as "internal-api-token" into the
+// secretlint form INTERNAL_API_TOKEN, which inline suppressions require
+func ruleID(id string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(id) {
+		if c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	converted := strings.Trim(b.String(), "_")
+	if converted == "" || converted[0] >= '0' && converted[0] <= '9' {
+		converted = "GITLEAKS_" + converted
+	}
+	return converted
+}
+
+// secretPattern names the capture group holding the secret "secret", as
+// secretlint rules do. Like gitleaks, the first group is used when
+// secretGroup isn't set.
+func secretPattern(regex string, secretGroup int, engine string) (string, error) {
+	var hasSecret bool
+	var groups int
+	if engine == regexcache.EngineRegexp2 {
+		compiled, err := regexcache.CompileRegexp2(regex)
+		if err != nil {
+			return "", fmt.Errorf("invalid regex: %w", err)
+		}
+		hasSecret = compiled.GroupNumberFromName("secret") > 0
+		groups = len(compiled.GetGroupNumbers()) - 1
+	} else {
+		compiled, err := regexp.Compile(regex)
+		if err != nil {
+			return "", fmt.Errorf("invalid regex: %w", err)
+		}
+		hasSecret = compiled.SubexpIndex("secret") > 0
+		groups = compiled.NumSubexp()
+	}
+	if hasSecret || groups == 0 {
+		return regex, nil
+	}
+	if secretGroup == 0 {
+		secretGroup = 1
+	}
+	if secretGroup > groups {
+		return "", fmt.Errorf("secretGroup %d doesn't exist in the regex", secretGroup)
+	}
+
+	group := 0
+	inClass := false
+	for i := 0; i < len(regex); i++ {
+		switch c := regex[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+		case c == '(':
+			rest := regex[i+1:]
+			named := ""
+			switch {
+			case strings.HasPrefix(rest, "?P<"):
+				named = "?P<"
+			case strings.HasPrefix(rest, "?<=") || strings.HasPrefix(rest, "?<!"):
+				// Lookbehinds don't capture
+				continue
+			case strings.HasPrefix(rest, "?<"):
+				named = "?<"
+			case strings.HasPrefix(rest, "?"):
+				continue
+			}
+			group++
+			if group != secretGroup {
+				continue
+			}
+			end := i + 1
+			if named != "" {
+				end = i + 1 + strings.Index(rest, ">") + 1
+			}
+			return regex[:i] + "(?P<secret>" + regex[end:], nil
+		}
+	}
+	return regex, nil
+}
+
+// numberedBackreference finds \1 to \9 in a regex
+var numberedBackreference = regexp.MustCompile(`(?:^|[^\\])(?:\\\\)*\\[1-9]`)
+
+// simplePath matches path regexes made of a literal, an optional leading
+// anchor or wildcard and an optional final group of alternatives
+var simplePath = regexp.MustCompile(`^(\^|\(\^\|/\)|\(\.\*\??\)|\.\*\??)?((?:[A-Za-z0-9_\-/]|\\[./\-_])*)(?:\((?:\?:)?((?:[A-Za-z0-9_\-|]|\\[./\-_])+)\))?(\$?)$`)
+
+var escaped = regexp.MustCompile(`\\(.)`)
+
+// globFor converts simple gitleaks path regexes, such as `(.*?)(jpg|png)$`,
+// `go\.sum$` or `(^|/)vendor/`, into .secretignore globs. Anything else
+// stays a regex in the allowlist.
+func globFor(regex string) ([]string, bool) {
+	m := simplePath.FindStringSubmatch(regex)
+	if m == nil {
+		return nil, false
+	}
+	prefix, literal, alternatives, anchoredEnd := m[1], escaped.ReplaceAllString(m[2], "$1"), m[3], m[4] == "$"
+	suffixes := []string{""}
+	if alternatives != "" {
+		suffixes = strings.Split(escaped.ReplaceAllString(alternatives, "$1"), "|")
+	}
+
+	var globs []string
+	for _, suffix := range suffixes {
+		path := literal + suffix
+		directory := strings.HasSuffix(path, "/")
+		if path == "" || !anchoredEnd && !directory {
+			return nil, false
+		}
+		switch prefix {
+		case "^":
+		case "":
+			if !strings.HasPrefix(path, "/") {
+				path = "**/" + path
+			}
+		case "(^|/)":
+			path = "**/" + path
+		default:
+			// A leading wildcard is only exact for names without directories
+			if strings.Contains(path, "/") {
+				return nil, false
+			}
+			path = "*" + path
+		}
+		if directory {
+			path += "**"
+		}
+		globs = append(globs, path)
+	}
+	return globs, true
+}
+
+func tables(t table, key string) []table {
+	switch value := t[key].(type) {
+	case table:
+		return []table{value}
+	case []table:
+		return value
+	}
+	return nil
+}
+
+func stringValue(t table, key string) string {
+	s, _ := t[key].(string)
+	return s
+}
+
+func stringsValue(t table, key string) []string {
+	values, _ := t[key].([]interface{})
+	var strs []string
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+func numberValue(t table, key string) float64 {
+	switch n := t[key].(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+func boolValue(t table, key string) bool {
+	b, _ := t[key].(bool)
+	return b
+}
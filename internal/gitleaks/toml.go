@@ -0,0 +1,413 @@
+package gitleaks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// table is a decoded TOML table
+type table map[string]interface{}
+
+// tomlParser decodes the TOML gitleaks configs use: tables, arrays of
+// tables, dotted keys, strings of all four kinds, multi-line arrays, inline
+// tables, numbers and booleans. Dates are kept as strings.
+type tomlParser struct {
+	data string
+	pos  int
+	line int
+}
+
+func parseTOML(data string) (table, error) {
+	p := &tomlParser{data: strings.ReplaceAll(data, "\r\n", "\n"), line: 1}
+	root := table{}
+	current := root
+
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return root, nil
+		}
+		if p.peek() == '[' {
+			array := strings.HasPrefix(p.data[p.pos:], "[[")
+			if array {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			p.skipBlank(false)
+			path, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			p.skipBlank(false)
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			if !strings.HasPrefix(p.data[p.pos:], closing) {
+				return nil, p.errorf("expected %s after table name", closing)
+			}
+			p.pos += len(closing)
+			if current, err = p.header(root, path, array); err != nil {
+				return nil, err
+			}
+		} else {
+			path, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			p.skipBlank(false)
+			if p.eof() || p.peek() != '=' {
+				return nil, p.errorf("expected = after key %s", strings.Join(path, "."))
+			}
+			p.pos++
+			p.skipBlank(false)
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.assign(current, path, value); err != nil {
+				return nil, err
+			}
+		}
+		p.skipBlank(false)
+		if !p.eof() && p.peek() != '\n' {
+			return nil, p.errorf("unexpected %q at end of line", p.peek())
+		}
+	}
+}
+
+// header returns the table a [name] or [[name]] header opens
+func (p *tomlParser) header(root table, path []string, array bool) (table, error) {
+	parent := root
+	for _, name := range path[:len(path)-1] {
+		next, err := p.descend(parent, name)
+		if err != nil {
+			return nil, err
+		}
+		parent = next
+	}
+	name := path[len(path)-1]
+	if array {
+		var tables []table
+		if existing, ok := parent[name]; ok {
+			if tables, ok = existing.([]table); !ok {
+				return nil, p.errorf("%s is not an array of tables", strings.Join(path, "."))
+			}
+		}
+		t := table{}
+		parent[name] = append(tables, t)
+		return t, nil
+	}
+	return p.descend(parent, name)
+}
+
+// descend returns the sub-table name, creating it if needed; for an array
+// of tables it is the last element, as in [[rules]] followed by [rules.allowlist]
+func (p *tomlParser) descend(parent table, name string) (table, error) {
+	switch existing := parent[name].(type) {
+	case nil:
+		t := table{}
+		parent[name] = t
+		return t, nil
+	case table:
+		return existing, nil
+	case []table:
+		return existing[len(existing)-1], nil
+	}
+	return nil, p.errorf("%s is not a table", name)
+}
+
+func (p *tomlParser) assign(t table, path []string, value interface{}) error {
+	for _, name := range path[:len(path)-1] {
+		next, err := p.descend(t, name)
+		if err != nil {
+			return err
+		}
+		t = next
+	}
+	name := path[len(path)-1]
+	if _, ok := t[name]; ok {
+		return p.errorf("duplicate key %s", strings.Join(path, "."))
+	}
+	t[name] = value
+	return nil
+}
+
+// key reads a bare, quoted or dotted key
+func (p *tomlParser) key() ([]string, error) {
+	var path []string
+	for {
+		p.skipBlank(false)
+		if p.eof() {
+			return nil, p.errorf("expected a key")
+		}
+		var part string
+		switch p.peek() {
+		case '"', '\'':
+			s, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			part = s
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("invalid key character %q", p.peek())
+			}
+			part = p.data[start:p.pos]
+		}
+		path = append(path, part)
+		p.skipBlank(false)
+		if p.eof() || p.peek() != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) value() (interface{}, error) {
+	if p.eof() {
+		return nil, p.errorf("expected a value")
+	}
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.str()
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inlineTable()
+	}
+
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(",]}#\n", rune(p.peek())) {
+		p.pos++
+	}
+	raw := strings.TrimSpace(p.data[start:p.pos])
+	switch raw {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "":
+		return nil, p.errorf("expected a value")
+	}
+	number := strings.ReplaceAll(raw, "_", "")
+	if n, err := strconv.ParseInt(number, 0, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, nil
+	}
+	// Dates and times aren't used by gitleaks; keep them as written
+	return raw, nil
+}
+
+func (p *tomlParser) array() ([]interface{}, error) {
+	p.pos++
+	var values []interface{}
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		p.skipBlank(true)
+		if !p.eof() && p.peek() == ',' {
+			p.pos++
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() (table, error) {
+	p.pos++
+	t := table{}
+	for {
+		p.skipBlank(false)
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return t, nil
+		}
+		path, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		if p.eof() || p.peek() != '=' {
+			return nil, p.errorf("expected = in inline table")
+		}
+		p.pos++
+		p.skipBlank(false)
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.assign(t, path, value); err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		if !p.eof() && p.peek() == ',' {
+			p.pos++
+		}
+	}
+}
+
+// str reads a basic, literal or multi-line string
+func (p *tomlParser) str() (string, error) {
+	quote := p.data[p.pos : p.pos+1]
+	if strings.HasPrefix(p.data[p.pos:], quote+quote+quote) {
+		p.pos += 3
+		// A newline right after the opening quotes is not part of the string
+		if !p.eof() && p.peek() == '\n' {
+			p.pos++
+			p.line++
+		}
+		end := strings.Index(p.data[p.pos:], quote+quote+quote)
+		if end < 0 {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		// Up to two quotes may directly precede the closing delimiter
+		for end+3 < len(p.data[p.pos:]) && p.data[p.pos+end+3:p.pos+end+4] == quote {
+			end++
+		}
+		raw := p.data[p.pos : p.pos+end]
+		p.line += strings.Count(raw, "\n")
+		p.pos += end + 3
+		if quote == "'" {
+			return raw, nil
+		}
+		return p.unescape(raw, true)
+	}
+
+	p.pos++
+	end := strings.IndexAny(p.data[p.pos:], quote+"\n")
+	if quote == `"` {
+		// Skip escaped quotes in basic strings
+		end = -1
+		for i := p.pos; i < len(p.data) && p.data[i] != '\n'; i++ {
+			if p.data[i] == '\\' {
+				i++
+				continue
+			}
+			if p.data[i] == '"' {
+				end = i - p.pos
+				break
+			}
+		}
+	}
+	if end < 0 || p.data[p.pos+end] != quote[0] {
+		return "", p.errorf("unterminated string")
+	}
+	raw := p.data[p.pos : p.pos+end]
+	p.pos += end + 1
+	if quote == "'" {
+		return raw, nil
+	}
+	return p.unescape(raw, false)
+}
+
+func (p *tomlParser) unescape(raw string, multiline bool) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(raw) {
+			return "", p.errorf("invalid escape at end of string")
+		}
+		switch raw[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case '"', '\\':
+			b.WriteByte(raw[i])
+		case 'u', 'U':
+			size := 4
+			if raw[i] == 'U' {
+				size = 8
+			}
+			if i+size >= len(raw) {
+				return "", p.errorf("invalid unicode escape")
+			}
+			code, err := strconv.ParseUint(raw[i+1:i+1+size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return "", p.errorf("invalid unicode escape")
+			}
+			b.WriteRune(rune(code))
+			i += size
+		default:
+			// A backslash at the end of a line in a multi-line string joins
+			// it with the next non-blank text
+			if multiline && strings.TrimLeft(raw[i:i+strings.IndexByte(raw[i:]+"\n", '\n')], " \t") == "" {
+				for i < len(raw) && strings.ContainsRune(" \t\n", rune(raw[i])) {
+					i++
+				}
+				i--
+				continue
+			}
+			return "", p.errorf("invalid escape \\%c", raw[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// skipBlank skips spaces and comments, and newlines too if requested
+func (p *tomlParser) skipBlank(newlines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		case c == '\n' && newlines:
+			p.pos++
+			p.line++
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) peek() byte {
+	return p.data[p.pos]
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
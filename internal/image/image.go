@@ -0,0 +1,271 @@
+package image
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// maxFileSize skips files in layers too large to be config or source
+const maxFileSize = 10 << 20
+
+// maxMetadataSize bounds the JSON documents (manifests, configs) read into memory
+const maxMetadataSize = 4 << 20
+
+// Config is the part of an image config that can hold baked-in secrets
+type Config struct {
+	Env     []string          // ENV instructions, as KEY=value
+	Labels  map[string]string // LABEL instructions
+	History []string          // the instruction that created each layer, including build args
+}
+
+// File is a regular file found in one of the image's layers
+type File struct {
+	Layer   int // 1-based, base layer first
+	Path    string
+	Content []byte
+}
+
+// Save exports an image to a tarball with docker or podman, pulling it if it
+// isn't available locally and pull is set. A ref naming an existing file (from
+// 'docker save' or an OCI layout tarball) is used as is. cleanup removes any
+// temporary file.
+func Save(ref string, pull bool) (string, func(), error) {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		return ref, func() {}, nil
+	}
+
+	tool := ""
+	for _, candidate := range []string{"docker", "podman"} {
+		if _, err := exec.LookPath(candidate); err == nil {
+			tool = candidate
+			break
+		}
+	}
+	if tool == "" {
+		return "", nil, fmt.Errorf("scanning %s needs docker or podman; alternatively pass a tarball from 'docker save'", ref)
+	}
+
+	if err := exec.Command(tool, "image", "inspect", ref).Run(); err != nil {
+		if !pull {
+			return "", nil, fmt.Errorf("image %s is not available locally and offline mode forbids pulling it", ref)
+		}
+		if output, err := exec.Command(tool, "pull", ref).CombinedOutput(); err != nil {
+			return "", nil, fmt.Errorf("failed to pull %s: %s", ref, strings.TrimSpace(string(output)))
+		}
+	}
+
+	file, err := ioutil.TempFile("", "secretlint-image-*.tar")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	file.Close()
+	cleanup := func() { os.Remove(file.Name()) }
+	if output, err := exec.Command(tool, "save", "-o", file.Name(), ref).CombinedOutput(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to save %s: %s", ref, strings.TrimSpace(string(output)))
+	}
+	return file.Name(), cleanup, nil
+}
+
+// Walk reads an image tarball in 'docker save' or OCI layout format, returning
+// its config and calling fn for every regular text-sized file in its layers
+func Walk(tarPath string, fn func(File)) (*Config, error) {
+	layers, cfg, err := readMetadata(tarPath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(tarPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image tarball: %w", err)
+	}
+	defer file.Close()
+
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return cfg, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image tarball: %w", err)
+		}
+		if layer, ok := layers[path.Clean(header.Name)]; ok {
+			if err := walkLayer(reader, layer, fn); err != nil {
+				return nil, fmt.Errorf("failed to read layer %s: %w", header.Name, err)
+			}
+		}
+	}
+}
+
+// walkLayer streams one (possibly gzipped) layer tar
+func walkLayer(r io.Reader, layer int, fn func(File)) error {
+	buffered := bufio.NewReader(r)
+	var layerReader io.Reader = buffered
+	if magic, _ := buffered.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		layerReader = gz
+	}
+
+	reader := tar.NewReader(layerReader)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Whiteouts only record deletions; the deleted file is still in an earlier layer
+		if header.Typeflag != tar.TypeReg || header.Size > maxFileSize || strings.HasPrefix(path.Base(header.Name), ".wh.") {
+			continue
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		fn(File{Layer: layer, Path: strings.TrimPrefix(path.Clean(header.Name), "/"), Content: content})
+	}
+}
+
+// dockerManifest is an entry of manifest.json written by 'docker save'
+type dockerManifest struct {
+	Config string   `json:"Config"`
+	Layers []string `json:"Layers"`
+}
+
+// ociDescriptor points at a blob in an OCI layout
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+// ociManifest is an OCI image manifest or index
+type ociManifest struct {
+	Config    ociDescriptor   `json:"config"`
+	Layers    []ociDescriptor `json:"layers"`
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// imageConfig is the subset of the image config JSON that is scanned
+type imageConfig struct {
+	Config struct {
+		Env    []string          `json:"Env"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+	History []struct {
+		CreatedBy string `json:"created_by"`
+	} `json:"history"`
+}
+
+// readMetadata makes a first pass over the tarball, keeping the small JSON
+// documents, and resolves which entries are layers (mapped to their position)
+func readMetadata(tarPath string) (map[string]int, *Config, error) {
+	file, err := os.Open(tarPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open image tarball: %w", err)
+	}
+	defer file.Close()
+
+	documents := make(map[string][]byte)
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read image tarball: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || header.Size > maxMetadataSize {
+			continue
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read image tarball: %w", err)
+		}
+		if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) || bytes.HasPrefix(bytes.TrimSpace(content), []byte("[")) {
+			documents[path.Clean(header.Name)] = content
+		}
+	}
+
+	var configPath string
+	var layerPaths []string
+	if data, ok := documents["manifest.json"]; ok {
+		var manifests []dockerManifest
+		if err := json.Unmarshal(data, &manifests); err != nil || len(manifests) == 0 {
+			return nil, nil, fmt.Errorf("invalid manifest.json in image tarball")
+		}
+		configPath = manifests[0].Config
+		layerPaths = manifests[0].Layers
+	} else if data, ok := documents["index.json"]; ok {
+		manifest, err := resolveOCIManifest(documents, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		configPath = blobPath(manifest.Config.Digest)
+		for _, layer := range manifest.Layers {
+			layerPaths = append(layerPaths, blobPath(layer.Digest))
+		}
+	} else {
+		return nil, nil, fmt.Errorf("%s is not a 'docker save' or OCI image tarball", tarPath)
+	}
+
+	layers := make(map[string]int)
+	for i, layerPath := range layerPaths {
+		layers[path.Clean(layerPath)] = i + 1
+	}
+
+	cfg := &Config{}
+	if data, ok := documents[path.Clean(configPath)]; ok {
+		var parsed imageConfig
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, nil, fmt.Errorf("invalid image config: %w", err)
+		}
+		cfg.Env = parsed.Config.Env
+		cfg.Labels = parsed.Config.Labels
+		for _, entry := range parsed.History {
+			cfg.History = append(cfg.History, entry.CreatedBy)
+		}
+	}
+	return layers, cfg, nil
+}
+
+// resolveOCIManifest follows an OCI index (and nested indexes) to the first
+// image manifest
+func resolveOCIManifest(documents map[string][]byte, data []byte) (*ociManifest, error) {
+	for depth := 0; depth < 4; depth++ {
+		var manifest ociManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid OCI manifest: %w", err)
+		}
+		if len(manifest.Manifests) == 0 {
+			return &manifest, nil
+		}
+		next, ok := documents[blobPath(manifest.Manifests[0].Digest)]
+		if !ok {
+			return nil, fmt.Errorf("OCI manifest %s missing from image tarball", manifest.Manifests[0].Digest)
+		}
+		data = next
+	}
+	return nil, fmt.Errorf("OCI index nested too deeply")
+}
+
+// blobPath maps a digest like sha256:abc to its OCI layout path
+func blobPath(digest string) string {
+	return path.Join("blobs", strings.Replace(digest, ":", "/", 1))
+}
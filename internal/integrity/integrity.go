@@ -0,0 +1,146 @@
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileName is the manifest of recorded hashes kept under .git/secretlint/
+const FileName = "integrity.json"
+
+// SchemaVersion is bumped whenever the manifest layout changes incompatibly
+const SchemaVersion = 1
+
+// Manifest records the state of the hook and config files at install time
+type Manifest struct {
+	Version    int                  `json:"version"`
+	RecordedAt time.Time            `json:"recorded_at"`
+	Files      map[string]FileState `json:"files"`
+}
+
+// FileState is the recorded content hash of one file; hooks must also stay
+// executable, or git silently skips them
+type FileState struct {
+	SHA256     string `json:"sha256"`
+	Executable bool   `json:"executable,omitempty"`
+}
+
+// Change describes a file that no longer matches the manifest
+type Change struct {
+	Path    string
+	Problem string
+}
+
+// Path returns the manifest location inside a git directory
+func Path(gitDir string) string {
+	return filepath.Join(gitDir, "secretlint", FileName)
+}
+
+// Record hashes the given files, relative to root. Files that don't exist
+// are left out, so verification reports them if they appear later.
+func Record(root string, paths []string) (*Manifest, error) {
+	manifest := &Manifest{
+		Version:    SchemaVersion,
+		RecordedAt: time.Now().UTC(),
+		Files:      make(map[string]FileState),
+	}
+	for _, path := range paths {
+		state, err := stat(filepath.Join(root, path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files[path] = state
+	}
+	return manifest, nil
+}
+
+// Save writes the manifest
+func (m *Manifest) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode integrity manifest: %w", err)
+	}
+	// Write then rename so an interrupted save never leaves a manifest that
+	// fails to parse
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write integrity manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write integrity manifest: %w", err)
+	}
+	return nil
+}
+
+// Load reads a manifest written by Save
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no recorded hashes at %s (run 'secretlint init' or 'secretlint hook record')", path)
+		}
+		return nil, fmt.Errorf("failed to read integrity manifest: %w", err)
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse integrity manifest %s: %w", path, err)
+	}
+	if manifest.Version > SchemaVersion {
+		return nil, fmt.Errorf("integrity manifest %s uses schema version %d, newer than this secretlint supports (%d)", path, manifest.Version, SchemaVersion)
+	}
+	return manifest, nil
+}
+
+// Verify compares the files under root with the manifest. paths lists every
+// file that should be checked, so files created since recording are reported.
+func (m *Manifest) Verify(root string, paths []string) ([]Change, error) {
+	var changes []Change
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	for _, path := range sorted {
+		recorded, wasRecorded := m.Files[path]
+		current, err := stat(filepath.Join(root, path))
+		switch {
+		case os.IsNotExist(err) && wasRecorded:
+			changes = append(changes, Change{Path: path, Problem: "deleted"})
+		case os.IsNotExist(err):
+		case err != nil:
+			return nil, err
+		case !wasRecorded:
+			changes = append(changes, Change{Path: path, Problem: "created after hashes were recorded"})
+		case current.SHA256 != recorded.SHA256:
+			changes = append(changes, Change{Path: path, Problem: "modified"})
+		case recorded.Executable && !current.Executable:
+			changes = append(changes, Change{Path: path, Problem: "no longer executable, so git skips it"})
+		}
+	}
+	return changes, nil
+}
+
+func stat(path string) (FileState, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileState{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FileState{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return FileState{
+		SHA256:     hex.EncodeToString(sum[:]),
+		Executable: info.Mode()&0111 != 0,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"secretlint/internal/fix"
+	"secretlint/internal/scanner"
+)
+
+// Targets lists the supported secret managers
+var Targets = []string{"vault", "aws-sm", "sops"}
+
+// Secret is a detected value prepared for migration
+type Secret struct {
+	Name     string
+	Value    string
+	RuleID   string
+	Location string
+	FilePath string
+}
+
+// Collect deduplicates findings into named secrets, one per distinct value
+func Collect(findings []scanner.Finding) []Secret {
+	var secrets []Secret
+	byValue := make(map[string]bool)
+	names := make(map[string]bool)
+
+	for _, finding := range findings {
+		if finding.Secret == "" || byValue[finding.Secret] {
+			continue
+		}
+		byValue[finding.Secret] = true
+
+		name := fix.SuggestEnvVar(finding)
+		for i := 2; names[name]; i++ {
+			name = fmt.Sprintf("%s_%d", fix.SuggestEnvVar(finding), i)
+		}
+		names[name] = true
+
+		secrets = append(secrets, Secret{
+			Name:     name,
+			Value:    finding.Secret,
+			RuleID:   finding.RuleID,
+			Location: fmt.Sprintf("%s:%d", finding.FilePath, finding.LineNum),
+			FilePath: finding.FilePath,
+		})
+	}
+
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+	return secrets
+}
+
+// Plan renders the commands or manifests that move secrets into the target manager.
+// prefix is the Vault path, Secrets Manager name prefix or SOPS file name.
+func Plan(target, prefix string, secrets []Secret) (string, error) {
+	var b strings.Builder
+
+	switch target {
+	case "vault":
+		planVault(&b, prefix, secrets)
+	case "aws-sm":
+		planAWS(&b, prefix, secrets)
+	case "sops":
+		planSOPS(&b, prefix, secrets)
+	default:
+		return "", fmt.Errorf("unsupported target %q (supported: %s)", target, strings.Join(Targets, ", "))
+	}
+
+	b.WriteString("\n# Code-side references (read the value from the environment):\n")
+	for _, secret := range secrets {
+		fmt.Fprintf(&b, "#   %s (%s): %s\n", secret.Location, secret.RuleID, fix.Reference(secret.FilePath, secret.Name))
+	}
+
+	return b.String(), nil
+}
+
+func planVault(b *strings.Builder, path string, secrets []Secret) {
+	b.WriteString("# Store the secrets in Vault's KV v2 engine (values are read from stdin,\n")
+	b.WriteString("# keeping them out of process listings)\n")
+	for _, secret := range secrets {
+		fmt.Fprintf(b, "printf '%%s' %s | vault kv put %s/%s value=-\n", shellQuote(secret.Value), path, secret.Name)
+	}
+
+	b.WriteString("\n# Expose them to the application, e.g. with a Vault Agent template:\n")
+	for _, secret := range secrets {
+		fmt.Fprintf(b, "#   %s={{ with secret \"%s/data/%s\" }}{{ .Data.data.value }}{{ end }}\n", secret.Name, path, secret.Name)
+	}
+	b.WriteString("# or export them in a shell:\n")
+	for _, secret := range secrets {
+		fmt.Fprintf(b, "#   export %s=\"$(vault kv get -field=value %s/%s)\"\n", secret.Name, path, secret.Name)
+	}
+}
+
+func planAWS(b *strings.Builder, prefix string, secrets []Secret) {
+	b.WriteString("# Store the secrets in AWS Secrets Manager (values are read from stdin,\n")
+	b.WriteString("# keeping them out of process listings)\n")
+	for _, secret := range secrets {
+		fmt.Fprintf(b, "printf '%%s' %s | aws secretsmanager create-secret --name %s/%s --secret-string file:///dev/stdin\n", shellQuote(secret.Value), prefix, secret.Name)
+	}
+
+	b.WriteString("\n# Load them into the environment at deploy time:\n")
+	for _, secret := range secrets {
+		fmt.Fprintf(b, "#   export %s=\"$(aws secretsmanager get-secret-value --secret-id %s/%s --query SecretString --output text)\"\n", secret.Name, prefix, secret.Name)
+	}
+}
+
+func planSOPS(b *strings.Builder, file string, secrets []Secret) {
+	fmt.Fprintf(b, "# Write the manifest below to %s, then encrypt it in place:\n", file)
+	fmt.Fprintf(b, "#   sops --encrypt --in-place %s\n", file)
+	b.WriteString("# (configure recipients in .sops.yaml first, e.g. an age or KMS key)\n\n")
+	fmt.Fprintf(b, "# --- %s ---\n", file)
+	for _, secret := range secrets {
+		fmt.Fprintf(b, "%s: %s\n", secret.Name, yamlQuote(secret.Value))
+	}
+	b.WriteString("# ---\n")
+
+	b.WriteString("\n# Run the application with the decrypted values in its environment:\n")
+	fmt.Fprintf(b, "#   sops exec-env %s '<your start command>'\n", file)
+}
+
+// shellQuote wraps a value in single quotes for POSIX shells
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+// yamlQuote wraps a value in single quotes for YAML
+func yamlQuote(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
@@ -0,0 +1,132 @@
+package owners
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Unowned groups findings in files no CODEOWNERS rule covers
+const Unowned = "(unowned)"
+
+// codeownersLocations are checked in the order GitHub uses
+var codeownersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// rule is one CODEOWNERS line
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Codeowners resolves file owners; the last matching rule wins
+type Codeowners struct {
+	Path  string
+	rules []rule
+}
+
+// Load reads the repository's CODEOWNERS file. A repository without one
+// yields an empty set that owns nothing.
+func Load(repoRoot string) (*Codeowners, error) {
+	for _, location := range codeownersLocations {
+		path := filepath.Join(repoRoot, location)
+		file, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer file.Close()
+
+		codeowners := &Codeowners{Path: location}
+		lineScanner := bufio.NewScanner(file)
+		for lineScanner.Scan() {
+			line := strings.TrimSpace(lineScanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if i := strings.Index(line, " #"); i >= 0 {
+				line = line[:i]
+			}
+			fields := strings.Fields(line)
+			pattern, err := compilePattern(fields[0])
+			if err != nil {
+				continue
+			}
+			codeowners.rules = append(codeowners.rules, rule{pattern: pattern, owners: fields[1:]})
+		}
+		if err := lineScanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return codeowners, nil
+	}
+
+	return &Codeowners{}, nil
+}
+
+// Owners returns the owners of a repo-relative path, or nil if unowned
+func (c *Codeowners) Owners(filePath string) []string {
+	filePath = strings.TrimPrefix(filepath.ToSlash(filePath), "./")
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(filePath) {
+			// A rule without owners explicitly leaves the path unowned
+			if len(c.rules[i].owners) == 0 {
+				return nil
+			}
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// compilePattern converts gitignore-style CODEOWNERS patterns to a regex
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("(^|/)")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					// "**/" matches zero or more directories
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if directory {
+		b.WriteString("/")
+	} else {
+		// A pattern naming a directory owns everything beneath it
+		b.WriteString("(/|$)")
+	}
+
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,120 @@
+package redact
+
+import (
+	"fmt"
+	"strings"
+
+	"secretlint/internal/scanner"
+)
+
+// contextLines is the number of unchanged lines shown around each change,
+// matching git's default so patches apply cleanly with `git apply`
+const contextLines = 3
+
+// FileRedaction holds the original and redacted lines of a single file
+type FileRedaction struct {
+	FilePath       string
+	Original       []string
+	Redacted       []string
+	Replacements   int
+	noFinalNewline bool
+}
+
+// Redact replaces every detected secret in content with its masked placeholder
+func Redact(filePath, content string, findings []scanner.Finding) *FileRedaction {
+	lines := strings.Split(content, "\n")
+
+	result := &FileRedaction{FilePath: filePath}
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		result.noFinalNewline = true
+	}
+
+	result.Original = lines
+	result.Redacted = make([]string, len(lines))
+	copy(result.Redacted, lines)
+
+	for _, finding := range findings {
+		idx := finding.LineNum - 1
+		if idx < 0 || idx >= len(lines) || finding.Secret == "" {
+			continue
+		}
+		if !strings.Contains(result.Redacted[idx], finding.Secret) {
+			continue
+		}
+		result.Redacted[idx] = strings.Replace(result.Redacted[idx], finding.Secret, finding.MaskValue(finding.Secret), -1)
+		result.Replacements++
+	}
+
+	return result
+}
+
+// Content returns the redacted file content
+func (r *FileRedaction) Content() string {
+	content := strings.Join(r.Redacted, "\n")
+	if !r.noFinalNewline {
+		content += "\n"
+	}
+	return content
+}
+
+// Patch renders the redaction as a unified diff, or "" if nothing changed
+func (r *FileRedaction) Patch() string {
+	var changed []int
+	for i := range r.Original {
+		if r.Original[i] != r.Redacted[i] {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", r.FilePath, r.FilePath)
+	fmt.Fprintf(&b, "--- a/%s\n", r.FilePath)
+	fmt.Fprintf(&b, "+++ b/%s\n", r.FilePath)
+
+	for i := 0; i < len(changed); {
+		// Extend the hunk while the next change falls within its context
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j] <= 2*contextLines {
+			j++
+		}
+
+		start := changed[i] - contextLines
+		if start < 0 {
+			start = 0
+		}
+		end := changed[j] + contextLines + 1
+		if end > len(r.Original) {
+			end = len(r.Original)
+		}
+
+		count := end - start
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", start+1, count, start+1, count)
+		for k := start; k < end; k++ {
+			if r.Original[k] == r.Redacted[k] {
+				b.WriteString(" " + r.Original[k] + "\n")
+				r.writeNewlineMarker(&b, k)
+				continue
+			}
+			b.WriteString("-" + r.Original[k] + "\n")
+			r.writeNewlineMarker(&b, k)
+			b.WriteString("+" + r.Redacted[k] + "\n")
+			r.writeNewlineMarker(&b, k)
+		}
+
+		i = j + 1
+	}
+
+	return b.String()
+}
+
+// writeNewlineMarker flags the last line of a file that lacks a trailing newline
+func (r *FileRedaction) writeNewlineMarker(b *strings.Builder, idx int) {
+	if r.noFinalNewline && idx == len(r.Original)-1 {
+		b.WriteString("\\ No newline at end of file\n")
+	}
+}
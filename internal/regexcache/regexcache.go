@@ -0,0 +1,183 @@
+// Package regexcache avoids compiling rule patterns more often than needed.
+// Within a process each pattern is compiled once, however many times the
+// config and rule packs are loaded. Across hook runs, the patterns known to
+// compile are remembered under the git common dir, so validating hundreds
+// of custom and pack rules doesn't compile them all on every commit.
+package regexcache
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dlclark/regexp2"
+)
+
+// Engines a rule can select with engine:. RE2, Go's regexp, is the default
+// and matches in linear time. regexp2 backtracks, which lets it run
+// lookarounds and backreferences, so each of its searches is bounded by
+// MatchTimeout.
+const (
+	EngineRE2     = "re2"
+	EngineRegexp2 = "regexp2"
+)
+
+// MatchTimeout bounds one regexp2 search of a line, so a pattern that
+// backtracks catastrophically can't hang a hook
+const MatchTimeout = 100 * time.Millisecond
+
+// FileName lists the hashes of valid patterns, under .git/secretlint/
+const FileName = "patterns.valid"
+
+var (
+	mu       sync.Mutex
+	compiled = make(map[string]*regexp.Regexp)
+	// backtracking holds the patterns compiled with regexp2
+	backtracking = make(map[string]*regexp2.Regexp)
+
+	// valid holds the hashes read from FileName; path is empty outside a
+	// repository, where nothing is persisted
+	loadOnce sync.Once
+	valid    map[string]bool
+	path     string
+)
+
+// Compile compiles pattern, or returns the regexp compiled for it earlier in
+// this process. Compiled regexps are safe for concurrent use.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	mu.Lock()
+	re, ok := compiled[pattern]
+	mu.Unlock()
+	if ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	compiled[pattern] = re
+	mu.Unlock()
+	return re, nil
+}
+
+// CompileRegexp2 compiles pattern with regexp2 in its RE2-compatible mode,
+// or returns the regexp compiled for it earlier in this process. Its
+// searches time out after MatchTimeout.
+func CompileRegexp2(pattern string) (*regexp2.Regexp, error) {
+	mu.Lock()
+	re, ok := backtracking[pattern]
+	mu.Unlock()
+	if ok {
+		return re, nil
+	}
+	re, err := regexp2.Compile(pattern, regexp2.RE2)
+	if err != nil {
+		return nil, err
+	}
+	re.MatchTimeout = MatchTimeout
+	mu.Lock()
+	backtracking[pattern] = re
+	mu.Unlock()
+	return re, nil
+}
+
+// ValidateEngine reports whether pattern compiles with engine, "" meaning RE2
+func ValidateEngine(engine, pattern string) error {
+	switch engine {
+	case "", EngineRE2:
+		return Validate(pattern)
+	case EngineRegexp2:
+		_, err := CompileRegexp2(pattern)
+		return err
+	}
+	return fmt.Errorf("unknown engine %q (use %s or %s)", engine, EngineRE2, EngineRegexp2)
+}
+
+// Validate reports whether pattern compiles. Patterns that compiled in an
+// earlier run with the same Go version aren't compiled again.
+func Validate(pattern string) error {
+	loadOnce.Do(load)
+	key := hash(pattern)
+	mu.Lock()
+	known := valid[key]
+	mu.Unlock()
+	if known {
+		return nil
+	}
+	if _, err := Compile(pattern); err != nil {
+		return err
+	}
+	remember(key)
+	return nil
+}
+
+// header ties the file to the Go version whose regexp syntax it vouches for
+func header() string {
+	return "regexcache " + runtime.Version()
+}
+
+func hash(pattern string) string {
+	sum := sha256.Sum256([]byte(pattern))
+	return hex.EncodeToString(sum[:16])
+}
+
+// load reads the valid hashes of the current repository, discarding a file
+// written by another Go version
+func load() {
+	valid = make(map[string]bool)
+	output, err := exec.Command("git", "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return
+	}
+	path = filepath.Join(strings.TrimSpace(string(output)), "secretlint", FileName)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() || scanner.Text() != header() {
+		os.Remove(path)
+		return
+	}
+	for scanner.Scan() {
+		valid[scanner.Text()] = true
+	}
+}
+
+// remember appends a valid hash to the file. The cache is an optimization,
+// so failing to write it is ignored.
+func remember(key string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if valid[key] {
+		return
+	}
+	valid[key] = true
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		file.WriteString(header() + "\n")
+	}
+	file.WriteString(key + "\n")
+}
@@ -0,0 +1,97 @@
+package rego
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	opa "github.com/open-policy-agent/opa/v1/rego"
+)
+
+// Query is the document a policy defines its decisions in
+const Query = "data.secretlint"
+
+// Input is the document a policy is evaluated against
+type Input struct {
+	Repo     Repo      `json:"repo"`
+	Scope    string    `json:"scope"`
+	Findings []Finding `json:"findings"`
+}
+
+// Repo describes the scanned repository
+type Repo struct {
+	Name   string `json:"name"`
+	Remote string `json:"remote,omitempty"`
+	Branch string `json:"branch,omitempty"`
+}
+
+// Finding is the policy's view of a finding. ID keys the decisions about it.
+type Finding struct {
+	ID          string   `json:"id"`
+	RuleID      string   `json:"rule_id"`
+	RuleName    string   `json:"rule_name"`
+	Severity    string   `json:"severity"`
+	File        string   `json:"file"`
+	Line        int      `json:"line"`
+	KeyPath     string   `json:"key_path,omitempty"`
+	Description string   `json:"description"`
+	Fingerprint string   `json:"fingerprint"`
+	Commit      string   `json:"commit,omitempty"`
+	Author      string   `json:"author,omitempty"`
+	Owners      []string `json:"owners,omitempty"`
+}
+
+// Decisions are what a policy concluded, keyed by finding ID: Severity
+// reclassifies findings ("error" escalates, "warning" downgrades) and
+// Waive drops them, with the reason as value
+type Decisions struct {
+	Severity map[string]string `json:"severity"`
+	Waive    map[string]string `json:"waive"`
+}
+
+// Evaluate runs a Rego policy (package secretlint) over the input with the
+// embedded OPA. Policies written in Rego v0, for older opa releases, are
+// accepted too.
+func Evaluate(policy string, input *Input) (*Decisions, error) {
+	// The policy sees the input under its JSON field names
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+
+	ctx := context.Background()
+	query, err := opa.New(opa.Query(Query), opa.Module("policy.rego", policy)).PrepareForEval(ctx)
+	if err != nil {
+		v0, v0Err := opa.New(opa.Query(Query), opa.Module("policy.rego", policy), opa.SetRegoVersion(ast.RegoV0)).PrepareForEval(ctx)
+		if v0Err != nil {
+			return nil, fmt.Errorf("failed to compile policy.rego: %w", err)
+		}
+		query = v0
+	}
+	results, err := query.Eval(ctx, opa.EvalInput(document))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate policy.rego: %w", err)
+	}
+
+	decisions := &Decisions{}
+	if len(results) > 0 && len(results[0].Expressions) > 0 {
+		value, err := json.Marshal(results[0].Expressions[0].Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy decisions: %w", err)
+		}
+		if err := json.Unmarshal(value, decisions); err != nil {
+			return nil, fmt.Errorf("invalid policy decisions: %w", err)
+		}
+	}
+	for id, severity := range decisions.Severity {
+		if severity != "error" && severity != "warning" {
+			return nil, fmt.Errorf("policy.rego sets severity %q for finding %s (use error or warning)", severity, id)
+		}
+	}
+	return decisions, nil
+}
@@ -0,0 +1,73 @@
+package report
+
+import "sort"
+
+// Diff compares two reports by fingerprint
+type Diff struct {
+	Introduced []Finding `json:"introduced"`
+	Resolved   []Finding `json:"resolved"`
+	Persisting []Finding `json:"persisting"`
+}
+
+// Compare returns the findings introduced, resolved and persisting between
+// an old and a new report. Each fingerprint appears once, using its first
+// occurrence in the respective report.
+func Compare(old, current *Report) *Diff {
+	oldIndex := indexByFingerprint(old)
+	newIndex := indexByFingerprint(current)
+
+	diff := &Diff{
+		Introduced: []Finding{},
+		Resolved:   []Finding{},
+		Persisting: []Finding{},
+	}
+	for fingerprint, finding := range newIndex {
+		if _, ok := oldIndex[fingerprint]; ok {
+			diff.Persisting = append(diff.Persisting, finding)
+		} else {
+			diff.Introduced = append(diff.Introduced, finding)
+		}
+	}
+	for fingerprint, finding := range oldIndex {
+		if _, ok := newIndex[fingerprint]; !ok {
+			diff.Resolved = append(diff.Resolved, finding)
+		}
+	}
+
+	sortFindings(diff.Introduced)
+	sortFindings(diff.Resolved)
+	sortFindings(diff.Persisting)
+	return diff
+}
+
+func indexByFingerprint(r *Report) map[string]Finding {
+	index := make(map[string]Finding, len(r.Findings))
+	for _, finding := range r.Findings {
+		if _, ok := index[finding.Fingerprint]; !ok {
+			index[finding.Fingerprint] = finding
+		}
+	}
+	return index
+}
+
+// sortFindings orders findings by location so diff output is stable
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Repo != findings[j].Repo {
+			return findings[i].Repo < findings[j].Repo
+		}
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		if findings[i].Column != findings[j].Column {
+			return findings[i].Column < findings[j].Column
+		}
+		if findings[i].RuleID != findings[j].RuleID {
+			return findings[i].RuleID < findings[j].RuleID
+		}
+		return findings[i].Fingerprint < findings[j].Fingerprint
+	})
+}
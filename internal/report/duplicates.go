@@ -0,0 +1,69 @@
+package report
+
+import "secretlint/internal/scanner"
+
+// Duplicate is one secret value found in several places. Responders need the
+// whole blast radius, so it's reported once with every location.
+type Duplicate struct {
+	Fingerprint string   `json:"fingerprint"`
+	RuleID      string   `json:"rule_id"`
+	Snippet     string   `json:"snippet"`
+	Repos       []string `json:"repos,omitempty"`
+	Locations   []string `json:"locations"`
+}
+
+// GroupByFingerprint groups findings by secret, in order of first occurrence
+func GroupByFingerprint(findings []scanner.Finding) [][]scanner.Finding {
+	index := make(map[string]int)
+	var groups [][]scanner.Finding
+	for _, finding := range findings {
+		fingerprint := finding.Fingerprint()
+		i, ok := index[fingerprint]
+		if !ok {
+			i = len(groups)
+			index[fingerprint] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], finding)
+	}
+	return groups
+}
+
+// FindDuplicates returns the secrets that appear in more than one file or
+// repository. Repeats within the same file (e.g. across commits) don't count.
+func FindDuplicates(findings []scanner.Finding) []Duplicate {
+	var duplicates []Duplicate
+	for _, group := range GroupByFingerprint(findings) {
+		seenFiles := make(map[string]bool)
+		seenLocations := make(map[string]bool)
+		duplicate := Duplicate{
+			Fingerprint: group[0].Fingerprint(),
+			RuleID:      group[0].RuleID,
+			Snippet:     group[0].MaskValue(group[0].Secret),
+			Locations:   []string{},
+		}
+		for _, finding := range group {
+			seenFiles[finding.Repo+"\x00"+finding.FilePath] = true
+			if location := finding.Location(); !seenLocations[location] {
+				seenLocations[location] = true
+				duplicate.Locations = append(duplicate.Locations, location)
+			}
+			if finding.Repo != "" && !containsString(duplicate.Repos, finding.Repo) {
+				duplicate.Repos = append(duplicate.Repos, finding.Repo)
+			}
+		}
+		if len(seenFiles) > 1 {
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+	return duplicates
+}
+
+func containsString(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package report
+
+import "fmt"
+
+// RepoSummary is one repository's outcome in a fleet scan
+type RepoSummary struct {
+	Repo     string `json:"repo"`
+	Source   string `json:"source"`
+	Findings int    `json:"findings"`
+	Blocking int    `json:"blocking"`
+
+	// Error is set when the repository could not be cloned or scanned
+	Error string `json:"error,omitempty"`
+}
+
+// NewFleet aggregates per-repository reports into one cross-repo report.
+// Findings keep their repository; secrets shared between repositories are
+// listed as duplicates.
+func NewFleet(repos []RepoSummary, findings []Finding) *Report {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	sortFindings(findings)
+	return &Report{
+		Version:     SchemaVersion,
+		Tool:        "secretlint",
+		Scope:       "fleet",
+		GeneratedAt: now(),
+		Findings:    findings,
+		Duplicates:  duplicateFindings(findings),
+		Repos:       repos,
+	}
+}
+
+// duplicateFindings is FindDuplicates for findings that were already
+// serialized, as the plaintext secret is no longer available
+func duplicateFindings(findings []Finding) []Duplicate {
+	index := make(map[string]int)
+	var groups [][]Finding
+	for _, finding := range findings {
+		i, ok := index[finding.Fingerprint]
+		if !ok {
+			i = len(groups)
+			index[finding.Fingerprint] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], finding)
+	}
+
+	var duplicates []Duplicate
+	for _, group := range groups {
+		seenFiles := make(map[string]bool)
+		duplicate := Duplicate{
+			Fingerprint: group[0].Fingerprint,
+			RuleID:      group[0].RuleID,
+			Snippet:     group[0].Snippet,
+			Locations:   []string{},
+		}
+		for _, finding := range group {
+			seenFiles[finding.Repo+"\x00"+finding.File] = true
+			location := finding.File
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", finding.File, finding.Line)
+			}
+			if finding.Repo != "" {
+				location = finding.Repo + ":" + location
+			}
+			if !containsString(duplicate.Locations, location) {
+				duplicate.Locations = append(duplicate.Locations, location)
+			}
+			if finding.Repo != "" && !containsString(duplicate.Repos, finding.Repo) {
+				duplicate.Repos = append(duplicate.Repos, finding.Repo)
+			}
+		}
+		if len(seenFiles) > 1 {
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+	return duplicates
+}
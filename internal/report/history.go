@@ -0,0 +1,127 @@
+package report
+
+import (
+	"sort"
+
+	"secretlint/internal/scanner"
+)
+
+// Lifetime summarizes one secret's history in the repository: where and by
+// whom it was introduced, every commit that added it, and whether it is
+// still present at HEAD
+type Lifetime struct {
+	Fingerprint  string   `json:"fingerprint"`
+	RuleID       string   `json:"rule_id"`
+	RuleName     string   `json:"rule_name"`
+	Snippet      string   `json:"snippet"`
+	IntroducedBy *Commit  `json:"introduced_by"`
+	LastAddedBy  *Commit  `json:"last_added_by"`
+	Commits      []Commit `json:"commits"`
+	Files        []string `json:"files"`
+	AtHead       bool     `json:"at_head"`
+	HeadFiles    []string `json:"head_files,omitempty"`
+	Owners       []string `json:"owners,omitempty"`
+	LastTouched  *Commit  `json:"last_touched_by,omitempty"`
+}
+
+// NewHistory builds a history report, grouping findings into per-secret
+// lifetimes. headLocations maps fingerprints to "file:line" entries at HEAD.
+func NewHistory(findings []scanner.Finding, headLocations map[string][]string) *Report {
+	report := New("history", findings)
+	report.Lifetimes = BuildLifetimes(findings, headLocations)
+	return report
+}
+
+// BuildLifetimes groups findings by fingerprint, oldest introduction first
+func BuildLifetimes(findings []scanner.Finding, headLocations map[string][]string) []Lifetime {
+	index := make(map[string]*Lifetime)
+	var order []string
+	seenCommits := make(map[string]bool)
+	seenFiles := make(map[string]bool)
+
+	for _, finding := range findings {
+		fingerprint := finding.Fingerprint()
+		lifetime, ok := index[fingerprint]
+		if !ok {
+			lifetime = &Lifetime{
+				Fingerprint: fingerprint,
+				RuleID:      finding.RuleID,
+				RuleName:    finding.RuleName,
+				Snippet:     finding.MaskValue(finding.Secret),
+				HeadFiles:   displayPaths(headLocations[fingerprint]),
+				LastTouched: newCommit(finding.LastTouchedBy),
+			}
+			lifetime.AtHead = len(lifetime.HeadFiles) > 0
+			index[fingerprint] = lifetime
+			order = append(order, fingerprint)
+		}
+
+		for _, owner := range finding.Owners {
+			if !containsString(lifetime.Owners, owner) {
+				lifetime.Owners = append(lifetime.Owners, owner)
+			}
+		}
+
+		file := finding.Path()
+		if finding.Repo != "" {
+			file = finding.Repo + ":" + file
+		}
+		if !seenFiles[fingerprint+"\x00"+file] {
+			seenFiles[fingerprint+"\x00"+file] = true
+			lifetime.Files = append(lifetime.Files, file)
+		}
+
+		if commit := finding.Commit; commit != nil && !seenCommits[fingerprint+"\x00"+commit.SHA] {
+			seenCommits[fingerprint+"\x00"+commit.SHA] = true
+			lifetime.Commits = append(lifetime.Commits, Commit{
+				SHA:     commit.SHA,
+				Author:  commit.Author,
+				Email:   commit.AuthorEmail,
+				Date:    commit.Date,
+				Subject: commit.Subject,
+				Shallow: commit.Shallow,
+			})
+		}
+	}
+
+	lifetimes := make([]Lifetime, 0, len(order))
+	for _, fingerprint := range order {
+		lifetime := index[fingerprint]
+		// git log lists newest first; reverse so commits with equal dates keep history order
+		for i, j := 0, len(lifetime.Commits)-1; i < j; i, j = i+1, j-1 {
+			lifetime.Commits[i], lifetime.Commits[j] = lifetime.Commits[j], lifetime.Commits[i]
+		}
+		sort.SliceStable(lifetime.Commits, func(i, j int) bool {
+			return lifetime.Commits[i].Date.Before(lifetime.Commits[j].Date)
+		})
+		sort.Strings(lifetime.Files)
+		if len(lifetime.Commits) > 0 {
+			first := lifetime.Commits[0]
+			last := lifetime.Commits[len(lifetime.Commits)-1]
+			lifetime.IntroducedBy = &first
+			lifetime.LastAddedBy = &last
+		}
+		lifetimes = append(lifetimes, *lifetime)
+	}
+
+	sort.SliceStable(lifetimes, func(i, j int) bool {
+		if lifetimes[i].IntroducedBy == nil || lifetimes[j].IntroducedBy == nil {
+			return lifetimes[j].IntroducedBy == nil && lifetimes[i].IntroducedBy != nil
+		}
+		return lifetimes[i].IntroducedBy.Date.Before(lifetimes[j].IntroducedBy.Date)
+	})
+
+	return lifetimes
+}
+
+// displayPaths returns "file:line" locations as output shows them
+func displayPaths(locations []string) []string {
+	if len(locations) == 0 {
+		return locations
+	}
+	paths := make([]string, len(locations))
+	for i, location := range locations {
+		paths[i] = scanner.DisplayPath(location)
+	}
+	return paths
+}
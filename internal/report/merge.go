@@ -0,0 +1,241 @@
+package report
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"secretlint/internal/scanner"
+)
+
+// Input is the findings of one scanner's JSON output, normalized for Merge
+type Input struct {
+	Tool     string
+	Findings []Finding
+
+	// secrets holds the plaintext secret of each finding when the tool
+	// reports it; secretlint reports never contain it
+	secrets []string
+}
+
+// gitleaksFinding is an entry of 'gitleaks detect --report-format json'
+type gitleaksFinding struct {
+	RuleID      string
+	Description string
+	File        string
+	StartLine   int
+	StartColumn int
+	Match       string
+	Secret      string
+	Commit      string
+	Author      string
+	Email       string
+	Date        string
+	Message     string
+}
+
+// trufflehogResult is a line of 'trufflehog --json'
+type trufflehogResult struct {
+	DetectorName   string
+	Verified       bool
+	Raw            string
+	SourceMetadata struct {
+		Data map[string]struct {
+			File       string `json:"file"`
+			Line       int    `json:"line"`
+			Commit     string `json:"commit"`
+			Email      string `json:"email"`
+			Repository string `json:"repository"`
+			Timestamp  string `json:"timestamp"`
+		}
+	}
+}
+
+// LoadInput reads a secretlint JSON report, a gitleaks JSON report or
+// trufflehog JSON lines, telling them apart by their layout
+func LoadInput(path string) (*Input, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	trimmed := bytes.TrimSpace(data)
+
+	switch {
+	case len(trimmed) == 0:
+		return nil, fmt.Errorf("%s is empty", path)
+	case trimmed[0] == '[':
+		var leaks []gitleaksFinding
+		if err := json.Unmarshal(trimmed, &leaks); err != nil {
+			return nil, fmt.Errorf("failed to parse gitleaks report %s: %w", path, err)
+		}
+		return fromGitleaks(leaks), nil
+	}
+
+	var probe struct {
+		Tool string `json:"tool"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(trimmed)).Decode(&probe); err == nil && probe.Tool == "secretlint" {
+		report, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return &Input{Tool: "secretlint", Findings: report.Findings, secrets: make([]string, len(report.Findings))}, nil
+	}
+
+	var results []trufflehogResult
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		var result trufflehogResult
+		if err := decoder.Decode(&result); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse trufflehog output %s: %w", path, err)
+		}
+		if result.DetectorName == "" {
+			return nil, fmt.Errorf("%s is not a secretlint, gitleaks or trufflehog JSON report", path)
+		}
+		results = append(results, result)
+	}
+	return fromTrufflehog(results), nil
+}
+
+func fromGitleaks(leaks []gitleaksFinding) *Input {
+	input := &Input{Tool: "gitleaks"}
+	for _, leak := range leaks {
+		secret := leak.Secret
+		if secret == "" {
+			secret = leak.Match
+		}
+		finding := externalFinding("gitleaks", leak.RuleID, leak.Description, secret)
+		finding.File = leak.File
+		finding.Line = leak.StartLine
+		finding.Column = leak.StartColumn
+		if leak.Commit != "" {
+			date, _ := time.Parse(time.RFC3339, leak.Date)
+			finding.Commit = &Commit{SHA: leak.Commit, Author: leak.Author, Email: leak.Email, Date: date, Subject: firstLine(leak.Message)}
+		}
+		input.Findings = append(input.Findings, finding)
+		input.secrets = append(input.secrets, secret)
+	}
+	return input
+}
+
+func fromTrufflehog(results []trufflehogResult) *Input {
+	input := &Input{Tool: "trufflehog"}
+	for _, result := range results {
+		finding := externalFinding("trufflehog", result.DetectorName, result.DetectorName+" credential", result.Raw)
+		if result.Verified {
+			finding.Description += ", verified live by trufflehog"
+		}
+		// Data holds one source, keyed by its type (Filesystem, Git, Github...)
+		for _, source := range result.SourceMetadata.Data {
+			finding.Repo = source.Repository
+			finding.File = source.File
+			finding.Line = source.Line
+			if source.Commit != "" {
+				date, _ := time.Parse("2006-01-02 15:04:05 -0700", source.Timestamp)
+				finding.Commit = &Commit{SHA: source.Commit, Email: source.Email, Date: date}
+			}
+		}
+		input.Findings = append(input.Findings, finding)
+		input.secrets = append(input.secrets, result.Raw)
+	}
+	return input
+}
+
+// externalFinding converts another scanner's rule into a finding with a
+// secretlint rule ID and fingerprint
+func externalFinding(tool, rule, description, secret string) Finding {
+	ruleID := strings.ToUpper(strings.NewReplacer("-", "_", " ", "_", ".", "_").Replace(rule))
+	sum := sha256.Sum256([]byte(ruleID + ":" + secret))
+	return Finding{
+		Fingerprint: hex.EncodeToString(sum[:])[:16],
+		RuleID:      ruleID,
+		RuleName:    rule,
+		Snippet:     scanner.MaskValue(secret),
+		Severity:    scanner.SeverityError,
+		Description: description,
+		Advice:      fmt.Sprintf("Reported by %s; rotate the secret and remove it from the code", tool),
+	}
+}
+
+func firstLine(message string) string {
+	return strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]
+}
+
+// mergeEntry is a merged finding with what's needed to match it
+type mergeEntry struct {
+	finding Finding
+	secret  string
+}
+
+// Merge combines the findings of several scanners into one report. A
+// finding is a duplicate of another at the same location when both carry
+// the same secret, or, as secretlint reports hold no plaintext, when one
+// of them comes from secretlint and the other from another tool. Sources
+// lists every tool that reported a finding.
+func Merge(inputs []*Input) (*Report, int) {
+	merged := &Report{
+		Version:     SchemaVersion,
+		Tool:        "secretlint",
+		Scope:       "merge",
+		GeneratedAt: now(),
+		Findings:    []Finding{},
+	}
+
+	var entries []*mergeEntry
+	byLocation := make(map[string][]*mergeEntry)
+	removed := 0
+	for _, input := range inputs {
+		for i, finding := range input.Findings {
+			secret := input.secrets[i]
+			location := finding.Repo + "\x00" + finding.File + "\x00" + fmt.Sprint(finding.Line)
+			if existing := matchEntry(byLocation[location], input.Tool, finding, secret); existing != nil {
+				if !containsString(existing.finding.Sources, input.Tool) {
+					existing.finding.Sources = append(existing.finding.Sources, input.Tool)
+				}
+				if existing.secret == "" {
+					existing.secret = secret
+				}
+				removed++
+				continue
+			}
+			finding.Sources = []string{input.Tool}
+			entry := &mergeEntry{finding: finding, secret: secret}
+			entries = append(entries, entry)
+			byLocation[location] = append(byLocation[location], entry)
+		}
+	}
+
+	for _, entry := range entries {
+		merged.Findings = append(merged.Findings, entry.finding)
+	}
+	sortFindings(merged.Findings)
+	return merged, removed
+}
+
+// matchEntry returns the entry at a location the finding duplicates
+func matchEntry(candidates []*mergeEntry, tool string, finding Finding, secret string) *mergeEntry {
+	for _, entry := range candidates {
+		if entry.finding.Fingerprint == finding.Fingerprint {
+			return entry
+		}
+		if secret != "" && entry.secret != "" {
+			if secret == entry.secret {
+				return entry
+			}
+			continue
+		}
+		// Without a secret to compare, pair up one finding per tool
+		if !containsString(entry.finding.Sources, tool) {
+			return entry
+		}
+	}
+	return nil
+}
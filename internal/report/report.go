@@ -0,0 +1,277 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"secretlint/internal/fix"
+	"secretlint/internal/scanner"
+)
+
+// SchemaVersion is bumped whenever the JSON layout changes incompatibly
+const SchemaVersion = 1
+
+// Report is the machine-readable result of a scan. It never contains
+// plaintext secrets: findings carry a masked snippet and a fingerprint.
+type Report struct {
+	Version     int       `json:"version"`
+	Tool        string    `json:"tool"`
+	Scope       string    `json:"scope"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Findings    []Finding `json:"findings"`
+
+	// Duplicates lists secrets found in more than one file or repository
+	Duplicates []Duplicate `json:"duplicates,omitempty"`
+
+	// Lifetimes groups history findings by secret; only set for history scans
+	Lifetimes []Lifetime `json:"lifetimes,omitempty"`
+
+	// Repos summarizes each repository; only set for fleet scans
+	Repos []RepoSummary `json:"repos,omitempty"`
+
+	// Repository is where the scan ran, so reports collected from many
+	// repositories can be attributed; unset outside a git repository
+	Repository *Repository `json:"repository,omitempty"`
+}
+
+// Repository identifies the repository, branch and commit a scan ran on
+type Repository struct {
+	// Remote is the origin URL without credentials
+	Remote string `json:"remote,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Head   string `json:"head,omitempty"`
+
+	// CommitInProgress describes the commit a staged scan checked
+	CommitInProgress *CommitInProgress `json:"commit_in_progress,omitempty"`
+}
+
+// CommitInProgress is the commit being made when a staged scan ran; its
+// parent is the repository's head
+type CommitInProgress struct {
+	Author string `json:"author,omitempty"`
+	Email  string `json:"email,omitempty"`
+
+	// Operation is merge, rebase, cherry-pick or revert when the commit
+	// is part of one
+	Operation string `json:"operation,omitempty"`
+}
+
+// Finding is the serialized form of a scanner finding
+type Finding struct {
+	Fingerprint string       `json:"fingerprint"`
+	RuleID      string       `json:"rule_id"`
+	RuleName    string       `json:"rule_name"`
+	Repo        string       `json:"repo,omitempty"`
+	File        string       `json:"file"`
+	Line        int          `json:"line"`
+	Column      int          `json:"column"`
+	KeyPath     string       `json:"key_path,omitempty"`
+	Cell        string       `json:"cell,omitempty"`
+	Snippet     string       `json:"snippet"`
+	Severity    string       `json:"severity,omitempty"`
+	Violation   bool         `json:"policy_violation,omitempty"`
+	Description string       `json:"description"`
+	Advice      string       `json:"advice"`
+	Remediation *Remediation `json:"remediation,omitempty"`
+	Fix         *Fix         `json:"fix,omitempty"`
+	Commit      *Commit      `json:"commit,omitempty"`
+	Owners      []string     `json:"owners,omitempty"`
+	LastTouched *Commit      `json:"last_touched_by,omitempty"`
+
+	// Sources lists the scanners that reported the finding; only set by
+	// 'secretlint report merge'
+	Sources []string `json:"sources,omitempty"`
+
+	// Metadata is the File metadata of findings from a library FileSource
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Resolution is the triage outcome, set by 'secretlint report annotate'
+	// or edited in by a web UI or spreadsheet round-trip
+	Resolution *Resolution `json:"resolution,omitempty"`
+}
+
+// Resolution statuses. Every status but open accepts the finding into the
+// baseline when the report is annotated.
+const (
+	ResolutionOpen          = "open"
+	ResolutionRotated       = "rotated"
+	ResolutionFalsePositive = "false_positive"
+	ResolutionAccepted      = "accepted"
+)
+
+// Resolution records how a finding was triaged
+type Resolution struct {
+	Status string     `json:"status"`
+	Note   string     `json:"note,omitempty"`
+	By     string     `json:"by,omitempty"`
+	At     *time.Time `json:"at,omitempty"`
+}
+
+// ValidResolution reports whether status is one of the resolution statuses
+func ValidResolution(status string) bool {
+	switch status {
+	case ResolutionOpen, ResolutionRotated, ResolutionFalsePositive, ResolutionAccepted:
+		return true
+	}
+	return false
+}
+
+// Remediation is the serialized form of a rule's rotation guidance
+type Remediation struct {
+	RevokeURL     string   `json:"revoke_url,omitempty"`
+	RotateCommand string   `json:"rotate_command,omitempty"`
+	DocLinks      []string `json:"doc_links,omitempty"`
+}
+
+// Fix is the "extract to environment variable" quick fix of a finding in
+// the working tree, for editor plugins: append EnvAppend followed by the text
+// at Value to EnvFile, then replace Replace with Replacement. The report
+// holds no secrets, so the value is read from the file itself.
+type Fix struct {
+	EnvVar      string `json:"env_var"`
+	Replace     Range  `json:"replace"`
+	Replacement string `json:"replacement"`
+	Value       Range  `json:"value"`
+	EnvFile     string `json:"env_file"`
+	EnvAppend   string `json:"env_append"`
+}
+
+// Range is a span of one line. Columns are 1-based byte offsets, like a
+// finding's column, and EndColumn is the first column after the span.
+type Range struct {
+	Line      int `json:"line"`
+	Column    int `json:"column"`
+	EndColumn int `json:"end_column"`
+}
+
+// Commit identifies the commit that introduced a finding in history scans
+type Commit struct {
+	SHA     string    `json:"sha"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+
+	// Shallow marks the boundary of a shallow clone: what the commit seems
+	// to add may have been added by commits that weren't fetched
+	Shallow bool `json:"shallow_boundary,omitempty"`
+}
+
+// now is the clock reports are stamped with
+var now = func() time.Time { return time.Now().UTC() }
+
+// Pin stamps every report with t instead of the current time, for output
+// that is identical between runs (scan --deterministic)
+func Pin(t time.Time) {
+	now = func() time.Time { return t.UTC() }
+}
+
+// New builds a report from scanner findings, in the order of
+// scanner.SortFindings
+func New(scope string, findings []scanner.Finding) *Report {
+	report := &Report{
+		Version:     SchemaVersion,
+		Tool:        "secretlint",
+		Scope:       scope,
+		GeneratedAt: now(),
+		Findings:    make([]Finding, 0, len(findings)),
+	}
+	findings = append([]scanner.Finding(nil), findings...)
+	scanner.SortFindings(findings)
+
+	for _, finding := range findings {
+		entry := Finding{
+			Fingerprint: finding.Fingerprint(),
+			RuleID:      finding.RuleID,
+			RuleName:    finding.RuleName,
+			Repo:        finding.Repo,
+			File:        finding.Path(),
+			Line:        finding.LineNum,
+			Column:      finding.StartPos + 1,
+			KeyPath:     finding.KeyPath,
+			Cell:        finding.Cell,
+			Snippet:     finding.MaskSecret(),
+			Severity:    finding.Severity,
+			Violation:   finding.PolicyViolation,
+			Description: finding.Description,
+			Advice:      finding.Advice,
+			Metadata:    finding.Metadata,
+		}
+
+		remediation := finding.Remediation
+		if remediation.RevokeURL != "" || remediation.RotateCommand != "" || len(remediation.DocLinks) > 0 {
+			entry.Remediation = &Remediation{
+				RevokeURL:     remediation.RevokeURL,
+				RotateCommand: remediation.RotateCommand,
+				DocLinks:      remediation.DocLinks,
+			}
+		}
+
+		if suggestion := fix.Suggest(finding); suggestion != nil {
+			entry.Fix = &Fix{
+				EnvVar:      suggestion.EnvVar,
+				Replace:     Range{Line: finding.LineNum, Column: suggestion.Start + 1, EndColumn: suggestion.End + 1},
+				Replacement: suggestion.Replacement,
+				Value:       Range{Line: finding.LineNum, Column: suggestion.ValueStart + 1, EndColumn: suggestion.ValueEnd + 1},
+				EnvFile:     fix.EnvFile,
+				EnvAppend:   suggestion.EnvVar + "=",
+			}
+		}
+
+		entry.Commit = newCommit(finding.Commit)
+		entry.Owners = finding.Owners
+		entry.LastTouched = newCommit(finding.LastTouchedBy)
+
+		report.Findings = append(report.Findings, entry)
+	}
+	report.Duplicates = FindDuplicates(findings)
+
+	return report
+}
+
+// newCommit converts scanner commit info, keeping nil as nil
+func newCommit(commit *scanner.CommitInfo) *Commit {
+	if commit == nil {
+		return nil
+	}
+	return &Commit{
+		SHA:     commit.SHA,
+		Author:  commit.Author,
+		Email:   commit.AuthorEmail,
+		Date:    commit.Date,
+		Subject: commit.Subject,
+		Shallow: commit.Shallow,
+	}
+}
+
+// Write encodes the report as indented JSON
+func (r *Report) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// Load reads a JSON report previously written by 'secretlint scan --format json'
+func Load(reportPath string) (*Report, error) {
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	report := &Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", reportPath, err)
+	}
+	if report.Version > SchemaVersion {
+		return nil, fmt.Errorf("report %s uses schema version %d, newer than this secretlint supports (%d)", reportPath, report.Version, SchemaVersion)
+	}
+
+	return report, nil
+}
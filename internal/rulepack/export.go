@@ -0,0 +1,261 @@
+package rulepack
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExportFormats lists the scanners rules can be exported to
+var ExportFormats = []string{"gitleaks", "trufflehog", "github"}
+
+// ExportGitleaks renders rules as a gitleaks config extending gitleaks'
+// default rules. A "secret" group becomes secretGroup.
+func ExportGitleaks(rules []Rule) string {
+	var b strings.Builder
+	b.WriteString("# Generated by 'secretlint rules export --format gitleaks'; edit the secretlint rules instead\n")
+	b.WriteString("title = \"secretlint rules\"\n\n[extend]\nuseDefault = true\n")
+	for _, rule := range rules {
+		b.WriteString("\n[[rules]]\n")
+		fmt.Fprintf(&b, "id = %s\n", tomlString(exportID(rule.ID)))
+		if description := ruleDescription(rule); description != "" {
+			fmt.Fprintf(&b, "description = %s\n", tomlString(description))
+		}
+		fmt.Fprintf(&b, "regex = %s\n", tomlString(rule.Pattern))
+		if compiled, err := regexp.Compile(rule.Pattern); err == nil {
+			if group := compiled.SubexpIndex("secret"); group > 0 {
+				fmt.Fprintf(&b, "secretGroup = %d\n", group)
+			}
+		}
+		if keywords := Keywords(rule.Pattern); len(keywords) > 0 {
+			fmt.Fprintf(&b, "keywords = [%s]\n", tomlString(keywords[0]))
+		}
+	}
+	return b.String()
+}
+
+// trufflehogConfig is trufflehog's custom detector configuration
+type trufflehogConfig struct {
+	Detectors []trufflehogDetector `yaml:"detectors"`
+}
+
+type trufflehogDetector struct {
+	Name     string            `yaml:"name"`
+	Keywords []string          `yaml:"keywords"`
+	Regex    map[string]string `yaml:"regex"`
+}
+
+// ExportTrufflehog renders rules as trufflehog custom detectors. trufflehog
+// requires a keyword to prefilter on, so rules without a literal part of
+// at least three characters are returned as skipped.
+func ExportTrufflehog(rules []Rule) (string, []string, error) {
+	cfg := trufflehogConfig{}
+	var skipped []string
+	for _, rule := range rules {
+		keywords := Keywords(rule.Pattern)
+		if len(keywords) == 0 {
+			skipped = append(skipped, rule.ID)
+			continue
+		}
+		cfg.Detectors = append(cfg.Detectors, trufflehogDetector{
+			Name:     exportID(rule.ID),
+			Keywords: keywords[:1],
+			Regex:    map[string]string{"secret": rule.Pattern},
+		})
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode trufflehog detectors: %w", err)
+	}
+	return "# Generated by 'secretlint rules export --format trufflehog'; edit the secretlint rules instead\n" + string(data), skipped, nil
+}
+
+// githubPattern is a GitHub secret scanning custom pattern
+type githubPattern struct {
+	Name           string `json:"name"`
+	SecretFormat   string `json:"secret_format"`
+	BeforeSecret   string `json:"before_secret"`
+	AfterSecret    string `json:"after_secret"`
+	PushProtection bool   `json:"push_protection"`
+}
+
+// GitHub's defaults for the boundaries around a secret
+const (
+	githubBeforeSecret = `\A|[^0-9A-Za-z]`
+	githubAfterSecret  = `\z|[^0-9A-Za-z]`
+)
+
+// ExportGitHub renders rules as GitHub secret scanning custom patterns with
+// push protection enabled. GitHub matches the secret separately from its
+// surroundings, so a pattern whose "secret" group is part of its top-level
+// sequence is split into before_secret, secret_format and after_secret;
+// other patterns are matched whole. Blocking rules only: GitHub has no
+// notion of a warning, so warning rules are returned as skipped.
+func ExportGitHub(rules []Rule) (string, []string, error) {
+	patterns := []githubPattern{}
+	var skipped []string
+	for _, rule := range rules {
+		if rule.Severity == "warning" {
+			skipped = append(skipped, rule.ID)
+			continue
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			skipped = append(skipped, rule.ID)
+			continue
+		}
+		pattern := githubPattern{
+			Name:           rule.ID,
+			SecretFormat:   unnameGroups(rule.Pattern),
+			BeforeSecret:   githubBeforeSecret,
+			AfterSecret:    githubAfterSecret,
+			PushProtection: true,
+		}
+		if before, secret, after, ok := splitSecret(rule.Pattern); ok {
+			pattern.SecretFormat = secret
+			if before != "" {
+				pattern.BeforeSecret = before
+			}
+			if after != "" {
+				pattern.AfterSecret = after
+			}
+		}
+		patterns = append(patterns, pattern)
+	}
+	data, err := json.MarshalIndent(patterns, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode GitHub custom patterns: %w", err)
+	}
+	return string(data) + "\n", skipped, nil
+}
+
+// leadingFlags matches flags such as (?i) that apply to a whole pattern
+var leadingFlags = regexp.MustCompile(`^\(\?[a-zA-Z]+\)`)
+
+// groupName matches the name of a named group, (?P<name> or (?<name>
+var groupName = regexp.MustCompile(`\(\?P?<[A-Za-z_][A-Za-z0-9_]*>`)
+
+// splitSecret splits a pattern around a "secret" group outside any other
+// group or alternation. Leading flags are repeated on each part, as GitHub
+// compiles them separately.
+func splitSecret(pattern string) (before, secret, after string, ok bool) {
+	flags := leadingFlags.FindString(pattern)
+	rest := pattern[len(flags):]
+	const open = "(?P<secret>"
+
+	depth, start, end := 0, -1, -1
+	inClass := false
+	for i := 0; i < len(rest); i++ {
+		switch c := rest[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+			// A ']' right after '[' or '[^' is literal
+			if strings.HasPrefix(rest[i+1:], "]") {
+				i++
+			} else if strings.HasPrefix(rest[i+1:], "^]") {
+				i += 2
+			}
+		case c == '|' && depth == 0:
+			return "", "", "", false
+		case c == '(':
+			if depth == 0 && start < 0 && strings.HasPrefix(rest[i:], open) {
+				start = i
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 && start >= 0 && end < 0 {
+				end = i
+			}
+		}
+	}
+	if start < 0 || end < 0 {
+		return "", "", "", false
+	}
+
+	part := func(text string) string {
+		if text == "" {
+			return ""
+		}
+		return flags + unnameGroups(text)
+	}
+	return part(rest[:start]), part(rest[start+len(open) : end]), part(rest[end+1:]), true
+}
+
+// unnameGroups turns named groups into plain ones, as GitHub's regex engine
+// doesn't accept group names
+func unnameGroups(pattern string) string {
+	return groupName.ReplaceAllString(pattern, "(")
+}
+
+// Keywords returns the literal strings every match of pattern contains,
+// longest first and lowercased, for scanners that prefilter on keywords
+func Keywords(pattern string) []string {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	var keywords []string
+	var collect func(re *syntax.Regexp)
+	collect = func(re *syntax.Regexp) {
+		switch re.Op {
+		case syntax.OpLiteral:
+			if literal := strings.ToLower(string(re.Rune)); len(literal) >= 3 {
+				keywords = append(keywords, literal)
+			}
+		case syntax.OpConcat, syntax.OpCapture:
+			// Only parts every match goes through; alternations and
+			// optional parts don't guarantee a literal
+			for _, sub := range re.Sub {
+				collect(sub)
+			}
+		case syntax.OpPlus:
+			collect(re.Sub[0])
+		case syntax.OpRepeat:
+			if re.Min > 0 {
+				collect(re.Sub[0])
+			}
+		}
+	}
+	collect(re.Simplify())
+
+	for i := 1; i < len(keywords); i++ {
+		for j := i; j > 0 && len(keywords[j]) > len(keywords[j-1]); j-- {
+			keywords[j], keywords[j-1] = keywords[j-1], keywords[j]
+		}
+	}
+	return keywords
+}
+
+// exportID turns a rule ID such as ACME_TOKEN into the kebab-case IDs the
+// other scanners use, acme-token
+func exportID(id string) string {
+	return strings.ReplaceAll(strings.ToLower(id), "_", "-")
+}
+
+func ruleDescription(rule Rule) string {
+	if rule.Name != "" && rule.Name != rule.ID {
+		return rule.Name
+	}
+	return rule.Description
+}
+
+// tomlString quotes a value as a TOML literal string when it allows that,
+// which keeps regexes readable, and as a basic string otherwise
+func tomlString(value string) string {
+	if !strings.ContainsAny(value, "'\n") {
+		return "'" + value + "'"
+	}
+	if !strings.Contains(value, "'''") && !strings.HasSuffix(value, "'") && !strings.Contains(value, "\n") {
+		return "'''" + value + "'''"
+	}
+	return strconv.Quote(value)
+}
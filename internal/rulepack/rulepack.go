@@ -0,0 +1,252 @@
+package rulepack
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"secretlint/internal/regexcache"
+	"secretlint/internal/signature"
+)
+
+// DirEnv overrides the directory installed packs are stored in, e.g. a path
+// shared by every user of a build machine
+const DirEnv = "SECRETLINT_PACKS_DIR"
+
+// FileName is the bundle file inside an OCI artifact
+const FileName = "pack.yml"
+
+// namePattern restricts pack names and versions to safe path components
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Pack is a versioned rule-pack bundle distributed by a security team
+type Pack struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Rule is one detection rule of a pack
+type Rule struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Pattern     string `yaml:"pattern"`
+	Description string `yaml:"description"`
+	Advice      string `yaml:"advice"`
+
+	// Severity is "error" (default, blocks) or "warning" (reported only)
+	Severity string `yaml:"severity,omitempty"`
+
+	// Tags and References (CWE IDs such as CWE-798, or documentation URLs)
+	// are listed by 'secretlint rules list'
+	Tags       []string `yaml:"tags,omitempty"`
+	References []string `yaml:"references,omitempty"`
+
+	// NotPrecededBy and NotFollowedBy drop matches with one of these
+	// strings right before or after them, standing in for the lookarounds
+	// Go's regexp doesn't support
+	NotPrecededBy []string `yaml:"not_preceded_by,omitempty"`
+	NotFollowedBy []string `yaml:"not_followed_by,omitempty"`
+
+	// Engine "regexp2" selects the backtracking engine, for patterns with
+	// lookarounds or backreferences; the default is Go's regexp
+	Engine string `yaml:"engine,omitempty"`
+}
+
+// Ref names the pack as registered under packs: in the config
+func (p *Pack) Ref() string {
+	return p.Name + "@" + p.Version
+}
+
+// Parse reads a pack bundle, rejecting packs that would not load cleanly
+func Parse(data []byte) (*Pack, error) {
+	pack := &Pack{}
+	if err := yaml.Unmarshal(data, pack); err != nil {
+		return nil, fmt.Errorf("invalid rule pack: %w", err)
+	}
+	if !namePattern.MatchString(pack.Name) || !namePattern.MatchString(pack.Version) {
+		return nil, fmt.Errorf("invalid rule pack: name and version are required (letters, digits, '.', '_' and '-')")
+	}
+	if len(pack.Rules) == 0 {
+		return nil, fmt.Errorf("invalid rule pack %s: no rules", pack.Ref())
+	}
+	seen := make(map[string]bool)
+	for i, rule := range pack.Rules {
+		if rule.ID == "" || seen[rule.ID] {
+			return nil, fmt.Errorf("invalid rule pack %s: rule IDs must be set and unique (%q)", pack.Ref(), rule.ID)
+		}
+		seen[rule.ID] = true
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("invalid rule pack %s: rule %s (rules[%d]) has no pattern", pack.Ref(), rule.ID, i)
+		}
+		if err := regexcache.ValidateEngine(rule.Engine, rule.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid rule pack %s: rule %s (rules[%d]) has an invalid pattern: %w", pack.Ref(), rule.ID, i, err)
+		}
+		switch rule.Severity {
+		case "", "error", "warning":
+		default:
+			return nil, fmt.Errorf("invalid rule pack %s: rule %s has severity %q (use error or warning)", pack.Ref(), rule.ID, rule.Severity)
+		}
+	}
+	return pack, nil
+}
+
+// Dir is where installed packs live: $SECRETLINT_PACKS_DIR, or the user's
+// config directory
+func Dir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no directory for rule packs (set %s): %w", DirEnv, err)
+	}
+	return filepath.Join(dir, "secretlint", "packs"), nil
+}
+
+// Load reads an installed pack by its name@version reference
+func Load(ref string) (*Pack, error) {
+	at := strings.LastIndex(ref, "@")
+	if at <= 0 {
+		return nil, fmt.Errorf("rule pack %q is not pinned to a version (use name@version)", ref)
+	}
+	name, version := ref[:at], ref[at+1:]
+	if !namePattern.MatchString(name) || !namePattern.MatchString(version) {
+		return nil, fmt.Errorf("invalid rule pack reference %q", ref)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name, version+".yml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("rule pack %s is not installed (run 'secretlint rules install')", ref)
+		}
+		return nil, fmt.Errorf("failed to read rule pack %s: %w", ref, err)
+	}
+	pack, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if pack.Ref() != ref {
+		return nil, fmt.Errorf("rule pack file for %s contains %s", ref, pack.Ref())
+	}
+	return pack, nil
+}
+
+// Fetch downloads a bundle and its minisign signature and verifies it. The
+// source is a local path, an http(s) URL (signature at <url>.minisig) or an
+// oci:// artifact holding pack.yml and pack.yml.minisig, pulled with oras.
+func Fetch(source string, key *signature.PublicKey) (*Pack, []byte, error) {
+	var data, minisig []byte
+	var err error
+	if strings.HasPrefix(source, "oci://") {
+		data, minisig, err = pullOCI(strings.TrimPrefix(source, "oci://"))
+	} else {
+		if data, err = read(source); err == nil {
+			minisig, err = read(source + ".minisig")
+			if err != nil {
+				err = fmt.Errorf("refusing unsigned rule pack %s: %w", source, err)
+			}
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := key.Verify(data, minisig); err != nil {
+		return nil, nil, fmt.Errorf("refusing rule pack %s: %w", source, err)
+	}
+	pack, err := Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pack, data, nil
+}
+
+// Install stores a verified bundle under Dir, returning its path
+func Install(pack *Pack, data []byte) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, pack.Name, pack.Version+".yml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	// Write then rename so a concurrent scan never reads a partial pack
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// IsRemote reports whether fetching source needs network access
+func IsRemote(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "oci://")
+}
+
+// read loads a file from disk or over http(s)
+func read(source string) ([]byte, error) {
+	if !IsRemote(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", source, err)
+		}
+		return data, nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", source, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+	}
+	return data, nil
+}
+
+// pullOCI pulls an artifact with the oras CLI and returns its bundle and signature
+func pullOCI(ref string) ([]byte, []byte, error) {
+	if _, err := exec.LookPath("oras"); err != nil {
+		return nil, nil, fmt.Errorf("installing oci://%s needs the oras CLI (https://oras.land)", ref)
+	}
+	dir, err := ioutil.TempDir("", "secretlint-pack-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if output, err := exec.Command("oras", "pull", "--output", dir, ref).CombinedOutput(); err != nil {
+		return nil, nil, fmt.Errorf("failed to pull %s: %s", ref, strings.TrimSpace(string(output)))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		return nil, nil, fmt.Errorf("artifact %s has no %s", ref, FileName)
+	}
+	minisig, err := os.ReadFile(filepath.Join(dir, FileName+".minisig"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("refusing unsigned rule pack %s: artifact has no %s.minisig", ref, FileName)
+	}
+	return data, minisig, nil
+}
@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"secretlint/internal/config"
+)
+
+// allowlistEntry is a compiled allowlist: entry from the config
+type allowlistEntry struct {
+	rules     map[string]bool
+	regexes   []*regexp.Regexp
+	target    string
+	stopwords []string
+	paths     []*regexp.Regexp
+}
+
+// compileAllowlist compiles the config's allowlist; LoadWith has already
+// rejected invalid regexes, so any that fail here are skipped
+func compileAllowlist(entries []config.AllowlistEntry) []allowlistEntry {
+	var compiled []allowlistEntry
+	for _, entry := range entries {
+		c := allowlistEntry{target: entry.Target}
+		if len(entry.Rules) > 0 {
+			c.rules = make(map[string]bool)
+			for _, ruleID := range entry.Rules {
+				c.rules[ruleID] = true
+			}
+		}
+		for _, pattern := range entry.Regexes {
+			if re, err := regexp.Compile(pattern); err == nil {
+				c.regexes = append(c.regexes, re)
+			}
+		}
+		for _, pattern := range entry.Paths {
+			if re, err := regexp.Compile(pattern); err == nil {
+				c.paths = append(c.paths, re)
+			}
+		}
+		for _, word := range entry.Stopwords {
+			c.stopwords = append(c.stopwords, strings.ToLower(word))
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled
+}
+
+// allowed reports whether an allowlist entry drops the finding. Mandatory
+// rules can't be allowlisted, like they can't be suppressed inline.
+func (s *SecretScanner) allowed(finding Finding) bool {
+	if s.policy.IsMandatory(finding.RuleID) {
+		return false
+	}
+	for _, entry := range s.allowlist {
+		if entry.rules != nil && !entry.rules[finding.RuleID] {
+			continue
+		}
+		if entry.matches(finding) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e allowlistEntry) matches(finding Finding) bool {
+	target := finding.Secret
+	switch e.target {
+	case "match":
+		target = finding.Match
+	case "line":
+		target = finding.Content
+	}
+	for _, re := range e.regexes {
+		if re.MatchString(target) {
+			return true
+		}
+	}
+	secret := strings.ToLower(finding.Secret)
+	for _, word := range e.stopwords {
+		if strings.Contains(secret, word) {
+			return true
+		}
+	}
+	path := filepath.ToSlash(finding.FilePath)
+	for _, re := range e.paths {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultBaselineFile is where accepted findings are recorded
+const DefaultBaselineFile = ".secretlint-baseline.json"
+
+// BaselineEntry records a finding that was reviewed and accepted
+type BaselineEntry struct {
+	Fingerprint string    `json:"fingerprint"`
+	RuleID      string    `json:"rule_id"`
+	FilePath    string    `json:"file"`
+	LineNum     int       `json:"line"`
+	Reason      string    `json:"reason"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// Baseline holds accepted findings keyed by fingerprint. Contains is safe
+// for concurrent use; Add is not.
+type Baseline struct {
+	Entries []BaselineEntry `json:"entries"`
+	index   map[string]bool
+}
+
+// NewBaseline creates an empty baseline
+func NewBaseline() *Baseline {
+	return &Baseline{index: make(map[string]bool)}
+}
+
+// LoadBaseline reads a baseline file, returning an empty baseline if it doesn't exist
+func LoadBaseline(baselinePath string) (*Baseline, error) {
+	baseline := NewBaseline()
+
+	data, err := os.ReadFile(baselinePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return baseline, nil
+		}
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", baselinePath, err)
+	}
+	for _, entry := range baseline.Entries {
+		baseline.index[entry.Fingerprint] = true
+	}
+
+	return baseline, nil
+}
+
+// Contains checks whether a finding has been accepted into the baseline
+func (b *Baseline) Contains(finding Finding) bool {
+	return b.index[finding.Fingerprint()]
+}
+
+// Add accepts a finding into the baseline with the given justification
+func (b *Baseline) Add(finding Finding, reason string) {
+	fingerprint := finding.Fingerprint()
+	if b.index[fingerprint] {
+		return
+	}
+	b.index[fingerprint] = true
+	b.Entries = append(b.Entries, BaselineEntry{
+		Fingerprint: fingerprint,
+		RuleID:      finding.RuleID,
+		FilePath:    finding.FilePath,
+		LineNum:     finding.LineNum,
+		Reason:      reason,
+		AddedAt:     time.Now().UTC(),
+	})
+}
+
+// AddEntry accepts a finding known only by its entry, e.g. from a triaged
+// report, reporting whether it was new
+func (b *Baseline) AddEntry(entry BaselineEntry) bool {
+	if b.index[entry.Fingerprint] {
+		return false
+	}
+	b.index[entry.Fingerprint] = true
+	b.Entries = append(b.Entries, entry)
+	return true
+}
+
+// Remove drops an accepted finding by fingerprint, reporting whether it was
+// in the baseline
+func (b *Baseline) Remove(fingerprint string) bool {
+	if !b.index[fingerprint] {
+		return false
+	}
+	delete(b.index, fingerprint)
+	for i, entry := range b.Entries {
+		if entry.Fingerprint == fingerprint {
+			b.Entries = append(b.Entries[:i], b.Entries[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Save writes the baseline to disk
+func (b *Baseline) Save(baselinePath string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(baselinePath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Fingerprint returns a stable identifier for the detected secret that
+// doesn't reveal its value, so it can be stored and shared safely
+func (f *Finding) Fingerprint() string {
+	secret := f.Secret
+	if secret == "" {
+		secret = f.Match
+	}
+	sum := sha256.Sum256([]byte(f.RuleID + ":" + secret))
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// blobCacheHeader starts the first line of a blob cache file, followed by
+// the version the cache was built for
+const blobCacheHeader = "secretlint-blob-cache "
+
+// BlobCache remembers the file changes of history scans that had no
+// findings. A change is keyed by its path and the blob IDs before and after
+// it, so the same change reached through another branch, a cherry-pick or
+// a later scan isn't scanned again. Only those keys are stored, never
+// findings, and the cache starts over when its version changes.
+type BlobCache struct {
+	path    string
+	version string
+	clean   map[string]bool
+	added   int
+
+	// Skipped counts the changes a scan skipped as clean
+	Skipped int
+}
+
+// LoadBlobCache reads the cache at path if it was built for version; a
+// missing or outdated cache is empty
+func LoadBlobCache(path, version string) (*BlobCache, error) {
+	cache := &BlobCache{path: path, version: version, clean: make(map[string]bool)}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cache, nil
+		}
+		return nil, fmt.Errorf("failed to read history cache: %w", err)
+	}
+	defer file.Close()
+
+	lines := bufio.NewScanner(file)
+	if !lines.Scan() || lines.Text() != blobCacheHeader+version {
+		return cache, nil
+	}
+	for lines.Scan() {
+		cache.clean[lines.Text()] = true
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history cache %s: %w", path, err)
+	}
+	return cache, nil
+}
+
+// BlobKey identifies the file change a history line belongs to, or returns
+// "" when the diff didn't name its blobs
+func BlobKey(line DiffLine) string {
+	if line.Blobs == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(line.FilePath + "\x00" + line.Blobs))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Clean reports whether the change with the given key had no findings
+func (c *BlobCache) Clean(key string) bool {
+	return key != "" && c.clean[key]
+}
+
+// MarkClean records that the change with the given key had no findings
+func (c *BlobCache) MarkClean(key string) {
+	if key != "" && !c.clean[key] {
+		c.clean[key] = true
+		c.added++
+	}
+}
+
+// Save writes the cache if changes were added since it was loaded
+func (c *BlobCache) Save() error {
+	if c.added == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(c.path), err)
+	}
+	var data strings.Builder
+	data.WriteString(blobCacheHeader + c.version + "\n")
+	for key := range c.clean {
+		data.WriteString(key + "\n")
+	}
+	if err := os.WriteFile(c.path, []byte(data.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write history cache: %w", err)
+	}
+	c.added = 0
+	return nil
+}
@@ -0,0 +1,153 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+
+	"secretlint/internal/config"
+)
+
+// RuleInfo is the machine-readable description of a rule listed by
+// 'secretlint rules list --format json'
+type RuleInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// Pattern is the regex source; empty for rules that aren't a single
+	// regex (HARDCODED_CREDENTIAL, SENSITIVE_FILE, LITERAL_SECRET)
+	Pattern string `json:"pattern,omitempty"`
+
+	Severity    string         `json:"severity"`
+	Description string         `json:"description,omitempty"`
+	Advice      string         `json:"advice,omitempty"`
+	Tags        []string       `json:"tags"`
+	References  RuleReferences `json:"references"`
+
+	// Pack names the pack the rule comes from; empty for built-in rules
+	Pack string `json:"pack,omitempty"`
+
+	// Custom marks rules defined under custom_rules in the config
+	Custom bool `json:"custom,omitempty"`
+
+	// Version is the pack version for installed packs, and the secretlint
+	// version for built-in rules and optional packs
+	Version string `json:"version"`
+}
+
+// RuleReferences link a rule to weakness classifications and provider docs
+type RuleReferences struct {
+	CWE  []string `json:"cwe"`
+	Docs []string `json:"docs,omitempty"`
+}
+
+// ruleClass tags a built-in rule and names its CWE; rules not listed are
+// CWE-798, use of hard-coded credentials
+type ruleClass struct {
+	tags []string
+	cwe  string
+}
+
+var ruleClasses = map[string]ruleClass{
+	"OPENAI_API_KEY":        {tags: []string{"ai", "api-key"}},
+	"GITHUB_PAT":            {tags: []string{"github", "scm", "token"}},
+	"AWS_ACCESS_KEY":        {tags: []string{"aws", "cloud", "access-key"}},
+	"AWS_SECRET_KEY":        {tags: []string{"aws", "cloud", "access-key"}},
+	"STRIPE_LIVE_PK":        {tags: []string{"stripe", "payments", "api-key"}},
+	"STRIPE_LIVE_SK":        {tags: []string{"stripe", "payments", "api-key"}},
+	"SLACK_TOKEN":           {tags: []string{"slack", "chat", "token"}},
+	"JWT_TOKEN":             {tags: []string{"jwt", "token"}},
+	"GENERIC_API_KEY":       {tags: []string{"generic", "api-key"}},
+	"PRIVATE_KEY":           {tags: []string{"private-key", "crypto"}, cwe: "CWE-321"},
+	"OPENSSH_PRIVATE_KEY":   {tags: []string{"private-key", "ssh", "crypto"}, cwe: "CWE-321"},
+	"PGP_PRIVATE_KEY":       {tags: []string{"private-key", "pgp", "crypto"}, cwe: "CWE-321"},
+	"AGE_SECRET_KEY":        {tags: []string{"private-key", "age", "sops", "crypto"}, cwe: "CWE-321"},
+	"NETRC_CREDENTIAL":      {tags: []string{"netrc", "password", "config"}},
+	"NPMRC_AUTH_TOKEN":      {tags: []string{"npm", "supply-chain", "token"}},
+	"PYPI_TOKEN":            {tags: []string{"pypi", "supply-chain", "token"}},
+	"PGPASS_CREDENTIAL":     {tags: []string{"postgres", "database", "password"}},
+	SensitiveKeyRule:        {tags: []string{"generic", "password", "config"}},
+	SensitiveFileRule:       {tags: []string{"file"}, cwe: "CWE-538"},
+	LiteralSecretRule:       {tags: []string{"generic", "reference"}},
+	"NPM_TOKEN":             {tags: []string{"npm", "supply-chain", "token"}},
+	"CREDIT_CARD":           {tags: []string{"pii", "pci"}, cwe: "CWE-359"},
+	"US_SSN":                {tags: []string{"pii", "national-id"}, cwe: "CWE-359"},
+	"UK_NINO":               {tags: []string{"pii", "national-id"}, cwe: "CWE-359"},
+	"CA_SIN":                {tags: []string{"pii", "national-id"}, cwe: "CWE-359"},
+	"EMAIL_PASSWORD":        {tags: []string{"pii", "password"}},
+	"CIRCLECI_TOKEN":        {tags: []string{"circleci", "ci", "token"}},
+	"BUILDKITE_AGENT_TOKEN": {tags: []string{"buildkite", "ci", "token"}},
+	"JENKINS_API_TOKEN":     {tags: []string{"jenkins", "ci", "token"}},
+	"AZURE_DEVOPS_PAT":      {tags: []string{"azure", "ci", "scm", "token"}},
+	"CODECOV_TOKEN":         {tags: []string{"codecov", "ci", "token"}},
+	"SENTRY_DSN_SECRET":     {tags: []string{"sentry", "observability", "api-key"}},
+	"ROLLBAR_ACCESS_TOKEN":  {tags: []string{"rollbar", "observability", "token"}},
+	"HONEYCOMB_API_KEY":     {tags: []string{"honeycomb", "observability", "api-key"}},
+	"GRAFANA_CLOUD_API_KEY": {tags: []string{"grafana", "observability", "api-key"}},
+	"PAGERDUTY_API_TOKEN":   {tags: []string{"pagerduty", "observability", "token"}},
+	"BACKBLAZE_B2_KEY":      {tags: []string{"backblaze", "storage", "api-key"}},
+	"WASABI_SECRET_KEY":     {tags: []string{"wasabi", "storage", "access-key"}},
+	"CLOUDINARY_API_SECRET": {tags: []string{"cloudinary", "storage", "cdn", "api-key"}},
+	"FASTLY_API_TOKEN":      {tags: []string{"fastly", "cdn", "token"}},
+	"AKAMAI_CLIENT_SECRET":  {tags: []string{"akamai", "cdn", "api-key"}},
+}
+
+// Catalog describes every rule secretlint knows, whether or not it is
+// enabled: built-in rules, optional packs and the installed packs of the
+// config and its custom rules, sorted by ID. version is the running
+// secretlint's.
+func Catalog(cfg *config.Config, version string) []RuleInfo {
+	rules, sources := knownRules(cfg)
+	custom := make(map[string]bool)
+	for _, rule := range cfg.CustomRules {
+		custom[rule.ID] = true
+	}
+	catalog := make([]RuleInfo, 0, len(rules))
+	for _, rule := range rules {
+		info := RuleInfo{
+			ID:          rule.ID,
+			Name:        rule.Name,
+			Severity:    rule.Severity,
+			Description: rule.Description,
+			Advice:      rule.Advice,
+			Tags:        rule.Tags,
+			Pack:        sources[rule.ID],
+			Custom:      custom[rule.ID],
+			Version:     version,
+		}
+		if rule.Pattern != nil {
+			info.Pattern = rule.Pattern.String()
+		}
+		if i := strings.Index(info.Pack, "@"); i >= 0 {
+			info.Version = info.Pack[i+1:]
+		}
+
+		class := ruleClasses[rule.ID]
+		if len(info.Tags) == 0 {
+			info.Tags = class.tags
+		}
+		for _, reference := range rule.References {
+			if strings.HasPrefix(strings.ToUpper(reference), "CWE-") {
+				info.References.CWE = append(info.References.CWE, strings.ToUpper(reference))
+			} else {
+				info.References.Docs = append(info.References.Docs, reference)
+			}
+		}
+		if len(info.References.CWE) == 0 {
+			cwe := class.cwe
+			if cwe == "" {
+				cwe = "CWE-798"
+			}
+			info.References.CWE = []string{cwe}
+		}
+		if rule.Remediation.RevokeURL != "" {
+			info.References.Docs = append(info.References.Docs, rule.Remediation.RevokeURL)
+		}
+		info.References.Docs = append(info.References.Docs, rule.Remediation.DocLinks...)
+		if info.Tags == nil {
+			info.Tags = []string{}
+		}
+		catalog = append(catalog, info)
+	}
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].ID < catalog[j].ID })
+	return catalog
+}
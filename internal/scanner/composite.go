@@ -0,0 +1,235 @@
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"secretlint/internal/config"
+)
+
+// ruleConditions are the signals a rule needs besides its pattern matching:
+// a keyword within a few lines, an entropy threshold, a file filter and the
+// text around the secret. All that are set must hold.
+type ruleConditions struct {
+	// keywords are lower-cased; one must appear within `within` lines of
+	// the match, 0 meaning the matched line itself
+	keywords []string
+	within   int
+
+	// minEntropy is the least Shannon entropy of the secret, in bits per character
+	minEntropy float64
+
+	// paths are globs matched against the file's base name and its path
+	paths []string
+
+	// notPrecededBy and notFollowedBy emulate a negative lookbehind at the
+	// start of the pattern and a negative lookahead at its end, which RE2
+	// lacks: a match with one of these strings right before or after it is
+	// not reported
+	notPrecededBy []string
+	notFollowedBy []string
+}
+
+// lineContext holds the lines scanned together with a line, by number, to
+// look for keywords around a match; onDisk says the file in the working tree,
+// or in the index for staged scans, is the same version, so lines missing
+// from a diff can be read from it
+type lineContext struct {
+	lines  map[int]string
+	onDisk bool
+}
+
+// lineKey groups the lines of one version of a file: one commit's, or the
+// index's and working tree's
+func lineKey(line DiffLine) string {
+	if line.Commit != nil {
+		return line.Commit.SHA + "\x00" + line.FilePath
+	}
+	return line.FilePath
+}
+
+// lineContexts indexes lines by file version, for rules with keywords; it
+// is skipped when no rule has any
+func (s *SecretScanner) lineContexts(lines []DiffLine) map[string]*lineContext {
+	needed := false
+	for _, rule := range s.rules {
+		if rule.conditions != nil && rule.conditions.within > 0 {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil
+	}
+	contexts := make(map[string]*lineContext)
+	for _, line := range lines {
+		key := lineKey(line)
+		context, ok := contexts[key]
+		if !ok {
+			context = &lineContext{lines: make(map[int]string), onDisk: line.Commit == nil}
+			contexts[key] = context
+		}
+		context.lines[line.LineNum] = line.Content
+	}
+	return contexts
+}
+
+// customRules converts the config's custom_rules
+func customRules(rules []config.CustomRule) []ruleDefinition {
+	var definitions []ruleDefinition
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = rule.ID
+		}
+		description := rule.Description
+		if description == "" {
+			description = name + " detected"
+		}
+		conditions := &ruleConditions{
+			within:        rule.Within,
+			minEntropy:    rule.MinEntropy,
+			paths:         rule.Paths,
+			notPrecededBy: rule.NotPrecededBy,
+			notFollowedBy: rule.NotFollowedBy,
+		}
+		for _, keyword := range rule.Keywords {
+			conditions.keywords = append(conditions.keywords, strings.ToLower(keyword))
+		}
+		definitions = append(definitions, ruleDefinition{
+			id:          rule.ID,
+			name:        name,
+			pattern:     rule.Pattern,
+			description: description,
+			advice:      rule.Advice,
+			severity:    rule.Severity,
+			conditions:  conditions,
+			source:      fmt.Sprintf("custom_rules[%d] in %s", i, config.DefaultConfigFile),
+			engine:      rule.Engine,
+		})
+	}
+	return definitions
+}
+
+// allowsPath applies the file filter
+func (c *ruleConditions) allowsPath(filePath string) bool {
+	if c == nil || len(c.paths) == 0 {
+		return true
+	}
+	slashed := filepath.ToSlash(filePath)
+	for _, pattern := range c.paths {
+		if matched, _ := path.Match(pattern, path.Base(slashed)); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, slashed); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsSecret applies the entropy threshold
+func (c *ruleConditions) allowsSecret(secret string) bool {
+	return c == nil || c.minEntropy == 0 || shannonEntropy(secret) >= c.minEntropy
+}
+
+// allowsContext checks the text around a match at content[start:end]
+// against the emulated lookarounds
+func (c *ruleConditions) allowsContext(content string, start, end int) bool {
+	if c == nil {
+		return true
+	}
+	for _, before := range c.notPrecededBy {
+		if strings.HasSuffix(content[:start], before) {
+			return false
+		}
+	}
+	for _, after := range c.notFollowedBy {
+		if strings.HasPrefix(content[end:], after) {
+			return false
+		}
+	}
+	return true
+}
+
+// keywordNear reports whether a keyword appears within range of a match on
+// lineNum, looking at the lines scanned with it and, when they don't cover
+// the range, at the file as read returns it
+func (c *ruleConditions) keywordNear(filePath string, lineNum int, content string, context *lineContext, read func(string) ([]byte, error)) bool {
+	if c == nil || len(c.keywords) == 0 {
+		return true
+	}
+	if c.hasKeyword(content) {
+		return true
+	}
+	if c.within == 0 {
+		return false
+	}
+
+	lines := map[int]string{}
+	onDisk := true
+	if context != nil {
+		lines, onDisk = context.lines, context.onDisk
+	}
+	complete := true
+	for n := lineNum - c.within; n <= lineNum+c.within; n++ {
+		if n < 1 || n == lineNum {
+			continue
+		}
+		line, ok := lines[n]
+		if !ok {
+			complete = false
+			continue
+		}
+		if c.hasKeyword(line) {
+			return true
+		}
+	}
+	if complete || !onDisk {
+		return false
+	}
+
+	data, err := read(filePath)
+	if err != nil {
+		return false
+	}
+	fileLines := strings.Split(string(data), "\n")
+	for n := lineNum - c.within; n <= lineNum+c.within; n++ {
+		if n >= 1 && n <= len(fileLines) && c.hasKeyword(fileLines[n-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ruleConditions) hasKeyword(line string) bool {
+	line = strings.ToLower(line)
+	for _, keyword := range c.keywords {
+		if strings.Contains(line, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
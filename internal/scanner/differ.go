@@ -1,12 +1,14 @@
 package scanner
 
 import (
-	"bufio"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // DiffLine represents a line added in a git diff
@@ -14,8 +16,29 @@ type DiffLine struct {
 	FilePath string
 	LineNum  int
 	Content  string
+	Commit   *CommitInfo
+	
+	// Blobs is "<old>..<new>", the blob IDs of the file change the line
+	// was added in, when the diff names them
+	Blobs string
+}
+
+// CommitInfo identifies the commit that introduced a line when scanning history
+type CommitInfo struct {
+	SHA         string
+	Author      string
+	AuthorEmail string
+	Date        time.Time
+	Subject     string
+	
+	// Shallow marks a boundary commit of a shallow clone. Its parents
+	// weren't fetched, so it appears to add every file it contains.
+	Shallow bool
 }
 
+// commitMarker prefixes commit headers in history output; NUL can't start a diff line
+const commitMarker = "\x00commit "
+
 // GitDiffer handles extracting added lines from git diff
 type GitDiffer struct{}
 
@@ -36,13 +59,164 @@ func (gd *GitDiffer) GetStagedChanges() ([]DiffLine, error) {
 	return gd.parseDiff(string(output))
 }
 
+// GetHistoryChanges returns all lines added by the commits reachable from revs
+// (every ref when revs is empty), tagged with the commit that introduced them
+func (gd *GitDiffer) GetHistoryChanges(revs ...string) ([]DiffLine, error) {
+	if len(revs) == 0 {
+		revs = []string{"--all"}
+	}
+	args := append([]string{
+		"log", "-p", "-U0", "--full-index", "--no-color", "--no-ext-diff",
+		"--format=%x00commit %H%x00%an%x00%ae%x00%aI%x00%s",
+	}, revs...)
+
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git history: %w", err)
+	}
+
+	lines, err := gd.parseHistory(string(output))
+	if err != nil {
+		return nil, err
+	}
+	gd.labelNotes(lines)
+	if shallow := gd.ShallowCommits(); len(shallow) > 0 {
+		for _, line := range lines {
+			if line.Commit != nil && shallow[line.Commit.SHA] {
+				line.Commit.Shallow = true
+			}
+		}
+	}
+	return lines, nil
+}
+
+// ShallowCommits returns the boundary commits of a shallow clone, the
+// oldest ones fetched; it is empty for a full clone
+func (gd *GitDiffer) ShallowCommits() map[string]bool {
+	output, err := exec.Command("git", "rev-parse", "--git-path", "shallow").Output()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil
+	}
+	commits := make(map[string]bool)
+	for _, sha := range strings.Fields(string(data)) {
+		commits[sha] = true
+	}
+	return commits
+}
+
+// labelNotes renames the lines of git notes commits, whose files are named
+// after the annotated object, to <notes ref>:<path>, which 'git show' opens
+func (gd *GitDiffer) labelNotes(lines []DiffLine) {
+	output, err := exec.Command("git", "for-each-ref", "--format=%(refname)", "refs/notes/").Output()
+	if err != nil {
+		return
+	}
+	notesRefs := make(map[string]string)
+	for _, ref := range strings.Fields(string(output)) {
+		commits, err := gd.RevList(ref)
+		if err != nil {
+			continue
+		}
+		for _, sha := range commits {
+			if _, ok := notesRefs[sha]; !ok {
+				notesRefs[sha] = ref
+			}
+		}
+	}
+	if len(notesRefs) == 0 {
+		return
+	}
+	for i := range lines {
+		if commit := lines[i].Commit; commit != nil {
+			if ref, ok := notesRefs[commit.SHA]; ok {
+				lines[i].FilePath = ref + ":" + lines[i].FilePath
+			}
+		}
+	}
+}
+
+// parseHistory splits git log -p output per commit and parses each diff
+func (gd *GitDiffer) parseHistory(logOutput string) ([]DiffLine, error) {
+	var lines []DiffLine
+	
+	chunks := strings.Split("\n"+logOutput, "\n"+commitMarker)
+	for _, chunk := range chunks[1:] {
+		header := chunk
+		body := ""
+		if i := strings.Index(chunk, "\n"); i >= 0 {
+			header, body = chunk[:i], chunk[i+1:]
+		}
+		
+		fields := strings.SplitN(header, "\x00", 5)
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("unexpected commit header in git log output: %q", header)
+		}
+		date, _ := time.Parse(time.RFC3339, fields[3])
+		commit := &CommitInfo{
+			SHA:         fields[0],
+			Author:      fields[1],
+			AuthorEmail: fields[2],
+			Date:        date,
+			Subject:     fields[4],
+		}
+		
+		commitLines, err := gd.parseDiff(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse commit %s: %w", commit.SHA, err)
+		}
+		for i := range commitLines {
+			commitLines[i].Commit = commit
+		}
+		lines = append(lines, commitLines...)
+	}
+	
+	return lines, nil
+}
+
+// GitDir returns the path of the repository's .git directory. From a linked
+// worktree this is the main .git directory, shared by every worktree, so
+// hooks and .git/secretlint state are the same whichever one runs.
+func (gd *GitDiffer) GitDir() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RepoRoot returns the absolute path of the repository's top-level directory
+func (gd *GitDiffer) RepoRoot() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate repository root: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RefsContaining lists the branches and tags that include the given commit
+func (gd *GitDiffer) RefsContaining(sha string) ([]string, error) {
+	output, err := exec.Command("git", "for-each-ref", "--contains", sha, "--format=%(refname:short)").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs containing %s: %w", sha, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
 // parseDiff parses git diff output and extracts added lines
 func (gd *GitDiffer) parseDiff(diffOutput string) ([]DiffLine, error) {
 	var lines []DiffLine
-	scanner := bufio.NewScanner(strings.NewReader(diffOutput))
 	
 	var currentFile string
 	var currentLineNum int
+	var currentBlobs string
+	
+	// Regex to match blob headers: index old..new [mode]
+	indexHeaderRegex := regexp.MustCompile(`^index ([0-9a-f]+\.\.[0-9a-f]+)`)
 	
 	// Regex to match file headers: +++ b/path/to/file
 	fileHeaderRegex := regexp.MustCompile(`^\+\+\+ b/(.+)$`)
@@ -50,8 +224,18 @@ func (gd *GitDiffer) parseDiff(diffOutput string) ([]DiffLine, error) {
 	// Regex to match hunk headers: @@ -old_start,old_count +new_start,new_count @@
 	hunkHeaderRegex := regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
 	
-	for scanner.Scan() {
-		line := scanner.Text()
+	// Split rather than bufio.Scanner, whose token limit fails the whole
+	// diff on a single line longer than 64KB
+	for _, line := range strings.Split(diffOutput, "\n") {
+		
+		if strings.HasPrefix(line, "diff --git ") {
+			currentBlobs = ""
+			continue
+		}
+		if matches := indexHeaderRegex.FindStringSubmatch(line); matches != nil {
+			currentBlobs = matches[1]
+			continue
+		}
 		
 		// Check for file header
 		if matches := fileHeaderRegex.FindStringSubmatch(line); matches != nil {
@@ -78,6 +262,7 @@ func (gd *GitDiffer) parseDiff(diffOutput string) ([]DiffLine, error) {
 				FilePath: currentFile,
 				LineNum:  currentLineNum,
 				Content:  content,
+				Blobs:    currentBlobs,
 			})
 			
 			currentLineNum++
@@ -88,10 +273,6 @@ func (gd *GitDiffer) parseDiff(diffOutput string) ([]DiffLine, error) {
 		// Ignore removed lines (start with -)
 	}
 	
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading diff output: %w", err)
-	}
-	
 	return lines, nil
 }
 
@@ -114,4 +295,291 @@ func (gd *GitDiffer) HasStagedChanges() (bool, error) {
 	}
 	// Exit code 0 means no differences (no staged changes)
 	return false, nil
-}
\ No newline at end of file
+}
+
+// HasUnstagedChanges reports whether the working tree copy of a file differs from the index
+func (gd *GitDiffer) HasUnstagedChanges(filePath string) (bool, error) {
+	cmd := exec.Command("git", "diff", "--quiet", "--", filePath)
+	err := cmd.Run()
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 1 {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to check %s for unstaged changes: %w", filePath, err)
+	}
+	return false, nil
+}
+
+// StageFiles adds the given files to the index
+func (gd *GitDiffer) StageFiles(filePaths ...string) error {
+	if len(filePaths) == 0 {
+		return nil
+	}
+	args := append([]string{"add", "--"}, filePaths...)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage files: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// UnstageFiles removes the given files from the index, keeping working tree changes
+func (gd *GitDiffer) UnstageFiles(filePaths ...string) error {
+	if len(filePaths) == 0 {
+		return nil
+	}
+	// git reset (unlike git restore --staged) also works before the first commit
+	args := append([]string{"reset", "-q", "--"}, filePaths...)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to unstage files: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// RevList returns the commit SHAs selected by the given rev-list arguments
+func (gd *GitDiffer) RevList(args ...string) ([]string, error) {
+	output, err := exec.Command("git", append([]string{"rev-list"}, args...)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// CurrentBranch returns the checked out branch name, or "" on a detached HEAD
+func (gd *GitDiffer) CurrentBranch() string {
+	output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// SetBranch creates or moves a branch to point at the given commit
+func (gd *GitDiffer) SetBranch(name, sha string) error {
+	if output, err := exec.Command("git", "branch", "-f", name, sha).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set branch %s: %s", name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Checkout switches to the given branch
+func (gd *GitDiffer) Checkout(name string) error {
+	if output, err := exec.Command("git", "checkout", "-q", name).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to check out %s: %s", name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// HeadLocations reports where each secret still appears in the HEAD tree, as
+// "file:line" entries. Secrets are passed on stdin so they never show up in
+// the process list.
+func (gd *GitDiffer) HeadLocations(secrets []string) (map[string][]string, error) {
+	locations := make(map[string][]string)
+	if len(secrets) == 0 {
+		return locations, nil
+	}
+
+	cmd := exec.Command("git", "grep", "-I", "-F", "-o", "-n", "-z", "-f", "-", "HEAD", "--")
+	cmd.Stdin = strings.NewReader(strings.Join(secrets, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit code 1 means nothing matched
+		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 1 {
+			return locations, nil
+		}
+		return nil, fmt.Errorf("failed to search HEAD: %w", err)
+	}
+
+	// Each match is "HEAD:<path>\0<line>\0<match>"
+	for _, entry := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		fields := strings.SplitN(entry, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		filePath := strings.TrimPrefix(fields[0], "HEAD:")
+		locations[fields[2]] = append(locations[fields[2]], filePath+":"+fields[1])
+	}
+
+	return locations, nil
+}
+
+// HeadSHA returns the commit HEAD points at, or "" before the first commit
+func (gd *GitDiffer) HeadSHA() string {
+	output, err := exec.Command("git", "rev-parse", "-q", "--verify", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Operation names the multi-step operation the repository is in the middle
+// of (merge, rebase, cherry-pick or revert), or "" when there is none. The
+// commit being made belongs to it.
+func (gd *GitDiffer) Operation() string {
+	output, err := exec.Command("git", "rev-parse", "--git-dir").Output()
+	if err != nil {
+		return ""
+	}
+	gitDir := strings.TrimSpace(string(output))
+	for _, state := range []struct{ path, operation string }{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+	} {
+		if _, err := os.Stat(filepath.Join(gitDir, state.path)); err == nil {
+			return state.operation
+		}
+	}
+	return ""
+}
+
+// AuthorIdent returns the name and email the next commit will be authored
+// with, honoring GIT_AUTHOR_NAME and GIT_AUTHOR_EMAIL
+func (gd *GitDiffer) AuthorIdent() (name, email string) {
+	output, err := exec.Command("git", "var", "GIT_AUTHOR_IDENT").Output()
+	if err != nil {
+		return "", ""
+	}
+	// Name <email> timestamp timezone
+	ident := strings.TrimSpace(string(output))
+	open, end := strings.LastIndex(ident, "<"), strings.LastIndex(ident, ">")
+	if open < 0 || end < open {
+		return "", ""
+	}
+	return strings.TrimSpace(ident[:open]), ident[open+1 : end]
+}
+
+// ConfigValue returns a git config value, or "" if it is unset
+func (gd *GitDiffer) ConfigValue(key string) string {
+	output, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// TrackedFiles lists the files in the index, relative to the repository
+// root. Files with the skip-worktree bit are left out and only counted:
+// outside a sparse checkout they aren't on disk, and elsewhere the working
+// copy is a local edit git deliberately ignores, not what is committed.
+func (gd *GitDiffer) TrackedFiles() (files []string, skipped int, err error) {
+	// -t tags every entry; S marks skip-worktree, which is also how sparse
+	// checkout excludes the files outside its cone
+	output, err := exec.Command("git", "ls-files", "-z", "-t", "--full-name").Output()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+	for _, entry := range strings.Split(string(output), "\x00") {
+		if len(entry) < 3 {
+			continue
+		}
+		if entry[0] == 'S' {
+			skipped++
+			continue
+		}
+		files = append(files, entry[2:])
+	}
+	return files, skipped, nil
+}
+
+// Blame returns the commit that last touched a line at rev (the working tree
+// when rev is ""), or nil if the line isn't committed yet
+func (gd *GitDiffer) Blame(rev, filePath string, lineNum int) (*CommitInfo, error) {
+	args := []string{"blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", lineNum, lineNum)}
+	if rev != "" {
+		args = append(args, rev)
+	}
+	args = append(args, "--", filePath)
+
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s:%d: %w", filePath, lineNum, err)
+	}
+
+	lines := strings.Split(string(output), "\n")
+	header := strings.Fields(lines[0])
+	if len(header) == 0 || strings.Trim(header[0], "0") == "" {
+		return nil, nil
+	}
+
+	commit := &CommitInfo{SHA: header[0]}
+	for _, line := range lines[1:] {
+		switch {
+		case strings.HasPrefix(line, "author "):
+			commit.Author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-mail "):
+			commit.AuthorEmail = strings.Trim(strings.TrimPrefix(line, "author-mail "), "<>")
+		case strings.HasPrefix(line, "author-time "):
+			if seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				commit.Date = time.Unix(seconds, 0).UTC()
+			}
+		case strings.HasPrefix(line, "summary "):
+			commit.Subject = strings.TrimPrefix(line, "summary ")
+		case strings.HasPrefix(line, "\t"):
+			return commit, nil
+		}
+	}
+	return commit, nil
+}
+
+// HeadContent returns a file's content at HEAD
+func (gd *GitDiffer) HeadContent(filePath string) ([]byte, error) {
+	output, err := exec.Command("git", "show", "HEAD:"+filePath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at HEAD: %w", filePath, err)
+	}
+	return output, nil
+}
+
+// StagedFiles lists files added or modified in the index
+func (gd *GitDiffer) StagedFiles() ([]string, error) {
+	output, err := exec.Command("git", "diff", "--cached", "--name-only", "-z", "--diff-filter=ACMR").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged files: %w", err)
+	}
+	var files []string
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// StagedContent returns a file's content as staged in the index
+func (gd *GitDiffer) StagedContent(filePath string) ([]byte, error) {
+	output, err := exec.Command("git", "show", ":"+filePath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read staged %s: %w", filePath, err)
+	}
+	return output, nil
+}
+
+// ContentAt returns a file's content at the given revision
+func (gd *GitDiffer) ContentAt(rev, filePath string) ([]byte, error) {
+	output, err := exec.Command("git", "show", rev+":"+filePath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %.12s: %w", filePath, rev, err)
+	}
+	return output, nil
+}
+
+// StagedBlob returns the object ID of a file's staged content, which stays
+// readable with BlobContent after the index moves on
+func (gd *GitDiffer) StagedBlob(filePath string) (string, error) {
+	output, err := exec.Command("git", "rev-parse", ":"+filePath).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve staged %s: %w", filePath, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// BlobContent returns the content of a blob by object ID
+func (gd *GitDiffer) BlobContent(blob string) ([]byte, error) {
+	output, err := exec.Command("git", "cat-file", "blob", blob).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %.12s: %w", blob, err)
+	}
+	return output, nil
+}
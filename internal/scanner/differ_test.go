@@ -0,0 +1,33 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzParseDiff(f *testing.F) {
+	f.Add("diff --git a/app.env b/app.env\nindex 0000000..1234567 100644\n--- a/app.env\n+++ b/app.env\n@@ -0,0 +1,2 @@\n+KEY=value\n+OTHER=1\n")
+	f.Add("+++ b/a\n@@ -1,3 +7 @@\n context\n-removed\n+added\n")
+	f.Add("@@ -1 +99999999999999999999 @@\n+x\n")
+	f.Add("+++ b/\n+\n+++\n")
+	f.Fuzz(func(t *testing.T, diff string) {
+		lines, err := NewGitDiffer().parseDiff(diff)
+		if err != nil {
+			return
+		}
+		added := 0
+		for _, line := range strings.Split(diff, "\n") {
+			if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+				added++
+			}
+		}
+		if len(lines) != added {
+			t.Fatalf("parsed %d added lines, diff has %d", len(lines), added)
+		}
+		for _, line := range lines {
+			if strings.Contains(line.Content, "\n") {
+				t.Fatalf("added line %q spans lines", line.Content)
+			}
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Text encodings recognized by DecodeText
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingLatin1  = "latin-1"
+)
+
+// sniffLength is how much of a file is inspected to guess its encoding
+const sniffLength = 8000
+
+// DecodeText transcodes file content to UTF-8 so rules can match it. UTF-16
+// is recognized by its BOM or, without one, by the NUL bytes ASCII text leaves
+// in every other position; content that isn't valid UTF-8 is read as latin-1.
+// ok is false for binary content.
+func DecodeText(data []byte) (text []byte, encoding string, ok bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		data = data[3:]
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return decodeUTF16(data[2:], binary.LittleEndian), EncodingUTF16LE, true
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return decodeUTF16(data[2:], binary.BigEndian), EncodingUTF16BE, true
+	}
+
+	sniff := data
+	if len(sniff) > sniffLength {
+		sniff = sniff[:sniffLength]
+	}
+	if bytes.IndexByte(sniff, 0) < 0 {
+		if utf8.Valid(data) {
+			return data, EncodingUTF8, true
+		}
+		return decodeLatin1(data), EncodingLatin1, true
+	}
+
+	switch guessUTF16(sniff) {
+	case EncodingUTF16LE:
+		return decodeUTF16(data, binary.LittleEndian), EncodingUTF16LE, true
+	case EncodingUTF16BE:
+		return decodeUTF16(data, binary.BigEndian), EncodingUTF16BE, true
+	}
+	return nil, "", false
+}
+
+// guessUTF16 detects BOM-less UTF-16: mostly-ASCII text has a NUL in every
+// high byte and none in the low bytes
+func guessUTF16(sniff []byte) string {
+	if len(sniff) < 4 {
+		return ""
+	}
+	zeros := [2]int{}
+	for i, b := range sniff {
+		if b == 0 {
+			zeros[i%2]++
+		}
+	}
+	pairs := len(sniff) / 2
+	switch {
+	case zeros[0] == 0 && zeros[1]*10 >= pairs*3:
+		return EncodingUTF16LE
+	case zeros[1] == 0 && zeros[0]*10 >= pairs*3:
+		return EncodingUTF16BE
+	}
+	return ""
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+func decodeLatin1(data []byte) []byte {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
+
+// normalizeLine transcodes a diff line that isn't valid UTF-8 from latin-1,
+// so snippets and matches print correctly
+func normalizeLine(content string) string {
+	if utf8.ValidString(content) {
+		return content
+	}
+	return string(decodeLatin1([]byte(content)))
+}
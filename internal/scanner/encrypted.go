@@ -0,0 +1,213 @@
+package scanner
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Encryption states of a file, as far as git-crypt and sops are concerned
+const (
+	notEncrypted = iota
+
+	// gitCryptEncrypted files are stored encrypted by git-crypt; a scan of an
+	// unlocked checkout sees plaintext that never reaches the repository
+	gitCryptEncrypted
+
+	// gitCryptPlaintext files are meant for git-crypt but were committed
+	// unencrypted, e.g. from a clone where git-crypt isn't set up
+	gitCryptPlaintext
+
+	// sopsEncrypted files carry sops metadata; only their ENC[...] values
+	// are encrypted
+	sopsEncrypted
+
+	// sopsPlaintext files match a .sops.yaml creation rule but have no sops
+	// metadata, i.e. the decrypted file was committed
+	sopsPlaintext
+)
+
+// gitCryptHeader starts every file git-crypt has encrypted
+var gitCryptHeader = []byte("\x00GITCRYPT\x00")
+
+// sopsValueRegex matches a value sops has encrypted
+var sopsValueRegex = regexp.MustCompile(`ENC\[[A-Z0-9_]+,data:[^\]]*\]`)
+
+// sopsMetadataRegex matches the metadata block sops adds to the files it
+// encrypts, in YAML, JSON, INI and dotenv form
+var sopsMetadataRegex = regexp.MustCompile(`(?m)^(sops:\s*$|\s*"sops"\s*:\s*\{|\[sops\]\s*$|sops_mac=)`)
+
+// encryptedFiles caches the encryption state of scanned files
+type encryptedFiles struct {
+	// mu guards the lazily filled state below, so a scanner can be shared
+	// between goroutines
+	mu     sync.Mutex
+	loaded bool
+
+	// index reads files, .gitattributes and .sops.yaml from the index
+	// rather than the working tree, for staged scans
+	index bool
+
+	// gitCrypt is set when a .gitattributes assigns the git-crypt filter
+	gitCrypt bool
+
+	// sopsRules are the path_regex of the .sops.yaml creation rules
+	sopsRules []*regexp.Regexp
+
+	states map[string]int
+}
+
+// load finds out whether the repository uses git-crypt or sops at all, so
+// repositories that use neither pay nothing per file
+func (e *encryptedFiles) load() {
+	e.loaded = true
+	e.states = make(map[string]int)
+
+	if output, err := exec.Command("git", "ls-files", "--", ".gitattributes", "*/.gitattributes").Output(); err == nil {
+		for _, attributes := range strings.Fields(string(output)) {
+			if data, err := e.read(attributes); err == nil && bytes.Contains(data, []byte("filter=git-crypt")) {
+				e.gitCrypt = true
+				break
+			}
+		}
+	}
+
+	data, err := e.read(".sops.yaml")
+	if err != nil {
+		return
+	}
+	var sops struct {
+		CreationRules []struct {
+			PathRegex string `yaml:"path_regex"`
+		} `yaml:"creation_rules"`
+	}
+	if yaml.Unmarshal(data, &sops) != nil {
+		return
+	}
+	for _, rule := range sops.CreationRules {
+		if rule.PathRegex == "" {
+			continue
+		}
+		if re, err := regexp.Compile(rule.PathRegex); err == nil {
+			e.sopsRules = append(e.sopsRules, re)
+		}
+	}
+}
+
+// read returns a file from the index or the working tree
+func (e *encryptedFiles) read(filePath string) ([]byte, error) {
+	if e.index {
+		return NewGitDiffer().StagedContent(filePath)
+	}
+	return os.ReadFile(filePath)
+}
+
+// state returns the encryption state of a file in the working tree, or in
+// the index for staged scans
+func (e *encryptedFiles) state(filePath string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.loaded {
+		e.load()
+	}
+	if state, ok := e.states[filePath]; ok {
+		return state
+	}
+	state := notEncrypted
+	if e.gitCrypt && usesGitCrypt(filePath, e.index) {
+		state = gitCryptPlaintext
+		if gitCryptStored(filePath) {
+			state = gitCryptEncrypted
+		}
+	} else if e.sopsManaged(filePath) {
+		state = sopsEncrypted
+	} else {
+		slashed := filepath.ToSlash(filePath)
+		for _, re := range e.sopsRules {
+			if re.MatchString(slashed) {
+				state = sopsPlaintext
+				break
+			}
+		}
+	}
+	e.states[filePath] = state
+	return state
+}
+
+// usesGitCrypt reports whether .gitattributes assigns the git-crypt filter
+// to a file; index reads the staged .gitattributes
+func usesGitCrypt(filePath string, index bool) bool {
+	args := []string{"check-attr", "filter", "--", filePath}
+	if index {
+		args = []string{"check-attr", "--cached", "filter", "--", filePath}
+	}
+	output, err := exec.Command("git", args...).Output()
+	return err == nil && strings.HasSuffix(strings.TrimSpace(string(output)), ": filter: git-crypt")
+}
+
+// gitCryptStored reports whether git-crypt encrypts the file in the
+// repository: the staged blob is checked, or for files not yet added,
+// whether the git-crypt filter is set up in this clone
+func gitCryptStored(filePath string) bool {
+	if blob, err := NewGitDiffer().StagedContent(filePath); err == nil {
+		return bytes.HasPrefix(blob, gitCryptHeader)
+	}
+	return NewGitDiffer().ConfigValue("filter.git-crypt.clean") != ""
+}
+
+// sopsManaged reports whether a file carries sops metadata. Only formats
+// sops encrypts value by value are read.
+func (e *encryptedFiles) sopsManaged(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml", ".json", ".ini", ".env":
+	default:
+		if !strings.HasPrefix(filepath.Base(filePath), ".env") {
+			return false
+		}
+	}
+	data, err := e.read(filePath)
+	return err == nil && sopsMetadataRegex.Match(data)
+}
+
+// inSopsValue reports whether a match lies inside a sops-encrypted value,
+// which is ciphertext whatever file it's in
+func inSopsValue(content string, start, end int) bool {
+	for _, loc := range sopsValueRegex.FindAllStringIndex(content, -1) {
+		if start >= loc[0] && end <= loc[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEncryption drops findings in files the repository only stores
+// encrypted, and explains findings in files that should have been. Findings
+// from history are left alone: an old revision may predate the encryption.
+func (s *SecretScanner) checkEncryption(finding *Finding) bool {
+	if finding.Commit != nil {
+		return true
+	}
+	switch s.encrypted.state(finding.FilePath) {
+	case gitCryptEncrypted:
+		return false
+	case gitCryptPlaintext:
+		finding.Description += " (in a git-crypt file committed unencrypted)"
+		finding.Advice = "Set up git-crypt in this clone ('git-crypt unlock'), re-add the file with 'git rm --cached " + finding.FilePath + " && git add " + finding.FilePath + "', and rotate the secret"
+	case sopsEncrypted:
+		if finding.RuleID == SensitiveFileRule {
+			return false
+		}
+		finding.Description += " (left unencrypted in a sops file)"
+		finding.Advice = "Encrypt the value with 'sops --encrypt --in-place " + finding.FilePath + "'; check unencrypted_suffix and encrypted_regex in .sops.yaml"
+	case sopsPlaintext:
+		finding.Description += " (in a file .sops.yaml says to encrypt, committed as plaintext)"
+		finding.Advice = "Encrypt the file with 'sops --encrypt --in-place " + finding.FilePath + "' before committing, and rotate the secret"
+	}
+	return true
+}
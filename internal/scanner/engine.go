@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/dlclark/regexp2"
+
+	"secretlint/internal/regexcache"
+)
+
+// Pattern is a compiled rule pattern: a *regexp.Regexp, or a regexp2 regexp
+// for rules with engine: regexp2. Indexes are byte offsets, as in Go's regexp.
+type Pattern interface {
+	FindAllStringSubmatchIndex(s string, n int) [][]int
+	SubexpIndex(name string) int
+	String() string
+}
+
+// compilePattern compiles a rule's pattern with the engine it selects
+func compilePattern(ruleID, engine, pattern string) (Pattern, error) {
+	if engine != regexcache.EngineRegexp2 {
+		re, err := regexcache.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re, nil
+	}
+	re, err := regexcache.CompileRegexp2(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &regexp2Pattern{ruleID: ruleID, re: re}, nil
+}
+
+// regexp2Pattern adapts regexp2, whose matches are in runes, to Pattern.
+// A search that times out ends the matches found on the line, with one
+// warning per rule.
+type regexp2Pattern struct {
+	ruleID   string
+	re       *regexp2.Regexp
+	timedOut sync.Once
+}
+
+func (p *regexp2Pattern) String() string {
+	return p.re.String()
+}
+
+func (p *regexp2Pattern) SubexpIndex(name string) int {
+	return p.re.GroupNumberFromName(name)
+}
+
+func (p *regexp2Pattern) FindAllStringSubmatchIndex(s string, n int) [][]int {
+	// offsets[i] is the byte offset of rune i, decoded the way regexp2 does
+	offsets := make([]int, 0, len(s)+1)
+	for i := range s {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(s))
+
+	numbers := p.re.GetGroupNumbers()
+	last := 0
+	for _, number := range numbers {
+		if number > last {
+			last = number
+		}
+	}
+
+	var matches [][]int
+	m, err := p.re.FindStringMatch(s)
+	for m != nil && (n < 0 || len(matches) < n) {
+		match := make([]int, 2*(last+1))
+		for i := range match {
+			match[i] = -1
+		}
+		for _, number := range numbers {
+			group := m.GroupByNumber(number)
+			if group == nil || len(group.Captures) == 0 {
+				continue
+			}
+			match[2*number] = offsets[group.Index]
+			match[2*number+1] = offsets[group.Index+group.Length]
+		}
+		matches = append(matches, match)
+		m, err = p.re.FindNextMatch(m)
+	}
+	if err != nil {
+		p.timedOut.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: rule %s gave up on a line after %s; simplify its pattern so it doesn't miss secrets\n", p.ruleID, regexcache.MatchTimeout)
+		})
+	}
+	return matches
+}
@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"testing"
+
+	"secretlint/internal/config"
+)
+
+func TestRegexp2Rule(t *testing.T) {
+	secretScanner, err := NewRuleScanner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretScanner.addRules(customRules([]config.CustomRule{{
+		ID:      "ACME_TOKEN",
+		Pattern: `(?<![\w-])(?P<secret>acme_[a-z0-9]{16})(?!_test)\b`,
+		Engine:  "regexp2",
+	}}))
+	if errs := secretScanner.ruleErrors; len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	tests := []struct {
+		content string
+		want    string
+		start   int
+	}{
+		{content: `clé = "acme_0123456789abcdef"`, want: "acme_0123456789abcdef", start: 8},
+		{content: `clé = "acme_0123456789abcdef_test"`},
+		{content: `clé = "x-acme_0123456789abcdef"`},
+	}
+	for _, test := range tests {
+		var got []Finding
+		for _, finding := range secretScanner.ScanLine("app.env", 1, test.content) {
+			if finding.RuleID == "ACME_TOKEN" {
+				got = append(got, finding)
+			}
+		}
+		if test.want == "" {
+			if len(got) > 0 {
+				t.Errorf("%q: unexpected finding %q", test.content, got[0].Secret)
+			}
+			continue
+		}
+		if len(got) != 1 {
+			t.Fatalf("%q: got %d findings, want 1", test.content, len(got))
+		}
+		if got[0].Secret != test.want || got[0].StartPos != test.start || test.content[got[0].StartPos:got[0].EndPos] != got[0].Match {
+			t.Errorf("%q: got %q at [%d:%d], want %q at %d", test.content, got[0].Secret, got[0].StartPos, got[0].EndPos, test.want, test.start)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SensitiveFileRule is reported for files whose name alone says they hold
+// credentials, whatever their content
+const SensitiveFileRule = "SENSITIVE_FILE"
+
+// sensitiveFiles are base-name patterns of files that should never be committed
+var sensitiveFiles = []struct {
+	pattern     string
+	description string
+}{
+	{"id_rsa", "SSH private key"},
+	{"id_dsa", "SSH private key"},
+	{"id_ecdsa", "SSH private key"},
+	{"id_ed25519", "SSH private key"},
+	{"id_ecdsa_sk", "SSH private key"},
+	{"id_ed25519_sk", "SSH private key"},
+	{"secring.gpg", "GnuPG secret keyring"},
+	{"*.pem", "PEM certificate or private key"},
+	{"*.key", "Private key"},
+	{"*.p12", "PKCS#12 key store"},
+	{"*.pfx", "PKCS#12 key store"},
+	{"*.jks", "Java key store"},
+	{"*.keystore", "Key store"},
+	{"*.kdbx", "KeePass password database"},
+	{"credentials.json", "Cloud credentials file"},
+	{"service-account*.json", "Google Cloud service account key"},
+	{"client_secret*.json", "OAuth client secret"},
+	{".npmrc", "npm config, which usually holds registry tokens"},
+	{".pypirc", "PyPI config, which usually holds upload tokens"},
+	{".netrc", "netrc file with machine passwords"},
+	{".pgpass", "PostgreSQL password file"},
+	{".htpasswd", "htpasswd password hashes"},
+	{".git-credentials", "Git credential store"},
+	{".dockercfg", "Docker registry credentials"},
+}
+
+// sensitivePaths match on the last two path segments
+var sensitivePaths = []struct {
+	pattern     string
+	description string
+}{
+	{".docker/config.json", "Docker registry credentials"},
+	{".aws/credentials", "AWS credentials file"},
+	{".kube/config", "Kubernetes credentials"},
+	{"age/keys.txt", "age identity file, as used by sops"},
+	{".ssh/*", "SSH private key"}, // whatever its name, next to known_hosts
+}
+
+// sshPublicFiles are files of an .ssh directory that hold no private keys
+var sshPublicFiles = map[string]bool{
+	"known_hosts":      true,
+	"known_hosts.old":  true,
+	"authorized_keys":  true,
+	"authorized_keys2": true,
+	"config":           true,
+}
+
+// ScanPaths flags files that look like credential stores by name alone, at
+// the given severity. Ignored files and baselined findings are skipped.
+func (s *SecretScanner) ScanPaths(filePaths []string, severity string) []Finding {
+	if s.disabled[SensitiveFileRule] {
+		return nil
+	}
+	var findings []Finding
+	for _, filePath := range filePaths {
+		if s.ignoreChecker.ShouldIgnore(filePath) {
+			continue
+		}
+		description, ok := sensitiveFileDescription(filePath)
+		if !ok {
+			continue
+		}
+		finding := Finding{
+			RuleID:      SensitiveFileRule,
+			RuleName:    "Sensitive File",
+			FilePath:    filePath,
+			Match:       filepath.Base(filePath),
+			Secret:      filePath,
+			Description: description + " committed to the repository",
+			Advice:      s.advice(SensitiveFileRule, "Remove the file from git (git rm --cached), add it to .gitignore, and rotate anything it contains"),
+			Remediation: s.remediation(SensitiveFileRule, Remediation{}),
+			Severity:    s.severity(SensitiveFileRule, severity),
+			masking:     s.masking,
+		}
+		if !s.baseline.Contains(finding) && !s.allowed(finding) && s.checkEncryption(&finding) {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// sensitiveFileDescription matches a path against the sensitive file patterns
+func sensitiveFileDescription(filePath string) (string, bool) {
+	slashed := filepath.ToSlash(filePath)
+	base := strings.ToLower(path.Base(slashed))
+	// Public halves of key pairs are meant to be shared
+	if strings.HasSuffix(base, ".pub") {
+		return "", false
+	}
+	for _, candidate := range sensitiveFiles {
+		if matched, _ := path.Match(candidate.pattern, base); matched {
+			return candidate.description, true
+		}
+	}
+	lastTwo := slashed
+	if i := strings.LastIndex(slashed, "/"); i >= 0 {
+		if j := strings.LastIndex(slashed[:i], "/"); j >= 0 {
+			lastTwo = slashed[j+1:]
+		}
+	}
+	for _, candidate := range sensitivePaths {
+		if matched, _ := path.Match(candidate.pattern, lastTwo); !matched {
+			continue
+		}
+		// Any file kept next to known_hosts is taken for a key, except these
+		if strings.HasPrefix(candidate.pattern, ".ssh/") && sshPublicFiles[base] {
+			continue
+		}
+		return candidate.description, true
+	}
+	return "", false
+}
@@ -0,0 +1,31 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadFileLines loads a file from disk as numbered lines so it can go
+// through the same scanning pipeline as diff output
+func ReadFileLines(filePath string) ([]DiffLine, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	return ContentLines(filePath, data), nil
+}
+
+// ContentLines splits file content into numbered lines
+func ContentLines(filePath string, data []byte) []DiffLine {
+	var lines []DiffLine
+	for i, content := range strings.Split(string(data), "\n") {
+		lines = append(lines, DiffLine{
+			FilePath: filePath,
+			LineNum:  i + 1,
+			Content:  strings.TrimSuffix(content, "\r"),
+		})
+	}
+	return lines
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// MinGitVersion is the oldest git secretlint works with; older releases
+// lack options it relies on, such as rev-parse --git-common-dir and
+// for-each-ref --contains
+var MinGitVersion = [2]int{2, 7}
+
+// gitVersionRegex reads 'git version 2.39.2', including vendor suffixes
+// such as '.windows.1' or ' (Apple Git-128)'
+var gitVersionRegex = regexp.MustCompile(`git version (\d+)\.(\d+)`)
+
+var gitCheck struct {
+	once sync.Once
+	err  error
+}
+
+// CheckGit reports why git can't be used: it isn't on PATH, or it is older
+// than MinGitVersion. The answer is worked out once per process.
+func CheckGit() error {
+	gitCheck.once.Do(func() {
+		if _, err := exec.LookPath("git"); err != nil {
+			gitCheck.err = fmt.Errorf("git is not installed or not on PATH")
+			return
+		}
+		output, err := exec.Command("git", "--version").Output()
+		if err != nil {
+			gitCheck.err = fmt.Errorf("failed to run git --version: %w", err)
+			return
+		}
+		match := gitVersionRegex.FindStringSubmatch(string(output))
+		if match == nil {
+			// An unusual build string isn't worth refusing to run over
+			return
+		}
+		major, _ := strconv.Atoi(match[1])
+		minor, _ := strconv.Atoi(match[2])
+		if major < MinGitVersion[0] || (major == MinGitVersion[0] && minor < MinGitVersion[1]) {
+			gitCheck.err = fmt.Errorf("git %d.%d is too old (secretlint needs %d.%d or newer)", major, minor, MinGitVersion[0], MinGitVersion[1])
+		}
+	})
+	return gitCheck.err
+}
+
+// WalkFiles lists the files under root, relative to it, skipping .git, .hg
+// and .jj directories. It stands in for the tracked file list when git can't
+// be used.
+func WalkFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped, not fatal
+			if info != nil && info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if name := info.Name(); name == ".git" || name == ".hg" || name == ".jj" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files under %s: %w", root, err)
+	}
+	return files, nil
+}
@@ -1,7 +1,6 @@
 package scanner
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,7 +8,8 @@ import (
 	"strings"
 )
 
-// IgnoreChecker handles .secretignore file parsing and matching
+// IgnoreChecker handles .secretignore file parsing and matching. Matching is
+// safe for concurrent use; loading and adding patterns is not.
 type IgnoreChecker struct {
 	patterns []string
 	regexes  []*regexp.Regexp
@@ -25,7 +25,7 @@ func NewIgnoreChecker() *IgnoreChecker {
 
 // LoadIgnoreFile loads patterns from .secretignore file
 func (ic *IgnoreChecker) LoadIgnoreFile(ignoreFilePath string) error {
-	file, err := os.Open(ignoreFilePath)
+	data, err := os.ReadFile(ignoreFilePath)
 	if err != nil {
 		// If .secretignore doesn't exist, that's OK
 		if os.IsNotExist(err) {
@@ -33,11 +33,9 @@ func (ic *IgnoreChecker) LoadIgnoreFile(ignoreFilePath string) error {
 		}
 		return fmt.Errorf("failed to open .secretignore: %w", err)
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
 		
 		// Skip empty lines and comments
 		if line == "" || strings.HasPrefix(line, "#") {
@@ -47,19 +45,23 @@ func (ic *IgnoreChecker) LoadIgnoreFile(ignoreFilePath string) error {
 		// Add pattern
 		if err := ic.AddPattern(line); err != nil {
 			// Log warning but continue processing
-			fmt.Fprintf(os.Stderr, "Warning: invalid pattern '%s': %v\n", line, err)
+			shown := line
+			if len(shown) > 80 {
+				shown = shown[:80] + "…"
+			}
+			fmt.Fprintf(os.Stderr, "Warning: invalid pattern '%s': %v\n", shown, err)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading .secretignore: %w", err)
-	}
-
 	return nil
 }
 
 // AddPattern adds a glob pattern to the ignore list
 func (ic *IgnoreChecker) AddPattern(pattern string) error {
+	if len(pattern) > maxPatternLength {
+		return fmt.Errorf("pattern is %d bytes long (limit %d)", len(pattern), maxPatternLength)
+	}
+	
 	// Convert glob pattern to regex
 	regex, err := ic.globToRegex(pattern)
 	if err != nil {
@@ -79,22 +81,27 @@ func (ic *IgnoreChecker) AddPattern(pattern string) error {
 
 // ShouldIgnore checks if a file path should be ignored
 func (ic *IgnoreChecker) ShouldIgnore(filePath string) bool {
+	return ic.MatchingPattern(filePath) != ""
+}
+
+// MatchingPattern returns the first pattern that ignores a file path, or ""
+func (ic *IgnoreChecker) MatchingPattern(filePath string) string {
 	// Normalize path separators for cross-platform compatibility
 	normalizedPath := filepath.ToSlash(filePath)
 	
-	for _, regex := range ic.regexes {
+	for i, regex := range ic.regexes {
 		if regex.MatchString(normalizedPath) {
-			return true
+			return ic.patterns[i]
 		}
 		
 		// Also check just the filename
 		filename := filepath.Base(normalizedPath)
 		if regex.MatchString(filename) {
-			return true
+			return ic.patterns[i]
 		}
 	}
 	
-	return false
+	return ""
 }
 
 // globToRegex converts a glob pattern to a regular expression
@@ -112,6 +119,10 @@ func (ic *IgnoreChecker) globToRegex(glob string) (string, error) {
 					// **/
 					regex += "(?:.*/|^)"
 					i += 3
+					// Repeated **/ match nothing more than one
+					for strings.HasPrefix(glob[i:], "**/") {
+						i += 3
+					}
 				} else if i+2 == len(glob) {
 					// ** at end
 					regex += ".*"
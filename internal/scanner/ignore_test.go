@@ -0,0 +1,36 @@
+package scanner
+
+import (
+	"regexp"
+	"testing"
+)
+
+func FuzzGlobToRegex(f *testing.F) {
+	for _, glob := range []string{"*.log", "**/*.test.js", "build/", "[!a]?.txt", "**/**/x", "a**b", "[", "[]]", "(a|b).{c}"} {
+		f.Add(glob, "src/app/main.test.js")
+	}
+	f.Fuzz(func(t *testing.T, glob, path string) {
+		if len(glob) > maxPatternLength {
+			return
+		}
+		ic := NewIgnoreChecker()
+		pattern, err := ic.globToRegex(glob)
+		if err != nil {
+			return
+		}
+		// Character classes are copied through, so a few globs yield invalid
+		// regexes; AddPattern reports those instead of panicking
+		if _, err := regexp.Compile(pattern); err != nil {
+			if ic.AddPattern(glob) == nil {
+				t.Fatalf("AddPattern accepted %q, whose regex %q doesn't compile", glob, pattern)
+			}
+			return
+		}
+		if err := ic.AddPattern(glob); err != nil {
+			t.Fatalf("AddPattern(%q): %v", glob, err)
+		}
+		if ic.ShouldIgnore(path) != (ic.MatchingPattern(path) == glob) {
+			t.Fatalf("ShouldIgnore and MatchingPattern disagree on %q for %q", path, glob)
+		}
+	})
+}
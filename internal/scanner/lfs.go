@@ -0,0 +1,173 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultLFSMaxSizeMB caps the LFS objects read when lfs.max_size_mb is unset
+const DefaultLFSMaxSizeMB = 10
+
+// Lines of a Git LFS pointer file (https://github.com/git-lfs/git-lfs/blob/main/docs/spec.md)
+var (
+	lfsVersionRegex = regexp.MustCompile(`^version https://git-lfs\.github\.com/spec/v1$`)
+	lfsOIDRegex     = regexp.MustCompile(`^oid sha256:([0-9a-f]{64})$`)
+	lfsSizeRegex    = regexp.MustCompile(`^size (\d+)$`)
+)
+
+// lfsPointer identifies the object an LFS pointer stands for; size is 0
+// when the lines at hand don't include it
+type lfsPointer struct {
+	oid  string
+	size int64
+}
+
+// IsLFSPointer reports whether file content is a Git LFS pointer
+func IsLFSPointer(data []byte) bool {
+	_, ok := readLFSPointer(data)
+	return ok
+}
+
+func readLFSPointer(data []byte) (lfsPointer, bool) {
+	if len(data) > 1024 {
+		return lfsPointer{}, false
+	}
+	return parseLFSPointer(strings.Split(string(data), "\n"))
+}
+
+// LFSObject returns the text of the object behind an LFS pointer, so
+// structured files tracked in LFS can be parsed; ok is false when
+// settings.lfs is off or the object can't be read or isn't text, and
+// ScanLines then explains why
+func (s *SecretScanner) LFSObject(pointerData []byte) (text []byte, ok bool) {
+	pointer, isPointer := readLFSPointer(pointerData)
+	if !isPointer || !s.lfs.Enabled {
+		return nil, false
+	}
+	data, err := s.lfsObject(pointer)
+	if err != nil {
+		return nil, false
+	}
+	text, _, ok = DecodeText(data)
+	return text, ok
+}
+
+// parseLFSPointer reads pointer lines. A diff of a changed pointer only
+// holds the oid and size lines, so the version line isn't required.
+func parseLFSPointer(contents []string) (lfsPointer, bool) {
+	var pointer lfsPointer
+	for _, content := range contents {
+		content = strings.TrimSuffix(content, "\r")
+		if content == "" {
+			continue
+		}
+		if match := lfsOIDRegex.FindStringSubmatch(content); match != nil {
+			pointer.oid = match[1]
+		} else if match := lfsSizeRegex.FindStringSubmatch(content); match != nil {
+			pointer.size, _ = strconv.ParseInt(match[1], 10, 64)
+		} else if !lfsVersionRegex.MatchString(content) {
+			return lfsPointer{}, false
+		}
+	}
+	return pointer, pointer.oid != ""
+}
+
+// expandLFS takes the pointer lines of LFS files out of the lines to scan.
+// With settings.lfs enabled they are replaced by the lines of the object
+// the pointer stands for, when it is text and within the size cap; the
+// files that can't be scanned are named on stderr, so none escapes silently.
+func (s *SecretScanner) expandLFS(lines []DiffLine) []DiffLine {
+	// A file's lines from one commit (or the index) make up one pointer
+	key := lineKey
+	groups := make(map[string][]string)
+	for _, line := range lines {
+		groups[key(line)] = append(groups[key(line)], line.Content)
+	}
+	pointers := make(map[string]lfsPointer)
+	for k, contents := range groups {
+		if pointer, ok := parseLFSPointer(contents); ok {
+			pointers[k] = pointer
+		}
+	}
+	if len(pointers) == 0 {
+		return lines
+	}
+
+	var expanded []DiffLine
+	done := make(map[string]bool)
+	for _, line := range lines {
+		k := key(line)
+		pointer, ok := pointers[k]
+		if !ok {
+			expanded = append(expanded, line)
+			continue
+		}
+		if done[k] {
+			continue
+		}
+		done[k] = true
+		if !s.lfs.Enabled {
+			fmt.Fprintf(os.Stderr, "ℹ️  %s is a Git LFS pointer; its content is not scanned (settings.lfs.enabled)\n", line.FilePath)
+			continue
+		}
+		data, err := s.lfsObject(pointer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ℹ️  Git LFS object of %s not scanned: %v\n", line.FilePath, err)
+			continue
+		}
+		text, _, ok := DecodeText(data)
+		if !ok {
+			continue
+		}
+		for _, objectLine := range ContentLines(line.FilePath, text) {
+			objectLine.Commit = line.Commit
+			expanded = append(expanded, objectLine)
+		}
+	}
+	return expanded
+}
+
+// lfsObject reads an LFS object from the local store, or downloads it with
+// 'git lfs smudge' when it isn't there and the pointer says how big it is
+func (s *SecretScanner) lfsObject(pointer lfsPointer) ([]byte, error) {
+	maxSize := int64(s.lfs.MaxSizeMB)
+	if maxSize == 0 {
+		maxSize = DefaultLFSMaxSizeMB
+	}
+	maxSize <<= 20
+	if pointer.size > maxSize {
+		return nil, fmt.Errorf("%d bytes, over lfs.max_size_mb", pointer.size)
+	}
+
+	if gitDir, err := NewGitDiffer().GitDir(); err == nil {
+		objectPath := filepath.Join(gitDir, "lfs", "objects", pointer.oid[:2], pointer.oid[2:4], pointer.oid)
+		if info, err := os.Stat(objectPath); err == nil {
+			if info.Size() > maxSize {
+				return nil, fmt.Errorf("%d bytes, over lfs.max_size_mb", info.Size())
+			}
+			return os.ReadFile(objectPath)
+		}
+	}
+
+	if pointer.size == 0 {
+		return nil, fmt.Errorf("not in the local LFS store")
+	}
+	if _, err := exec.LookPath("git-lfs"); err != nil {
+		return nil, fmt.Errorf("not in the local LFS store, and downloading it needs the git-lfs CLI (https://git-lfs.com)")
+	}
+	cmd := exec.Command("git", "lfs", "smudge")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", pointer.oid, pointer.size))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git lfs smudge failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return data, nil
+}
@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxLineLength is how much of a line is scanned; the rest of longer lines
+// (minified bundles, embedded blobs) is cut off at a truncation marker
+const MaxLineLength = 1 << 20
+
+// maxPatternLength bounds .secretignore patterns so a pathological glob
+// can't compile into a huge regex that every path is matched against
+const maxPatternLength = 4096
+
+// truncationMarker ends a line cut at MaxLineLength, with the number of
+// bytes dropped
+const truncationMarker = "…[truncated %d bytes]"
+
+// clampLine cuts a line longer than MaxLineLength at a rune boundary and
+// marks the cut, reporting whether it did
+func clampLine(content string) (string, bool) {
+	if len(content) <= MaxLineLength {
+		return content, false
+	}
+	end := MaxLineLength
+	for end > 0 && !utf8.RuneStart(content[end]) {
+		end--
+	}
+	return content[:end] + fmt.Sprintf(truncationMarker, len(content)-end), true
+}
+
+// stripNUL turns NUL bytes into spaces, keeping match offsets, so they
+// can't reach terminal output or reports
+func stripNUL(content string) string {
+	if strings.IndexByte(content, 0) < 0 {
+		return content
+	}
+	return strings.ReplaceAll(content, "\x00", " ")
+}
+
+// scanLineSafely runs ScanLine, turning a panic on an adversarial line into
+// a warning so one line can't abort the whole scan
+func (s *SecretScanner) scanLineSafely(line DiffLine, context *lineContext) (findings []Finding) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipped %s:%d: %v\n", line.FilePath, line.LineNum, r)
+			findings = nil
+		}
+	}()
+	return s.scanLine(line.FilePath, line.LineNum, line.Content, context)
+}
@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"secretlint/internal/config"
+)
+
+// Masking strategies for secrets in output and reports
+const (
+	MaskPartial = "partial"
+	MaskFull    = "full"
+	MaskHash    = "hash"
+)
+
+// DefaultReveal is how many characters partial masking shows at most
+const DefaultReveal = 8
+
+// unmasked is only ever set by an explicit --no-mask and only affects what
+// DisplayValue prints to the terminal. The masking strategy isn't global:
+// each scanner keeps its settings.masking and stamps it on its findings.
+var (
+	maskMu   sync.RWMutex
+	unmasked bool
+)
+
+// Unmask makes DisplayValue show secrets in full, for trusted local use
+func Unmask() {
+	maskMu.Lock()
+	defer maskMu.Unlock()
+	unmasked = true
+}
+
+// DisplayValue hides a value printed to the terminal, unless --no-mask was
+// given. Anything written to a file or sent elsewhere uses MaskValue.
+func DisplayValue(value string) string {
+	return displayValue(value, config.MaskingSettings{})
+}
+
+// MaskValue hides a value with the default masking, for values no scanner
+// reported. Findings mask with their scanner's settings instead.
+func MaskValue(value string) string {
+	return maskValue(value, config.MaskingSettings{})
+}
+
+// displayValue is maskValue for the terminal, where --no-mask shows values
+func displayValue(value string, masking config.MaskingSettings) string {
+	maskMu.RLock()
+	show := unmasked
+	maskMu.RUnlock()
+	if show {
+		return value
+	}
+	return maskValue(value, masking)
+}
+
+// maskValue hides a value according to the masking settings; empty values
+// mean the defaults. Reports, baselines, logs and payloads always use it,
+// even with --no-mask.
+func maskValue(value string, masking config.MaskingSettings) string {
+	switch masking.Strategy {
+	case MaskFull:
+		// A fixed width doesn't give away the secret's length
+		return "********"
+	case MaskHash:
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:])[:12]
+	}
+	reveal := masking.Reveal
+	if reveal == 0 {
+		reveal = DefaultReveal
+	}
+	return maskPartial(value, reveal)
+}
+
+// maskPartial masks the middle of a value, keeping a few characters for
+// recognition. It shows at most reveal characters and never more than a
+// quarter of the value, so short secrets stay mostly hidden.
+func maskPartial(value string, reveal int) string {
+	// Count characters, not bytes, so transcoded non-ASCII text isn't split
+	runes := []rune(value)
+	shown := len(runes) / 4
+	if shown > reveal {
+		shown = reveal
+	}
+	
+	// Split what is shown between the start and the end
+	prefix := string(runes[:shown-shown/2])
+	suffix := string(runes[len(runes)-shown/2:])
+	middle := strings.Repeat("*", len(runes)-shown)
+	return prefix + middle + suffix
+}
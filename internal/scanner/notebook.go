@@ -0,0 +1,38 @@
+package scanner
+
+import (
+	"secretlint/internal/structured"
+)
+
+// scanNotebook scans the decoded text of notebook cells rather than the raw
+// JSON, so escaped quotes and newlines don't hide secrets. Findings keep the
+// .ipynb line number and name the cell they were found in. Lines outside any
+// cell (metadata) are scanned as they are.
+func (s *SecretScanner) scanNotebook(lines []DiffLine, content []byte) []Finding {
+	cellLines, err := structured.ParseNotebook(content)
+	if err != nil {
+		return s.ScanLines(lines)
+	}
+	byFileLine := make(map[int][]structured.CellLine)
+	for _, cellLine := range cellLines {
+		byFileLine[cellLine.FileLine] = append(byFileLine[cellLine.FileLine], cellLine)
+	}
+
+	var findings []Finding
+	for _, line := range lines {
+		inCells, ok := byFileLine[line.LineNum]
+		if !ok {
+			findings = append(findings, s.ScanLines([]DiffLine{line})...)
+			continue
+		}
+		for _, cellLine := range inCells {
+			decoded := line
+			decoded.Content = cellLine.Text
+			for _, finding := range s.ScanLines([]DiffLine{decoded}) {
+				finding.Cell = cellLine.Location()
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,344 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"secretlint/internal/rulepack"
+)
+
+// rulePacks are optional rule sets, off unless listed under packs: in the config
+var rulePacks = map[string][]ruleDefinition{
+	"pii":           piiRules,
+	"npm":           npmRules,
+	"cicd":          cicdRules,
+	"observability": observabilityRules,
+	"storage":       storageRules,
+}
+
+// RulePacks lists the names of the optional rule packs
+func RulePacks() []string {
+	var names []string
+	for name := range rulePacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// enablePacks adds the rules of each named pack, warning about unknown names.
+// Names pinned as name@version refer to packs installed with 'secretlint rules install'.
+func (s *SecretScanner) enablePacks(names []string) {
+	for _, name := range names {
+		if strings.Contains(name, "@") {
+			pack, err := rulepack.Load(name)
+			if err != nil {
+				s.ruleErrors = append(s.ruleErrors, err)
+				continue
+			}
+			s.addRules(installedRules(pack))
+			continue
+		}
+		rules, ok := rulePacks[strings.ToLower(name)]
+		if !ok {
+			s.ruleErrors = append(s.ruleErrors, fmt.Errorf("unknown rule pack %q (available: %s)", name, strings.Join(RulePacks(), ", ")))
+			continue
+		}
+		s.addRules(rules)
+	}
+}
+
+// installedRules converts the rules of an installed pack
+func installedRules(pack *rulepack.Pack) []ruleDefinition {
+	var rules []ruleDefinition
+	for i, rule := range pack.Rules {
+		name := rule.Name
+		if name == "" {
+			name = rule.ID
+		}
+		definition := ruleDefinition{
+			id:          rule.ID,
+			name:        name,
+			pattern:     rule.Pattern,
+			description: rule.Description,
+			advice:      rule.Advice,
+			severity:    rule.Severity,
+			tags:        rule.Tags,
+			references:  rule.References,
+			source:      fmt.Sprintf("rules[%d] of pack %s", i, pack.Ref()),
+			engine:      rule.Engine,
+		}
+		if len(rule.NotPrecededBy) > 0 || len(rule.NotFollowedBy) > 0 {
+			definition.conditions = &ruleConditions{notPrecededBy: rule.NotPrecededBy, notFollowedBy: rule.NotFollowedBy}
+		}
+		rules = append(rules, definition)
+	}
+	return rules
+}
+
+// piiRules detect personal data for teams whose compliance scope covers more
+// than credentials
+var piiRules = []ruleDefinition{
+	{
+		id:          "CREDIT_CARD",
+		name:        "Credit Card Number",
+		pattern:     `\b(?P<secret>(?:4\d{3}|5[1-5]\d{2}|2[2-7]\d{2}|6011|65\d{2})(?:[ -]?\d{4}){3}|3[47]\d{2}[ -]?\d{6}[ -]?\d{5})\b`,
+		description: "Payment card number detected (passes the Luhn check)",
+		advice:      "Remove card data from the repository; use the payment provider's test card numbers in fixtures",
+		validate:    luhnValid,
+	},
+	{
+		id:          "US_SSN",
+		name:        "US Social Security Number",
+		pattern:     `\b(?P<secret>\d{3}-\d{2}-\d{4})\b`,
+		description: "US Social Security Number detected",
+		advice:      "Remove personal data from the repository; use synthetic data in fixtures",
+		validate:    ssnValid,
+	},
+	{
+		id:          "UK_NINO",
+		name:        "UK National Insurance Number",
+		pattern:     `\b(?P<secret>[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z] ?\d{2} ?\d{2} ?\d{2} ?[A-D])\b`,
+		description: "UK National Insurance number detected",
+		advice:      "Remove personal data from the repository; use synthetic data in fixtures",
+	},
+	{
+		id:          "CA_SIN",
+		name:        "Canadian Social Insurance Number",
+		pattern:     `\b(?P<secret>[1-79]\d{2}[- ]\d{3}[- ]\d{3})\b`,
+		description: "Canadian Social Insurance Number detected (passes the Luhn check)",
+		advice:      "Remove personal data from the repository; use synthetic data in fixtures",
+		validate:    luhnValid,
+	},
+	{
+		id:          "EMAIL_PASSWORD",
+		name:        "Email and Password Combo",
+		pattern:     `\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}[:;|](?P<secret>[^\s:;|]{6,})`,
+		description: "Email address followed by a password, as in credential dumps",
+		advice:      "Remove the credential list and treat the accounts as compromised; reset their passwords",
+		validate:    comboPasswordValid,
+	},
+}
+
+// npmRules detect npm registry tokens, which can publish packages under the
+// owner's name
+var npmRules = []ruleDefinition{
+	{
+		id:          "NPM_TOKEN",
+		name:        "npm Access Token",
+		pattern:     `\b(?P<secret>npm_[A-Za-z0-9]{36})\b`,
+		description: "npm access token detected",
+		advice:      "Use an NPM_TOKEN CI secret and reference it from .npmrc as ${NPM_TOKEN}",
+		remediation: Remediation{
+			RevokeURL:     "https://www.npmjs.com/settings/~/tokens",
+			RotateCommand: "npm token revoke <token-id> && npm token create",
+			DocLinks:      []string{"https://docs.npmjs.com/revoking-access-tokens"},
+		},
+	},
+}
+
+// cicdRules detect CI/CD and automation tokens, which leak through committed
+// pipeline configs and can run jobs or read every secret a pipeline holds.
+// Formats without a prefix are only matched next to a telling key name.
+var cicdRules = []ruleDefinition{
+	{
+		id:          "CIRCLECI_TOKEN",
+		name:        "CircleCI Token",
+		pattern:     `\bCCIP(?:AT|RJ)_[A-Za-z0-9]{22}_[0-9a-f]{40}\b|(?i:circle[\w.-]*token)["']?\s*[:=]\s*["']?(?P<secret>[0-9a-f]{40})\b`,
+		description: "CircleCI personal or project API token detected",
+		advice:      "Store the token as a CircleCI context or project environment variable, not in .circleci/config.yml",
+		remediation: Remediation{
+			RevokeURL: "https://app.circleci.com/settings/user/tokens",
+			DocLinks:  []string{"https://circleci.com/docs/managing-api-tokens/"},
+		},
+	},
+	{
+		id:          "BUILDKITE_AGENT_TOKEN",
+		name:        "Buildkite Agent Token",
+		pattern:     `(?i:buildkite[\w.-]*agent[\w.-]*token["']?\s*[:=]\s*["']?|buildkite-agent\b.*?--token[= ]["']?)(?P<secret>[a-z0-9]{50})\b`,
+		description: "Buildkite agent registration token detected",
+		advice:      "Pass the token to agents through BUILDKITE_AGENT_TOKEN from a secret store, not a committed config or script",
+		remediation: Remediation{
+			DocLinks: []string{"https://buildkite.com/docs/agent/v3/tokens"},
+		},
+	},
+	{
+		id:          "JENKINS_API_TOKEN",
+		name:        "Jenkins API Token",
+		pattern:     `(?:<apiToken>|(?i:jenkins[\w.-]*token)["']?\s*[:=]\s*["']?)(?P<secret>(?:11)?[0-9a-f]{32})\b`,
+		description: "Jenkins API token detected",
+		advice:      "Keep the token in the Jenkins credentials store and bind it with withCredentials instead of job config XML",
+		remediation: Remediation{
+			DocLinks: []string{"https://www.jenkins.io/doc/book/using/remote-access-api/"},
+		},
+	},
+	{
+		id:          "AZURE_DEVOPS_PAT",
+		name:        "Azure DevOps Personal Access Token",
+		pattern:     `\b[A-Za-z0-9]{52}JQQJ9[9DH][A-Za-z0-9]{18}AZDO[A-Za-z0-9]{4}\b|(?i:\b(?:azure|devops|azdo|ado|vsts)[\w.-]*(?:pat|token))["']?\s*[:=]\s*["']?(?P<secret>[a-z2-7]{52})\b`,
+		description: "Azure DevOps personal access token detected",
+		advice:      "Use $(System.AccessToken) or a secret pipeline variable instead of a committed PAT",
+		remediation: Remediation{
+			DocLinks: []string{"https://learn.microsoft.com/en-us/azure/devops/organizations/accounts/use-personal-access-tokens-to-authenticate"},
+		},
+	},
+	{
+		id:          "CODECOV_TOKEN",
+		name:        "Codecov Upload Token",
+		pattern:     `(?i:codecov[\w.-]*token["']?\s*[:=]\s*["']?|codecov\b.*?\s(?:-t|--token)[= ]["']?)(?P<secret>[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12})\b`,
+		description: "Codecov upload token detected",
+		advice:      "Store the token as a CODECOV_TOKEN CI secret and reference it from the pipeline",
+		remediation: Remediation{
+			DocLinks: []string{"https://docs.codecov.com/docs/codecov-tokens"},
+		},
+	},
+}
+
+// observabilityRules detect keys of monitoring and error-tracking services,
+// which are often pasted into app configs because SDKs need them at runtime
+var observabilityRules = []ruleDefinition{
+	{
+		id:          "SENTRY_DSN_SECRET",
+		name:        "Sentry DSN with Secret Key",
+		pattern:     `\bhttps?://[0-9a-f]{32}:(?P<secret>[0-9a-f]{32})@[\w.-]+(?::\d+)?/\d+`,
+		description: "Sentry DSN with a secret key detected",
+		advice:      "Use the public DSN without the secret key; modern SDKs don't need it",
+		remediation: Remediation{
+			DocLinks: []string{"https://docs.sentry.io/concepts/key-terms/dsn-explainer/"},
+		},
+	},
+	{
+		id:          "ROLLBAR_ACCESS_TOKEN",
+		name:        "Rollbar Access Token",
+		pattern:     `(?i:rollbar[\w.-]*(?:token|key))["']?\s*[:=]\s*["']?(?P<secret>[0-9a-f]{32})\b`,
+		description: "Rollbar access token detected",
+		advice:      "Load server-side Rollbar tokens from the environment; only post_client_item tokens belong in client code",
+		remediation: Remediation{
+			DocLinks: []string{"https://docs.rollbar.com/docs/access-tokens"},
+		},
+	},
+	{
+		id:          "HONEYCOMB_API_KEY",
+		name:        "Honeycomb API Key",
+		pattern:     `\bhc[a-z]ik_[a-z0-9]{58}\b|(?i:honeycomb[\w.-]*(?:key|token)|x-honeycomb-team)["']?\s*[:=]\s*["']?(?P<secret>[0-9a-f]{32}|[A-Za-z0-9]{22})\b`,
+		description: "Honeycomb API key detected",
+		advice:      "Pass the key to the OpenTelemetry exporter through OTEL_EXPORTER_OTLP_HEADERS set from a secret",
+		remediation: Remediation{
+			DocLinks: []string{"https://docs.honeycomb.io/configure/environments/manage-api-keys/"},
+		},
+	},
+	{
+		id:          "GRAFANA_CLOUD_API_KEY",
+		name:        "Grafana Cloud API Key",
+		pattern:     `\b(?P<secret>glc_[A-Za-z0-9+/]{32,400}={0,2})`,
+		description: "Grafana Cloud API key or access policy token detected",
+		advice:      "Store the token in a secret and reference it from the agent or Alloy config",
+		remediation: Remediation{
+			DocLinks: []string{"https://grafana.com/docs/grafana-cloud/account-management/authentication-and-permissions/access-policies/"},
+		},
+	},
+	{
+		id:          "PAGERDUTY_API_TOKEN",
+		name:        "PagerDuty API Token",
+		pattern:     `(?i:pagerduty[\w.-]*(?:token|key)["']?\s*[:=]\s*["']?|token\s+token=)(?P<secret>[A-Za-z0-9_+-]{20})(?:[^A-Za-z0-9_+-]|$)`,
+		description: "PagerDuty REST API token detected",
+		advice:      "Keep the token in a secret store; prefer a read-only or scoped OAuth token",
+		remediation: Remediation{
+			DocLinks: []string{"https://support.pagerduty.com/main/docs/api-access-keys"},
+		},
+	},
+}
+
+// storageRules detect object storage and CDN credentials, which can read,
+// replace or purge every asset a product serves
+var storageRules = []ruleDefinition{
+	{
+		id:          "BACKBLAZE_B2_KEY",
+		name:        "Backblaze B2 Application Key",
+		pattern:     `(?i:(?:b2|backblaze)[\w.-]*key)["']?\s*[:=]\s*["']?(?P<secret>K\d{3}[A-Za-z0-9+/]{27}|[0-9a-f]{40})(?:[^A-Za-z0-9+/]|$)`,
+		description: "Backblaze B2 application key detected",
+		advice:      "Load B2_APPLICATION_KEY from a secret store and restrict keys to the buckets they need",
+		remediation: Remediation{
+			RevokeURL: "https://secure.backblaze.com/app_keys.htm",
+			DocLinks:  []string{"https://www.backblaze.com/docs/cloud-storage-application-keys"},
+		},
+	},
+	{
+		id:          "WASABI_SECRET_KEY",
+		name:        "Wasabi Secret Access Key",
+		pattern:     `(?i:wasabi[\w.-]*secret[\w.-]*)["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9+/]{40})(?:[^A-Za-z0-9+/]|$)`,
+		description: "Wasabi secret access key detected",
+		advice:      "Keep Wasabi keys in the S3 credentials file or a secret store, not in application config",
+	},
+	{
+		id:          "CLOUDINARY_API_SECRET",
+		name:        "Cloudinary API Secret",
+		pattern:     `(?:\bcloudinary://\d+:|(?i:cloudinary[\w.-]*secret)["']?\s*[:=]\s*["']?)(?P<secret>[A-Za-z0-9_-]{27})\b`,
+		description: "Cloudinary API secret detected",
+		advice:      "Set CLOUDINARY_URL from a secret at runtime; client-side uploads should use signed or unsigned upload presets",
+		remediation: Remediation{
+			RevokeURL: "https://console.cloudinary.com/settings/api-keys",
+			DocLinks:  []string{"https://cloudinary.com/documentation/how_to_integrate_cloudinary"},
+		},
+	},
+	{
+		id:          "FASTLY_API_TOKEN",
+		name:        "Fastly API Token",
+		pattern:     `(?i:fastly[\w.-]*(?:token|key))["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9_-]{32})(?:[^A-Za-z0-9_-]|$)`,
+		description: "Fastly API token detected",
+		advice:      "Store the token as a CI secret (FASTLY_API_TOKEN) and scope it to the services it manages",
+		remediation: Remediation{
+			RevokeURL: "https://manage.fastly.com/account/personal/tokens",
+			DocLinks:  []string{"https://docs.fastly.com/en/guides/using-api-tokens"},
+		},
+	},
+	{
+		id:          "AKAMAI_CLIENT_SECRET",
+		name:        "Akamai EdgeGrid Client Secret",
+		pattern:     `(?i:(?:akamai[\w.-]*|\b)client[_.-]?secret)["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9+/]{43}=)`,
+		description: "Akamai EdgeGrid client secret detected",
+		advice:      "Keep EdgeGrid credentials in ~/.edgerc outside the repository, or in a secret store for CI",
+		remediation: Remediation{
+			DocLinks: []string{"https://techdocs.akamai.com/developer/docs/set-up-authentication-credentials"},
+		},
+	},
+}
+
+// luhnValid checks the Luhn checksum of the digits in a number
+func luhnValid(number string) bool {
+	sum, count := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if count%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		count++
+	}
+	return count > 0 && sum%10 == 0
+}
+
+// ssnValid rejects numbers the SSA never issues (area 000, 666 or 9xx,
+// group 00, serial 0000)
+func ssnValid(ssn string) bool {
+	parts := strings.Split(ssn, "-")
+	if len(parts) != 3 {
+		return false
+	}
+	area, group, serial := parts[0], parts[1], parts[2]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// comboPasswordValid skips ports (user@host:8080) and scp-style paths
+// (git@github.com:org/repo.git)
+func comboPasswordValid(password string) bool {
+	return strings.Trim(password, "0123456789") != "" && !strings.Contains(password, "/")
+}
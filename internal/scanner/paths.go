@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Findings carry paths relative to the repository root, which is what
+// baselines, .secretignore and the findings database match against.
+// pathRoot is only set by --absolute-paths or settings.absolute_paths and
+// only changes how those paths are shown and reported.
+var (
+	pathMu   sync.RWMutex
+	pathRoot string
+)
+
+// ShowAbsolutePaths makes DisplayPath show repository-relative paths under root
+func ShowAbsolutePaths(root string) {
+	pathMu.Lock()
+	defer pathMu.Unlock()
+	pathRoot = root
+}
+
+// DisplayPath returns a repository-relative path, or "path:line", as output
+// shows it: unchanged, or under the repository root with --absolute-paths.
+// Files of notes commits, labeled with their ref, are left alone.
+func DisplayPath(path string) string {
+	pathMu.RLock()
+	root := pathRoot
+	pathMu.RUnlock()
+	if root == "" || path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "refs/") {
+		return path
+	}
+	return filepath.Join(root, filepath.FromSlash(path))
+}
+
+// Path returns the finding's file as output shows it. Findings of
+// multi-repo scans are labeled with their repository instead.
+func (f *Finding) Path() string {
+	if f.Repo != "" {
+		return f.FilePath
+	}
+	return DisplayPath(f.FilePath)
+}
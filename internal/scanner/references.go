@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+
+	"secretlint/internal/structured"
+)
+
+// LiteralSecretRule is reported in reference mode for credential-looking
+// names assigned a literal in source files, where a secret reference should
+// be used instead
+const LiteralSecretRule = "LITERAL_SECRET"
+
+// referenceAdvice shows the approved reference syntaxes
+const referenceAdvice = "Reference the secret instead of writing it down, e.g. op://<vault>/<item>/<field> (1Password) or vault:<path>#<key> (Vault)"
+
+// secretReferences are the syntaxes of secret manager references: 1Password
+// (op://), Vault (vault:, and vals' ref+vault://) and AWS Secrets Manager
+var secretReferences = []string{"op://", "vault:", "ref+vault://", "arn:aws:secretsmanager"}
+
+// literalAssignmentRegex matches a name assigned a quoted value, as in
+// code (password = "...", apiKey: '...', TOKEN := "..."), or an unquoted
+// value in shell scripts (export DB_PASSWORD=...)
+var literalAssignmentRegex = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_.\-]*)["']?\s*(?::=|=>|=|:)\s*["']([^"'\n]*)["']|^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)=([^\s"'#;]+)`)
+
+// IsSecretReference reports whether a value points to a secret manager
+// (op://, vault:, ...) rather than holding the secret, the approved pattern
+func IsSecretReference(value string) bool {
+	for _, reference := range secretReferences {
+		if strings.Contains(value, reference) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanLiteral reports credential-looking names assigned a literal on a line
+// no other rule flagged. Structured files are left to SensitiveKeyRule.
+func (s *SecretScanner) scanLiteral(line DiffLine) []Finding {
+	if !s.references || structured.Supported(line.FilePath) {
+		return nil
+	}
+	suppressed := IsSuppressed(line.Content, LiteralSecretRule)
+	if suppressed && !s.policy.IsMandatory(LiteralSecretRule) {
+		return nil
+	}
+
+	var findings []Finding
+	for _, match := range literalAssignmentRegex.FindAllStringSubmatchIndex(line.Content, -1) {
+		name, value := 2, 4
+		if match[2] < 0 {
+			name, value = 6, 8
+		}
+		key := line.Content[match[name]:match[name+1]]
+		literal := line.Content[match[value]:match[value+1]]
+		if !IsSensitiveKey(key) || !LooksLikeLiteralCredential(literal) || s.tooShort(LiteralSecretRule, literal) {
+			continue
+		}
+
+		description := key + " is set to a literal instead of a secret reference"
+		if suppressed {
+			description = s.policyViolation(LiteralSecretRule, description)
+		}
+		finding := Finding{
+			RuleID:      LiteralSecretRule,
+			RuleName:    "Literal Secret",
+			FilePath:    line.FilePath,
+			LineNum:     line.LineNum,
+			Content:     line.Content,
+			Match:       literal,
+			Secret:      literal,
+			StartPos:    match[value],
+			EndPos:      match[value+1],
+			Description: description,
+			Advice:      s.advice(LiteralSecretRule, referenceAdvice),
+			Remediation: s.remediation(LiteralSecretRule, Remediation{}),
+			Severity:    s.severity(LiteralSecretRule, SeverityWarning),
+			masking:     s.masking,
+			Commit:      line.Commit,
+
+			PolicyViolation: suppressed,
+		}
+		if !s.baseline.Contains(finding) && !s.allowed(finding) && s.checkEncryption(&finding) {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"secretlint/internal/config"
+	"secretlint/internal/regexcache"
+)
+
+// bodyPlaceholder marks where a rule's pattern holds its token body
+const bodyPlaceholder = "{body}"
+
+// tokenBody is the variable part of a token, such as the 24 characters after
+// sk_live_: a character class and length bounds, max 0 meaning unbounded
+type tokenBody struct {
+	charset  string
+	min, max int
+}
+
+// expand puts the body's regex into pattern; patterns of rules without a
+// body are returned as they are
+func (b *tokenBody) expand(pattern string) string {
+	if b == nil {
+		return pattern
+	}
+	var repeat string
+	switch {
+	case b.max == b.min:
+		repeat = fmt.Sprintf("{%d}", b.min)
+	case b.max == 0:
+		repeat = fmt.Sprintf("{%d,}", b.min)
+	default:
+		repeat = fmt.Sprintf("{%d,%d}", b.min, b.max)
+	}
+	return strings.Replace(pattern, bodyPlaceholder, "["+b.charset+"]"+repeat, 1)
+}
+
+// with returns the body with the options' non-zero fields applied. Raising
+// min_length above a fixed length lifts the upper bound with it.
+func (b tokenBody) with(options config.RuleOptions) *tokenBody {
+	if options.Charset != "" {
+		b.charset = options.Charset
+	}
+	if options.MinLength > 0 {
+		if b.max != 0 && options.MinLength > b.max {
+			b.max = 0
+		}
+		b.min = options.MinLength
+	}
+	if options.MaxLength > 0 {
+		b.max = options.MaxLength
+	}
+	return &b
+}
+
+// applyRuleOptions recompiles the rules whose token length or charset the
+// config's rule_options tune, warning about options it can't apply. Rules
+// the policy makes mandatory keep their built-in pattern.
+func (s *SecretScanner) applyRuleOptions(options map[string]config.RuleOptions) {
+	ids := make([]string, 0, len(options))
+	for id := range options {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if s.policy.IsMandatory(id) {
+			fmt.Fprintf(os.Stderr, "Warning: rule_options.%s is ignored, the rule is mandatory under the policy from %s\n", id, s.policy.Source)
+			continue
+		}
+		index := -1
+		for i := range s.rules {
+			if s.rules[i].ID == id {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			fmt.Fprintf(os.Stderr, "Warning: rule_options names unknown rule %q\n", id)
+			continue
+		}
+		rule := &s.rules[index]
+		if rule.body == nil {
+			fmt.Fprintf(os.Stderr, "Warning: rule_options.%s: the rule has no token length or charset to tune\n", id)
+			continue
+		}
+		body := rule.body.with(options[id])
+		compiled, err := regexcache.Compile(body.expand(rule.template))
+		if err != nil {
+			s.ruleErrors = append(s.ruleErrors, fmt.Errorf("rule_options.%s in %s is ignored, the tuned pattern doesn't compile: %w", id, config.DefaultConfigFile, err))
+			continue
+		}
+		rule.Pattern = compiled
+		rule.body = body
+	}
+}
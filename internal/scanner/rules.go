@@ -2,17 +2,49 @@ package scanner
 
 import (
 	"fmt"
-	"regexp"
-	"strings"
+	"os"
+	"sort"
+
+	"secretlint/internal/config"
 )
 
 // SecretRule represents a regex-based rule for detecting secrets
 type SecretRule struct {
 	ID          string
 	Name        string
-	Pattern     *regexp.Regexp
+	Pattern     Pattern
 	Description string
 	Advice      string
+	Remediation Remediation
+	Severity    string
+	
+	// Validate, if set, rejects matches that fail a checksum or similar test
+	Validate func(secret string) bool
+	
+	// Tags and References come from rule packs, for 'secretlint rules list'
+	Tags       []string
+	References []string
+	
+	// template and body let rule_options recompile Pattern with another
+	// token length or charset
+	template string
+	body     *tokenBody
+	
+	// conditions narrow the matches of custom and pack rules
+	conditions *ruleConditions
+}
+
+// Severities: errors block commits and fail scans, warnings are only reported
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Remediation holds structured guidance for revoking and rotating a leaked secret
+type Remediation struct {
+	RevokeURL     string
+	RotateCommand string
+	DocLinks      []string
 }
 
 // Finding represents a detected secret
@@ -23,90 +55,248 @@ type Finding struct {
 	LineNum     int
 	Content     string
 	Match       string
+	Secret      string
 	StartPos    int
 	EndPos      int
 	Description string
 	Advice      string
+	Remediation Remediation
+	Severity    string
+	Commit      *CommitInfo
+	
+	// Repo names the repository when one invocation scans several
+	Repo string
+	
+	// KeyPath locates the value in structured config files, e.g. "database.password"
+	KeyPath string
+	
+	// Cell locates the finding in a Jupyter notebook, e.g. "cell 3, line 2"
+	Cell string
+	
+	// PolicyViolation is set when an inline suppression of a mandatory rule was ignored
+	PolicyViolation bool
+	
+	// Owners come from CODEOWNERS; LastTouchedBy from git blame
+	Owners        []string
+	LastTouchedBy *CommitInfo
+	
+	// Metadata is the File metadata of findings from a FileSource
+	Metadata map[string]string
+	
+	// masking is the settings.masking of the scanner that reported it
+	masking config.MaskingSettings
 }
 
-// SecretScanner handles secret detection using regex rules
+// SecretScanner handles secret detection using regex rules. Once created, a
+// scanner is safe for concurrent use: the Scan methods only read its rules,
+// ignore patterns, baseline and allowlist, and the encryption cache is
+// locked. Changing it (GetBaseline().Add, GetIgnoreChecker().AddPattern)
+// must not overlap with scans.
 type SecretScanner struct {
 	rules         []SecretRule
 	ignoreChecker *IgnoreChecker
+	baseline      *Baseline
+	
+	// disabled holds rule IDs turned off in the config; policy protects rules
+	// from being disabled or suppressed
+	disabled map[string]bool
+	policy   config.Policy
+	
+	// failOn is the config's fail_on threshold
+	failOn string
+	
+	// minLength is settings.min_length: shorter values aren't reported
+	minLength int
+
+	// reportOnly holds the rules the applied profile only reports
+	reportOnly map[string]bool
+	
+	// allowlist drops known false positives
+	allowlist []allowlistEntry
+	
+	// encrypted knows which files git-crypt and sops encrypt
+	encrypted encryptedFiles
+	
+	// references enables LiteralSecretRule (settings.references)
+	references bool
+	
+	// lfs sets whether the objects behind Git LFS pointers are scanned
+	lfs config.LFSSettings
+	
+	// masking is settings.masking, which the scanner's findings mask with
+	masking config.MaskingSettings
+	
+	// ruleErrors are the rules and packs that failed to load, which leave
+	// gaps in coverage; they are warnings, or errors under --strict-config
+	ruleErrors []error
 }
 
-// NewSecretScanner creates a new SecretScanner with default rules
-func NewSecretScanner() *SecretScanner {
-	scanner := &SecretScanner{
-		ignoreChecker: NewIgnoreChecker(),
+// NewSecretScanner creates a new SecretScanner with default rules. It fails
+// when .secretlintrc.yml can't be loaded, rather than scanning without the
+// config's rules and policy.
+func NewSecretScanner() (*SecretScanner, error) {
+	scanner, err := NewRuleScanner()
+	if err != nil {
+		return nil, err
 	}
-	scanner.loadDefaultRules()
 	
 	// Try to load .secretignore file
 	if err := scanner.ignoreChecker.LoadIgnoreFile(".secretignore"); err != nil {
 		// Non-fatal error, just continue without ignore patterns
 	}
 	
-	return scanner
+	// Try to load previously accepted findings
+	if baseline, err := LoadBaseline(DefaultBaselineFile); err == nil {
+		scanner.baseline = baseline
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	
+	return scanner, nil
+}
+
+// NewRuleScanner creates a scanner with the rules .secretlintrc.yml
+// enables but without .secretignore patterns or the baseline, to measure
+// the rules themselves
+func NewRuleScanner() (*SecretScanner, error) {
+	scanner := &SecretScanner{
+		ignoreChecker: NewIgnoreChecker(),
+		baseline:      NewBaseline(),
+	}
+	scanner.loadDefaultRules()
+	
+	// Rule packs, disabled rules and org policy come from .secretlintrc.yml
+	cfg, err := config.Load(config.DefaultConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	scanner.configure(cfg)
+	for _, err := range scanner.ruleErrors {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return scanner, nil
+}
+
+// ruleDefinition is the uncompiled form of a rule
+type ruleDefinition struct {
+	id          string
+	name        string
+	pattern     string
+	description string
+	advice      string
+	remediation Remediation
+	validate    func(secret string) bool
+	
+	// body is the token part that rule_options can tune; pattern holds it
+	// as {body}
+	body *tokenBody
+	
+	// conditions are the extra signals a rule requires, such as the
+	// lookarounds its pattern can't express
+	conditions *ruleConditions
+	
+	// source says where a pack or config rule is defined, for load errors
+	source string
+	
+	// engine is regexcache.EngineRegexp2 for rules that need lookarounds or
+	// backreferences; other rules use Go's regexp
+	engine string
+	
+	// severity defaults to SeverityError
+	severity string
+	
+	tags       []string
+	references []string
 }
 
 // loadDefaultRules loads the curated regex patterns from the specification
 func (s *SecretScanner) loadDefaultRules() {
-	rules := []struct {
-		id          string
-		name        string
-		pattern     string
-		description string
-		advice      string
-	}{
+	rules := []ruleDefinition{
 		{
 			id:          "OPENAI_API_KEY",
 			name:        "OpenAI API Key",
-			pattern:     `sk-[A-Za-z0-9]{20,}`,
+			pattern:     `sk-{body}`,
+			body:        &tokenBody{charset: "A-Za-z0-9", min: 20},
 			description: "OpenAI API key detected",
 			advice:      "Move this to an environment variable (.env file) and add .env to .gitignore",
+			remediation: Remediation{
+				RevokeURL: "https://platform.openai.com/api-keys",
+				DocLinks:  []string{"https://platform.openai.com/docs/api-reference/authentication"},
+			},
 		},
 		{
 			id:          "GITHUB_PAT",
 			name:        "GitHub Personal Access Token",
-			pattern:     `ghp_[A-Za-z0-9]{36}`,
+			pattern:     `ghp_{body}`,
+			body:        &tokenBody{charset: "A-Za-z0-9", min: 36, max: 36},
 			description: "GitHub Personal Access Token detected",
 			advice:      "Store in environment variables or GitHub Secrets for CI/CD",
+			remediation: Remediation{
+				RevokeURL: "https://github.com/settings/tokens",
+				DocLinks:  []string{"https://docs.github.com/en/authentication/keeping-your-account-and-data-secure/managing-your-personal-access-tokens"},
+			},
 		},
 		{
 			id:          "AWS_ACCESS_KEY",
 			name:        "AWS Access Key ID",
-			pattern:     `(AKIA|ASIA)[A-Z0-9]{16}`,
+			pattern:     `(AKIA|ASIA){body}`,
+			body:        &tokenBody{charset: "A-Z0-9", min: 16, max: 16},
 			description: "AWS Access Key ID detected",
 			advice:      "Use AWS IAM roles or store in AWS credentials file/environment variables",
+			remediation: Remediation{
+				RevokeURL:     "https://console.aws.amazon.com/iam/home#/security_credentials",
+				RotateCommand: "aws iam create-access-key --user-name <user> && aws iam update-access-key --access-key-id <leaked-key-id> --status Inactive --user-name <user>",
+				DocLinks:      []string{"https://docs.aws.amazon.com/IAM/latest/UserGuide/id_credentials_access-keys.html#rotating_access_keys_console"},
+			},
 		},
 		{
 			id:          "AWS_SECRET_KEY",
 			name:        "AWS Secret Access Key",
-			pattern:     `(?i)aws(.{0,20})?(secret|access).{0,20}['\"][A-Za-z0-9/+=]{40}['\"]`,
+			pattern:     `(?i)aws(.{0,20})?(secret|access).{0,20}['\"](?P<secret>{body})['\"]`,
+			body:        &tokenBody{charset: "A-Za-z0-9/+=", min: 40, max: 40},
 			description: "AWS Secret Access Key detected",
 			advice:      "Use AWS IAM roles or store in AWS credentials file/environment variables",
+			remediation: Remediation{
+				RevokeURL:     "https://console.aws.amazon.com/iam/home#/security_credentials",
+				RotateCommand: "aws iam create-access-key --user-name <user> && aws iam update-access-key --access-key-id <leaked-key-id> --status Inactive --user-name <user>",
+				DocLinks:      []string{"https://docs.aws.amazon.com/IAM/latest/UserGuide/id_credentials_access-keys.html#rotating_access_keys_console"},
+			},
 		},
 		{
 			id:          "STRIPE_LIVE_PK",
 			name:        "Stripe Live Publishable Key",
-			pattern:     `pk_live_[A-Za-z0-9]{24}`,
+			pattern:     `pk_live_{body}`,
+			body:        &tokenBody{charset: "A-Za-z0-9", min: 24, max: 24},
 			description: "Stripe Live Publishable Key detected",
 			advice:      "Move to environment variables and ensure it's not exposed in client-side code",
+			remediation: Remediation{
+				RevokeURL: "https://dashboard.stripe.com/apikeys",
+				DocLinks:  []string{"https://docs.stripe.com/keys#rolling-keys"},
+			},
 		},
 		{
 			id:          "STRIPE_LIVE_SK",
 			name:        "Stripe Live Secret Key", 
-			pattern:     `sk_live_[A-Za-z0-9]{24}`,
+			pattern:     `sk_live_{body}`,
+			body:        &tokenBody{charset: "A-Za-z0-9", min: 24, max: 24},
 			description: "Stripe Live Secret Key detected",
 			advice:      "Move to environment variables and never expose in client-side code",
+			remediation: Remediation{
+				RevokeURL: "https://dashboard.stripe.com/apikeys",
+				DocLinks:  []string{"https://docs.stripe.com/keys#rolling-keys"},
+			},
 		},
 		{
 			id:          "SLACK_TOKEN",
 			name:        "Slack Token",
-			pattern:     `xox[baprs]-[0-9A-Za-z\-]+`,
+			pattern:     `xox[baprs]-{body}`,
+			body:        &tokenBody{charset: `0-9A-Za-z\-`, min: 1},
 			description: "Slack API token detected",
 			advice:      "Store in environment variables or secure configuration management",
+			remediation: Remediation{
+				RevokeURL: "https://api.slack.com/apps",
+				DocLinks:  []string{"https://api.slack.com/authentication/rotation"},
+			},
 		},
 		{
 			id:          "JWT_TOKEN",
@@ -114,11 +304,15 @@ func (s *SecretScanner) loadDefaultRules() {
 			pattern:     `eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9._\-]+\.[A-Za-z0-9._\-]+`,
 			description: "JWT token detected",
 			advice:      "Avoid committing JWTs; use secure token storage and short expiration times",
+			remediation: Remediation{
+				DocLinks: []string{"https://datatracker.ietf.org/doc/html/rfc8725"},
+			},
 		},
 		{
 			id:          "GENERIC_API_KEY",
 			name:        "Generic API Key Pattern",
-			pattern:     `(?i)(api[_\-]?key|apikey|secret[_\-]?key|secretkey|access[_\-]?token|accesstoken)\s*[=:]\s*['\"]?[A-Za-z0-9\+/]{32,}['\"]?`,
+			pattern:     `(?i)(api[_\-]?key|apikey|secret[_\-]?key|secretkey|access[_\-]?token|accesstoken)\s*[=:]\s*['\"]?(?P<secret>{body})['\"]?`,
+			body:        &tokenBody{charset: `A-Za-z0-9\+/`, min: 32},
 			description: "Generic API key pattern detected",
 			advice:      "Move sensitive keys to environment variables or secure configuration",
 		},
@@ -128,15 +322,211 @@ func (s *SecretScanner) loadDefaultRules() {
 			pattern:     `-----BEGIN\s+(RSA\s+)?PRIVATE\s+KEY-----`,
 			description: "Private key detected",
 			advice:      "Store private keys securely, never commit to version control",
+			remediation: Remediation{
+				RotateCommand: "ssh-keygen -t ed25519 -f <new-key-file>",
+				DocLinks:      []string{"https://docs.github.com/en/authentication/connecting-to-github-with-ssh/generating-a-new-ssh-key-and-adding-it-to-the-ssh-agent"},
+			},
+		},
+		{
+			id:   "OPENSSH_PRIVATE_KEY",
+			name: "OpenSSH Private Key",
+			// The base64 body of every OpenSSH key starts with "openssh-key-v1",
+			// so keys assigned without their header are caught too; the first
+			// body line of a whole key isn't reported again
+			pattern:     `-----BEGIN\s+OPENSSH\s+PRIVATE\s+KEY-----|[=:"']\s*(?P<secret>b3BlbnNzaC1rZXktdjE[A-Za-z0-9+/]{16,})`,
+			description: "OpenSSH private key detected (ssh-ed25519, ECDSA or RSA)",
+			advice:      "Keep SSH keys in ~/.ssh or an SSH agent; use deploy keys or CI secrets for automation",
+			remediation: Remediation{
+				RotateCommand: "ssh-keygen -t ed25519 -f <new-key-file>",
+				DocLinks:      []string{"https://docs.github.com/en/authentication/connecting-to-github-with-ssh/generating-a-new-ssh-key-and-adding-it-to-the-ssh-agent"},
+			},
+		},
+		{
+			id:          "PGP_PRIVATE_KEY",
+			name:        "PGP Private Key Block",
+			pattern:     `-----BEGIN\s+PGP\s+PRIVATE\s+KEY\s+BLOCK-----`,
+			description: "Exported GPG private key detected",
+			advice:      "Keep GPG secret keys in the keyring; give CI a dedicated signing subkey through its secret store",
+			remediation: Remediation{
+				RotateCommand: "gpg --gen-revoke <key-id> && gpg --full-generate-key",
+				DocLinks:      []string{"https://www.gnupg.org/gph/en/manual/c14.html"},
+			},
+		},
+		{
+			id:          "AGE_SECRET_KEY",
+			name:        "age Secret Key",
+			pattern:     `\bAGE-SECRET-KEY-1[QPZRY9X8GF2TVDW0S3JN54KHCE6MUA7L]{58}\b`,
+			description: "age secret key detected, which decrypts every file (and sops secret) encrypted to it",
+			advice:      "Keep age identities in ~/.config/sops/age/keys.txt or SOPS_AGE_KEY in CI, never next to the files they decrypt",
+			remediation: Remediation{
+				RotateCommand: "age-keygen -o keys.txt && sops updatekeys <file>",
+				DocLinks:      []string{"https://github.com/FiloSottile/age", "https://github.com/getsops/sops#encrypting-using-age"},
+			},
 		},
+		// Credential file formats, wherever their lines end up: CI scripts
+		// writing ~/.netrc, Dockerfiles echoing an .npmrc, docs, fixtures
+		{
+			id:          "NETRC_CREDENTIAL",
+			name:        "netrc Credentials",
+			pattern:     `\b(?:machine\s+\S+|default)\s+(?:login\s+\S+\s+)?(?:account\s+\S+\s+)?password\s+(?P<secret>[^\s"'\\]+)`,
+			description: "netrc machine/login/password entry detected",
+			advice:      "Write ~/.netrc at runtime from a CI secret, or use a git credential helper",
+			validate:    LooksLikeLiteralCredential,
+		},
+		{
+			id:          "NPMRC_AUTH_TOKEN",
+			name:        "npmrc Auth Token",
+			pattern:     `(?:^|[\s"'/:])_(?:authToken|auth|password)\s*=\s*["']?(?P<secret>[^\s"']+)`,
+			description: "npm registry credential (_authToken, _auth or _password) detected",
+			advice:      "Reference the token from .npmrc as ${NPM_TOKEN} and keep the value in a CI secret",
+			validate:    LooksLikeLiteralCredential,
+			remediation: Remediation{
+				RevokeURL: "https://www.npmjs.com/settings/~/tokens",
+				DocLinks:  []string{"https://docs.npmjs.com/using-private-packages-in-a-ci-cd-workflow"},
+			},
+		},
+		{
+			id:          "PYPI_TOKEN",
+			name:        "PyPI API Token",
+			pattern:     `\bpypi-AgE(?:IcHlwaS5vcmc|NdGVzdC5weXBpLm9yZw)[A-Za-z0-9_-]{50,}`,
+			description: "PyPI or TestPyPI upload token detected, as kept in .pypirc",
+			advice:      "Use trusted publishing from CI, or a TWINE_PASSWORD secret scoped to one project",
+			remediation: Remediation{
+				RevokeURL: "https://pypi.org/manage/account/token/",
+				DocLinks:  []string{"https://docs.pypi.org/trusted-publishers/"},
+			},
+		},
+		{
+			id:          "PGPASS_CREDENTIAL",
+			name:        "pgpass Entry",
+			pattern:     `^\s*(?:\*|\d{1,3}(?:\.\d{1,3}){3}|[A-Za-z0-9._-]*[A-Za-z][A-Za-z0-9._-]*):(?:\d{1,5}|\*):[^:\s]+:[^:\s]+:(?P<secret>(?:[^:\s\\]|\\.)+)\s*$`,
+			description: "PostgreSQL pgpass line (host:port:database:user:password) detected",
+			advice:      "Keep .pgpass in the home directory with mode 0600, or pass PGPASSWORD from a secret",
+			validate:    LooksLikeLiteralCredential,
+		},
+	}
+
+	s.addRules(rules)
+}
+
+// CheckRules loads the rules of cfg like a scan would and returns the rules
+// and packs that failed to load, for --strict-config
+func CheckRules(cfg *config.Config) []error {
+	s := &SecretScanner{}
+	s.loadDefaultRules()
+	s.configure(cfg)
+	return s.ruleErrors
+}
+
+// configure applies rule packs, disabled rules and the org policy
+func (s *SecretScanner) configure(cfg *config.Config) {
+	s.enablePacks(cfg.Packs)
+	s.addRules(customRules(cfg.CustomRules))
+	s.policy = cfg.Policy
+	s.applyRuleOptions(cfg.RuleOptions)
+	s.failOn = cfg.FailOn
+	s.minLength = cfg.Settings.MinLength
+	s.reportOnly = make(map[string]bool)
+	for _, ruleID := range cfg.ReportOnly {
+		s.reportOnly[ruleID] = true
+	}
+	s.allowlist = compileAllowlist(cfg.Allowlist)
+	s.masking = cfg.Settings.Masking
+	s.disabled = make(map[string]bool)
+	
+	var enabled []SecretRule
+	for _, rule := range s.rules {
+		if cfg.RuleEnabled(rule.ID) {
+			enabled = append(enabled, rule)
+		}
+	}
+	s.rules = enabled
+	for _, ruleID := range []string{SensitiveKeyRule, SensitiveFileRule} {
+		if !cfg.RuleEnabled(ruleID) {
+			s.disabled[ruleID] = true
+		}
+	}
+	s.references = cfg.Settings.References.Enabled && cfg.RuleEnabled(LiteralSecretRule)
+	s.lfs = cfg.Settings.LFS
+}
+
+// severity applies the policy's override, the profile's report_only rules
+// and the fail_on threshold to a rule's severity. Mandatory rules keep
+// blocking even when report_only or fail_on say they shouldn't.
+func (s *SecretScanner) severity(ruleID, severity string) string {
+	if override := s.policy.RuleOverrides[ruleID].Severity; override != "" {
+		severity = override
+	}
+	switch {
+	case s.reportOnly[ruleID] && !s.policy.IsMandatory(ruleID):
+		return SeverityWarning
+	case s.failOn == "warning":
+		return SeverityError
+	case s.failOn == "never" && !s.policy.IsMandatory(ruleID):
+		return SeverityWarning
 	}
+	return severity
+}
+
+// advice returns the policy's advice for a rule, or the rule's own
+func (s *SecretScanner) advice(ruleID, advice string) string {
+	if override := s.policy.RuleOverrides[ruleID].Advice; override != "" {
+		return override
+	}
+	return advice
+}
+
+// remediation lays the policy's remediation links for a rule over the rule's own
+func (s *SecretScanner) remediation(ruleID string, remediation Remediation) Remediation {
+	return overrideRemediation(remediation, s.policy.RuleOverrides[ruleID])
+}
+
+// overrideRemediation replaces the parts of remediation an override sets
+func overrideRemediation(remediation Remediation, override config.RuleOverride) Remediation {
+	if override.RevokeURL != "" {
+		remediation.RevokeURL = override.RevokeURL
+	}
+	if override.RotateCommand != "" {
+		remediation.RotateCommand = override.RotateCommand
+	}
+	if len(override.DocLinks) > 0 {
+		remediation.DocLinks = override.DocLinks
+	}
+	return remediation
+}
+
+// tooShort reports whether a detected value is below settings.min_length.
+// Mandatory rules report values of any length.
+func (s *SecretScanner) tooShort(ruleID, value string) bool {
+	return len(value) < s.minLength && !s.policy.IsMandatory(ruleID)
+}
+
+// policyViolation explains a finding whose suppression the policy overrode
+func (s *SecretScanner) policyViolation(ruleID, description string) string {
+	return fmt.Sprintf("%s (policy violation: inline suppression ignored, %s is mandatory under %s)", description, ruleID, s.policy.Source)
+}
 
+// addRules compiles rule definitions and appends them to the scanner
+func (s *SecretScanner) addRules(rules []ruleDefinition) {
 	for _, rule := range rules {
-		compiled, err := regexp.Compile(rule.pattern)
+		source := rule.source
+		if source == "" {
+			source = "built-in"
+		}
+		// A second rule with the same ID would report each secret twice
+		if s.hasRule(rule.id) {
+			s.ruleErrors = append(s.ruleErrors, fmt.Errorf("rule %s (%s) is skipped, a built-in or pack rule already has this ID", rule.id, source))
+			continue
+		}
+		compiled, err := compilePattern(rule.id, rule.engine, rule.body.expand(rule.pattern))
 		if err != nil {
-			// Skip invalid patterns
+			s.ruleErrors = append(s.ruleErrors, fmt.Errorf("rule %s (%s) is skipped, its pattern doesn't compile: %w", rule.id, source, err))
 			continue
 		}
+		severity := rule.severity
+		if severity == "" {
+			severity = SeverityError
+		}
 		
 		s.rules = append(s.rules, SecretRule{
 			ID:          rule.id,
@@ -144,48 +534,109 @@ func (s *SecretScanner) loadDefaultRules() {
 			Pattern:     compiled,
 			Description: rule.description,
 			Advice:      rule.advice,
+			Remediation: rule.remediation,
+			Severity:    severity,
+			Validate:    rule.validate,
+			Tags:        rule.tags,
+			References:  rule.references,
+			template:    rule.pattern,
+			body:        rule.body,
+			conditions:  rule.conditions,
 		})
 	}
 }
 
+// hasRule reports whether a loaded rule, or a check without a pattern such
+// as SensitiveKeyRule, has the ID
+func (s *SecretScanner) hasRule(id string) bool {
+	switch id {
+	case SensitiveKeyRule, SensitiveFileRule, LiteralSecretRule:
+		return true
+	}
+	for _, rule := range s.rules {
+		if rule.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 // ScanLine scans a single line for secrets using all loaded rules
 func (s *SecretScanner) ScanLine(filePath string, lineNum int, content string) []Finding {
+	return s.scanLine(filePath, lineNum, content, nil)
+}
+
+// scanLine is ScanLine with the lines scanned together with this one, which
+// custom rules search for their keywords
+func (s *SecretScanner) scanLine(filePath string, lineNum int, content string, context *lineContext) []Finding {
 	var findings []Finding
 	
 	for _, rule := range s.rules {
-		matches := rule.Pattern.FindAllStringSubmatch(content, -1)
+		if !rule.conditions.allowsPath(filePath) {
+			continue
+		}
+		matches := rule.Pattern.FindAllStringSubmatchIndex(content, -1)
 		if matches == nil {
 			continue
 		}
+		// Staged scans read the rest of the file from the index
+		if !rule.conditions.keywordNear(filePath, lineNum, content, context, s.encrypted.read) {
+			continue
+		}
 		
-		// Find all match positions
-		indexes := rule.Pattern.FindAllStringIndex(content, -1)
-		
-		for i, match := range matches {
-			var matchText string
-			if len(match) > 0 {
-				matchText = match[0]
+		// Mandatory rules ignore inline suppressions and report them as violations
+		description := rule.Description
+		violation := IsSuppressed(content, rule.ID)
+		if violation {
+			if !s.policy.IsMandatory(rule.ID) {
+				continue
 			}
+			description = s.policyViolation(rule.ID, description)
+		}
+		
+		// Rules may isolate the secret value in a "secret" group so the
+		// surrounding key name is not treated as part of the credential
+		secretGroup := rule.Pattern.SubexpIndex("secret")
+		
+		for _, match := range matches {
+			startPos, endPos := match[0], match[1]
+			matchText := content[startPos:endPos]
 			
-			// Get position info
-			var startPos, endPos int
-			if i < len(indexes) {
-				startPos = indexes[i][0]
-				endPos = indexes[i][1]
+			secretText := matchText
+			if secretGroup > 0 && match[2*secretGroup] >= 0 {
+				secretText = content[match[2*secretGroup]:match[2*secretGroup+1]]
+			}
+			if rule.Validate != nil && !rule.Validate(secretText) {
+				continue
+			}
+			if !rule.conditions.allowsSecret(secretText) || !rule.conditions.allowsContext(content, startPos, endPos) {
+				continue
+			}
+			if inSopsValue(content, startPos, endPos) || s.tooShort(rule.ID, secretText) {
+				continue
 			}
 			
-			findings = append(findings, Finding{
+			finding := Finding{
 				RuleID:      rule.ID,
 				RuleName:    rule.Name,
 				FilePath:    filePath,
 				LineNum:     lineNum,
 				Content:     content,
 				Match:       matchText,
+				Secret:      secretText,
 				StartPos:    startPos,
 				EndPos:      endPos,
-				Description: rule.Description,
-				Advice:      rule.Advice,
-			})
+				Description: description,
+				Advice:      s.advice(rule.ID, rule.Advice),
+				Remediation: s.remediation(rule.ID, rule.Remediation),
+				Severity:    s.severity(rule.ID, rule.Severity),
+				masking:     s.masking,
+				
+				PolicyViolation: violation,
+			}
+			if !s.allowed(finding) {
+				findings = append(findings, finding)
+			}
 		}
 	}
 	
@@ -196,34 +647,148 @@ func (s *SecretScanner) ScanLine(filePath string, lineNum int, content string) [
 func (s *SecretScanner) ScanLines(lines []DiffLine) []Finding {
 	var allFindings []Finding
 	
+	lines = s.expandLFS(lines)
+	contexts := s.lineContexts(lines)
 	for _, line := range lines {
 		// Skip ignored files
 		if s.ignoreChecker.ShouldIgnore(line.FilePath) {
 			continue
 		}
 		
-		findings := s.ScanLine(line.FilePath, line.LineNum, line.Content)
-		allFindings = append(allFindings, findings...)
+		content, truncated := clampLine(stripNUL(normalizeLine(line.Content)))
+		if truncated {
+			fmt.Fprintf(os.Stderr, "⚠️  %s:%d is %d bytes long; only the first %d were scanned\n", line.FilePath, line.LineNum, len(line.Content), MaxLineLength)
+		}
+		line.Content = content
+		
+		found := false
+		for _, finding := range s.scanLineSafely(line, contexts[lineKey(line)]) {
+			found = true
+			if s.baseline.Contains(finding) {
+				continue
+			}
+			finding.Commit = line.Commit
+			if !s.checkEncryption(&finding) {
+				continue
+			}
+			allFindings = append(allFindings, finding)
+		}
+		if !found {
+			allFindings = append(allFindings, s.scanLiteral(line)...)
+		}
 	}
 	
 	return allFindings
 }
 
+// GetBaseline returns the loaded baseline of accepted findings
+func (s *SecretScanner) GetBaseline() *Baseline {
+	return s.baseline
+}
+
+// GetRules returns the loaded detection rules
+func (s *SecretScanner) GetRules() []SecretRule {
+	return s.rules
+}
+
+// IsMandatory reports whether org policy forbids disabling or suppressing a rule
+func (s *SecretScanner) IsMandatory(ruleID string) bool {
+	return s.policy.IsMandatory(ruleID)
+}
+
+// UseIndex makes the scanner read files from the index, as staged scans
+// need: a partially staged file is judged by what will be committed, not
+// by its working copy. Call it before scanning.
+func (s *SecretScanner) UseIndex() {
+	s.encrypted.mu.Lock()
+	defer s.encrypted.mu.Unlock()
+	s.encrypted.index = true
+}
+
 // GetIgnoreChecker returns the ignore checker for external use
 func (s *SecretScanner) GetIgnoreChecker() *IgnoreChecker {
 	return s.ignoreChecker
 }
 
+// Location returns "file:line", prefixed with the repository in multi-repo scans
+func (f *Finding) Location() string {
+	location := f.Path()
+	if f.LineNum > 0 {
+		location = fmt.Sprintf("%s:%d", location, f.LineNum)
+	}
+	if f.Repo != "" {
+		location = f.Repo + ":" + location
+	}
+	return location
+}
+
 // MaskSecret returns a masked version of the secret for safe display
 func (f *Finding) MaskSecret() string {
-	if len(f.Match) <= 8 {
-		return strings.Repeat("*", len(f.Match))
+	// A file name flagged by ScanPaths isn't itself secret
+	if f.RuleID == SensitiveFileRule {
+		return f.Match
 	}
-	
-	// Show first 4 and last 4 characters, mask the middle
-	prefix := f.Match[:4]
-	suffix := f.Match[len(f.Match)-4:]
-	middle := strings.Repeat("*", len(f.Match)-8)
-	
-	return fmt.Sprintf("%s%s%s", prefix, middle, suffix)
-}
\ No newline at end of file
+	return f.MaskValue(f.Match)
+}
+
+// DisplaySecret is MaskSecret for terminal output, where --no-mask shows the
+// secret in full
+func (f *Finding) DisplaySecret() string {
+	if f.RuleID == SensitiveFileRule {
+		return f.Match
+	}
+	return f.DisplayValue(f.Match)
+}
+
+// MaskValue masks a value, such as the finding's Secret, the way the
+// scanner that reported the finding masks secrets
+func (f *Finding) MaskValue(value string) string {
+	return maskValue(value, f.masking)
+}
+
+// DisplayValue is MaskValue for terminal output
+func (f *Finding) DisplayValue(value string) string {
+	return displayValue(value, f.masking)
+}
+
+// Blocking reports whether a finding should fail the scan; findings from
+// before severities existed count as errors
+func (f *Finding) Blocking() bool {
+	return f.Severity != SeverityWarning
+}
+
+// BlockingFindings filters findings down to those that fail the scan
+func BlockingFindings(findings []Finding) []Finding {
+	var blocking []Finding
+	for _, finding := range findings {
+		if finding.Blocking() {
+			blocking = append(blocking, finding)
+		}
+	}
+	return blocking
+}
+
+
+// SortFindings puts findings in a stable order (repository, path, line,
+// column, rule), so output doesn't change between runs however the scan
+// was scheduled
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		switch {
+		case a.Repo != b.Repo:
+			return a.Repo < b.Repo
+		case a.FilePath != b.FilePath:
+			return a.FilePath < b.FilePath
+		case a.LineNum != b.LineNum:
+			return a.LineNum < b.LineNum
+		case a.Cell != b.Cell:
+			return a.Cell < b.Cell
+		case a.StartPos != b.StartPos:
+			return a.StartPos < b.StartPos
+		case a.RuleID != b.RuleID:
+			return a.RuleID < b.RuleID
+		}
+		return a.Secret < b.Secret
+	})
+}
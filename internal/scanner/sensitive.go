@@ -0,0 +1,179 @@
+package scanner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"secretlint/internal/structured"
+)
+
+// SensitiveKeyRule is reported for credential-looking keys that hold a
+// literal value, which token-specific rules miss (e.g. database passwords)
+const SensitiveKeyRule = "HARDCODED_CREDENTIAL"
+
+// sensitiveKeyRegex matches key names that conventionally hold credentials
+var sensitiveKeyRegex = regexp.MustCompile(`(?i)(password|passwd|pwd|secret|token|api[_\-]?key|apikey|auth[_\-]?key|credential|private[_\-]?key|access[_\-]?key)`)
+
+// placeholderValues are obviously fake values that aren't worth reporting
+var placeholderValues = map[string]bool{
+	"true": true, "false": true, "null": true, "nil": true, "none": true,
+	"changeme": true, "example": true, "placeholder": true, "required": true,
+}
+
+// IsSensitiveKey reports whether a key name conventionally holds a credential
+func IsSensitiveKey(key string) bool {
+	return sensitiveKeyRegex.MatchString(key)
+}
+
+// LooksLikeLiteralCredential filters out references, placeholders and short values
+func LooksLikeLiteralCredential(value string) bool {
+	if len(value) < 6 || placeholderValues[strings.ToLower(value)] {
+		return false
+	}
+	// sops-encrypted values (ENC[AES256_GCM,data:...]) are ciphertext
+	if strings.HasPrefix(value, "$") || strings.HasPrefix(value, "<") || strings.HasPrefix(value, "{{") || strings.HasPrefix(value, "ENC[") {
+		return false
+	}
+	// Interpolated values like "postgres://app:${DB_PASSWORD}@db" hold a reference, not the secret
+	if strings.Contains(value, "${") || strings.Trim(value, "0123456789") == "" {
+		return false
+	}
+	if strings.Trim(value, "*xX.") == "" || strings.ContainsAny(value, " \t") {
+		return false
+	}
+	for _, reference := range []string{"process.env", "os.environ", "getenv", "ENV[", "import.meta.env"} {
+		if strings.Contains(value, reference) {
+			return false
+		}
+	}
+	return !IsSecretReference(value)
+}
+
+// ScanStructured scans lines of a recognized config file (YAML, JSON, TOML,
+// INI, dotenv) or notebook. content is the whole file, which gives each line its key path: rule
+// findings are labelled with it, and credential-looking keys holding literal
+// values are reported even when no token rule matches. lines may be a subset
+// of the file, e.g. the lines added by a diff.
+func (s *SecretScanner) ScanStructured(lines []DiffLine, content []byte) []Finding {
+	if len(lines) > 0 && structured.IsNotebook(lines[0].FilePath) {
+		return s.scanNotebook(lines, content)
+	}
+	findings := s.ScanLines(lines)
+	if len(lines) == 0 || !structured.Supported(lines[0].FilePath) {
+		return findings
+	}
+	if s.ignoreChecker.ShouldIgnore(lines[0].FilePath) {
+		return findings
+	}
+
+	// Unparseable files (e.g. templated YAML) still get line-based scanning
+	entries, err := structured.Parse(lines[0].FilePath, content)
+	if err != nil {
+		return findings
+	}
+	byLine := structured.ByLine(entries)
+
+	found := make(map[int]bool)
+	for i := range findings {
+		found[findings[i].LineNum] = true
+		findings[i].KeyPath = keyPathAt(byLine[findings[i].LineNum], findings[i].StartPos)
+	}
+
+	for _, line := range lines {
+		if found[line.LineNum] || s.disabled[SensitiveKeyRule] {
+			continue
+		}
+		suppressed := IsSuppressed(line.Content, SensitiveKeyRule)
+		if suppressed && !s.policy.IsMandatory(SensitiveKeyRule) {
+			continue
+		}
+		for _, entry := range byLine[line.LineNum] {
+			if !(entry.Sensitive || IsSensitiveKey(entry.Key)) || !LooksLikeLiteralCredential(entry.Value) || s.tooShort(SensitiveKeyRule, entry.Value) {
+				continue
+			}
+			start := strings.Index(line.Content, entry.Value)
+			if start < 0 {
+				continue
+			}
+			description := "Credential-looking key " + entry.Path + " holds a literal value"
+			if suppressed {
+				description = s.policyViolation(SensitiveKeyRule, description)
+			}
+
+			finding := Finding{
+				RuleID:      SensitiveKeyRule,
+				RuleName:    "Hardcoded Credential",
+				FilePath:    line.FilePath,
+				LineNum:     line.LineNum,
+				Content:     line.Content,
+				Match:       entry.Value,
+				Secret:      entry.Value,
+				StartPos:    start,
+				EndPos:      start + len(entry.Value),
+				Description: description,
+				Advice:      s.advice(SensitiveKeyRule, s.literalAdvice(line.FilePath)),
+				Remediation: s.remediation(SensitiveKeyRule, Remediation{}),
+				Severity:    s.severity(SensitiveKeyRule, SeverityError),
+				masking:     s.masking,
+				Commit:      line.Commit,
+				KeyPath:     entry.Path,
+				
+				PolicyViolation: suppressed,
+			}
+			if !s.baseline.Contains(finding) && !s.allowed(finding) && s.checkEncryption(&finding) {
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	return findings
+}
+
+// literalAdvice points to secret references in reference mode, unless the
+// file type has a more specific fix (CI secrets, Terraform variables)
+func (s *SecretScanner) literalAdvice(filePath string) string {
+	advice := structuredAdvice(filePath)
+	if s.references && advice == defaultStructuredAdvice {
+		return referenceAdvice
+	}
+	return advice
+}
+
+// structuredAdvice tailors the fix for hardcoded credentials to the file type
+func structuredAdvice(filePath string) string {
+	switch structured.CIPlatform(filePath) {
+	case structured.GitHubActions:
+		return "Store the value as an encrypted repository secret and reference it as ${{ secrets.NAME }}"
+	case structured.GitLabCI:
+		return "Define the value as a masked CI/CD variable in the project settings and reference it as $NAME"
+	case structured.CircleCI:
+		return "Store the value in a project environment variable or context instead of config.yml"
+	}
+
+	switch {
+	case structured.IsTerraformState(filePath):
+		return "Terraform state holds secrets in plaintext; keep it in a remote backend, not in git"
+	case strings.EqualFold(filepath.Ext(filePath), ".tfvars"):
+		return "Keep secret .tfvars out of git and supply the values as TF_VAR_ environment variables"
+	case structured.IsTerraform(filePath):
+		return "Pass the value through a sensitive variable or the provider's environment variables (e.g. AWS_ACCESS_KEY_ID) instead"
+	}
+	return defaultStructuredAdvice
+}
+
+const defaultStructuredAdvice = "Read the value from an environment variable or secret manager instead"
+
+// keyPathAt picks the entry whose value contains position pos on its line
+func keyPathAt(entries []structured.Entry, pos int) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	best := entries[0]
+	for _, entry := range entries {
+		if entry.Column > 0 && entry.Column-1 <= pos {
+			best = entry
+		}
+	}
+	return best.Path
+}
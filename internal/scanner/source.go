@@ -0,0 +1,91 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+)
+
+// File is content to scan that doesn't come from git: an object in a
+// bucket, an entry of an upload, a database row
+type File struct {
+	// Path names the file in findings and is matched against .secretignore
+	Path string
+
+	// Reader yields the content; it is closed after reading if it is an
+	// io.Closer
+	Reader io.Reader
+
+	// Metadata is copied onto every finding in the file, e.g. a bucket and
+	// object version or a table and primary key
+	Metadata map[string]string
+}
+
+// FileSource feeds files to ScanSource. Next returns io.EOF once there are
+// no more files; any other error ends the scan.
+type FileSource interface {
+	Next() (*File, error)
+}
+
+// Files returns a FileSource yielding the given files in order
+func Files(files ...File) FileSource {
+	return &fileList{files: files}
+}
+
+type fileList struct {
+	files []File
+}
+
+func (l *fileList) Next() (*File, error) {
+	if len(l.files) == 0 {
+		return nil, io.EOF
+	}
+	file := l.files[0]
+	l.files = l.files[1:]
+	return &file, nil
+}
+
+// ScanSource scans every file of source like 'secretlint scan --all' scans
+// a tracked file: ignored paths are skipped, UTF-16 and latin-1 text is
+// transcoded, binary content is skipped and config files are scanned with
+// their key paths. It returns the findings so far and the error if source
+// or a reader fails.
+func (s *SecretScanner) ScanSource(source FileSource) ([]Finding, error) {
+	var findings []Finding
+	for {
+		file, err := source.Next()
+		if err == io.EOF {
+			return findings, nil
+		}
+		if err != nil {
+			return findings, fmt.Errorf("failed to read file source: %w", err)
+		}
+		fileFindings, err := s.scanFile(file)
+		if err != nil {
+			return findings, err
+		}
+		findings = append(findings, fileFindings...)
+	}
+}
+
+// scanFile reads and scans one file of a FileSource
+func (s *SecretScanner) scanFile(file *File) ([]Finding, error) {
+	if closer, ok := file.Reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if s.ignoreChecker.ShouldIgnore(file.Path) {
+		return nil, nil
+	}
+	data, err := io.ReadAll(file.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Path, err)
+	}
+	text, _, ok := DecodeText(data)
+	if !ok {
+		return nil, nil
+	}
+	findings := s.ScanStructured(ContentLines(file.Path, text), text)
+	for i := range findings {
+		findings[i].Metadata = file.Metadata
+	}
+	return findings, nil
+}
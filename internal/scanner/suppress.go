@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// AllowMarker is the inline comment that suppresses findings on its line
+const AllowMarker = "secretlint:allow"
+
+// allowRegex matches the marker and an optional list of rule IDs it applies
+// to. The list ends at the first word that isn't a whole rule ID, so a reason
+// such as "secretlint:allow Because ..." isn't read as the rule "B".
+var allowRegex = regexp.MustCompile(`secretlint:allow\b((?:[ \t,]+[A-Z][A-Z0-9_]*\b)*)`)
+
+// IsSuppressed reports whether a line carries an inline allow comment covering the rule.
+// A bare marker suppresses every rule; "secretlint:allow RULE_A RULE_B" only those listed.
+func IsSuppressed(content, ruleID string) bool {
+	if !strings.Contains(content, AllowMarker) {
+		return false
+	}
+
+	for _, match := range allowRegex.FindAllStringSubmatch(content, -1) {
+		ids := strings.FieldsFunc(match[1], func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		})
+		if len(ids) == 0 {
+			return true
+		}
+		for _, id := range ids {
+			if id == ruleID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// commentStyles maps file extensions to their line comment syntax (prefix, suffix)
+var commentStyles = map[string][2]string{
+	".js": {"//", ""}, ".jsx": {"//", ""}, ".ts": {"//", ""}, ".tsx": {"//", ""},
+	".mjs": {"//", ""}, ".cjs": {"//", ""}, ".go": {"//", ""}, ".java": {"//", ""},
+	".kt": {"//", ""}, ".swift": {"//", ""}, ".c": {"//", ""}, ".h": {"//", ""},
+	".cpp": {"//", ""}, ".cc": {"//", ""}, ".cs": {"//", ""}, ".rs": {"//", ""},
+	".php": {"//", ""}, ".scala": {"//", ""}, ".dart": {"//", ""},
+	".py": {"#", ""}, ".rb": {"#", ""}, ".sh": {"#", ""}, ".bash": {"#", ""},
+	".zsh": {"#", ""}, ".yml": {"#", ""}, ".yaml": {"#", ""}, ".toml": {"#", ""},
+	".env": {"#", ""}, ".ini": {";", ""}, ".cfg": {"#", ""}, ".conf": {"#", ""},
+	".properties": {"#", ""}, ".tf": {"#", ""}, ".tfvars": {"#", ""}, ".r": {"#", ""},
+	".pl": {"#", ""}, ".ps1": {"#", ""}, ".sql": {"--", ""}, ".lua": {"--", ""},
+	".html": {"<!--", " -->"}, ".xml": {"<!--", " -->"}, ".md": {"<!--", " -->"},
+}
+
+// AllowComment builds the inline suppression comment for a file, or "" when
+// the file format has no line comments (e.g. JSON)
+func AllowComment(filePath, ruleID string) string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	style, ok := commentStyles[ext]
+	if !ok {
+		base := filepath.Base(filePath)
+		if base == "Dockerfile" || base == "Makefile" || strings.HasPrefix(base, ".env") {
+			style = [2]string{"#", ""}
+		} else {
+			return ""
+		}
+	}
+	return style[0] + " " + AllowMarker + " " + ruleID + style[1]
+}
@@ -0,0 +1,28 @@
+package scanner
+
+import "testing"
+
+func TestIsSuppressed(t *testing.T) {
+	tests := []struct {
+		content string
+		ruleID  string
+		want    bool
+	}{
+		{`key = "x" # secretlint:allow`, "AWS_ACCESS_KEY", true},
+		{`key = "x" # secretlint:allow AWS_ACCESS_KEY`, "AWS_ACCESS_KEY", true},
+		{`key = "x" # secretlint:allow AWS_ACCESS_KEY`, "GITHUB_TOKEN", false},
+		{`key = "x" # secretlint:allow AWS_ACCESS_KEY, GITHUB_TOKEN`, "GITHUB_TOKEN", true},
+		{`key = "x" <!-- secretlint:allow GITHUB_TOKEN -->`, "GITHUB_TOKEN", true},
+		// The list ends at the first word that isn't a rule ID
+		{`key = "x" # secretlint:allow AWS_ACCESS_KEY because it is a fixture`, "AWS_ACCESS_KEY", true},
+		{`key = "x" # secretlint:allow AWS_ACCESS_KEY because it is a fixture`, "GITHUB_TOKEN", false},
+		{`key = "x" # secretlint:allow AWS_ACCESS_KEY Because it is a fixture`, "GITHUB_TOKEN", false},
+		{`key = "x" # secretlint:allow Because it is a fixture`, "AWS_ACCESS_KEY", true},
+		{`key = "x" # secretlint:allowance`, "AWS_ACCESS_KEY", false},
+	}
+	for _, test := range tests {
+		if got := IsSuppressed(test.content, test.ruleID); got != test.want {
+			t.Errorf("IsSuppressed(%q, %s) = %v, want %v", test.content, test.ruleID, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Values of settings.symlinks.follow
+const (
+	FollowRepo   = "repo"
+	FollowNever  = "never"
+	FollowAlways = "always"
+)
+
+// CheckReadable decides whether a tracked path under root may be read by a
+// full-tree scan. Directories (submodules) are skipped silently; for other
+// paths that are skipped, reason says why: a symlink not followed under the
+// follow setting, or a socket, named pipe or device file, which could block
+// or never end.
+func CheckReadable(root, filePath, follow string) (ok bool, reason string) {
+	fullPath := filepath.Join(root, filePath)
+	info, err := os.Lstat(fullPath)
+	if err != nil || info.IsDir() {
+		return false, ""
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		if follow == FollowNever {
+			return false, "symlink (settings.symlinks.follow: never)"
+		}
+		target, err := filepath.EvalSymlinks(fullPath)
+		if err != nil {
+			return false, "broken symlink"
+		}
+		if follow != FollowAlways && !insideDir(root, target) {
+			return false, "symlink to " + target + ", outside the repository"
+		}
+		if info, err = os.Stat(target); err != nil || info.IsDir() {
+			return false, ""
+		}
+	}
+	if kind := specialFileKind(info.Mode()); kind != "" {
+		return false, kind
+	}
+	return true, ""
+}
+
+// specialFileKind names file types that aren't regular files or directories
+func specialFileKind(mode os.FileMode) string {
+	switch {
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	case mode&os.ModeNamedPipe != 0:
+		return "named pipe"
+	case mode&os.ModeDevice != 0:
+		return "device file"
+	case !mode.IsRegular() && !mode.IsDir():
+		return "special file"
+	}
+	return ""
+}
+
+// insideDir reports whether path resolves to dir or below it
+func insideDir(dir, path string) bool {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ScanSymlinks flags tracked symlinks under root that point at credential
+// files, such as a link to ~/.aws/credentials: anything that follows the
+// link reads the credentials. Links whose own name is sensitive are left to
+// ScanPaths.
+func (s *SecretScanner) ScanSymlinks(root string, filePaths []string, severity string) []Finding {
+	if s.disabled[SensitiveFileRule] {
+		return nil
+	}
+	var findings []Finding
+	for _, filePath := range filePaths {
+		if s.ignoreChecker.ShouldIgnore(filePath) {
+			continue
+		}
+		if _, ok := sensitiveFileDescription(filePath); ok {
+			continue
+		}
+		fullPath := filepath.Join(root, filePath)
+		if info, err := os.Lstat(fullPath); err != nil || info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		target, err := os.Readlink(fullPath)
+		if err != nil {
+			continue
+		}
+		description, ok := sensitiveFileDescription(target)
+		if !ok {
+			continue
+		}
+		finding := Finding{
+			RuleID:      SensitiveFileRule,
+			RuleName:    "Sensitive File",
+			FilePath:    filePath,
+			Match:       filepath.Base(filePath),
+			Secret:      filePath + " -> " + target,
+			Description: "Symlink to " + description + " (" + target + ")",
+			Advice:      s.advice(SensitiveFileRule, "Remove the symlink from git (git rm --cached) and point tools at the credential file through configuration instead"),
+			Remediation: s.remediation(SensitiveFileRule, Remediation{}),
+			Severity:    s.severity(SensitiveFileRule, severity),
+			masking:     s.masking,
+		}
+		if !s.baseline.Contains(finding) && !s.allowed(finding) {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,161 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// VCS is the version control system a repository is kept in. Git supports
+// every scan; Mercurial and Jujutsu repositories support staged-equivalent
+// scans of their uncommitted changes and history scans.
+type VCS interface {
+	// Name is the command of the VCS: git, hg or jj
+	Name() string
+
+	// RepoRoot returns the absolute path of the repository's top-level
+	// directory
+	RepoRoot() (string, error)
+
+	// PendingChanges returns the lines added by the changes the next commit
+	// would record: staged changes in git, working copy changes in hg and jj
+	PendingChanges() ([]DiffLine, error)
+
+	// HistoryChanges returns the lines added by the commits of revs (the
+	// whole repository when revs is empty), tagged with their commit
+	HistoryChanges(revs ...string) ([]DiffLine, error)
+}
+
+// DetectVCS returns the VCS of the repository around the working
+// directory, or nil outside one. Git wins, so a Jujutsu repository
+// colocated with git is scanned as the git repository.
+func DetectVCS() VCS {
+	if gd := NewGitDiffer(); CheckGit() == nil && gd.IsInGitRepo() {
+		return gd
+	}
+	for _, vcs := range []VCS{NewHgDiffer(), NewJJDiffer()} {
+		if _, err := vcs.RepoRoot(); err == nil {
+			return vcs
+		}
+	}
+	return nil
+}
+
+func (gd *GitDiffer) Name() string {
+	return "git"
+}
+
+func (gd *GitDiffer) PendingChanges() ([]DiffLine, error) {
+	return gd.GetStagedChanges()
+}
+
+func (gd *GitDiffer) HistoryChanges(revs ...string) ([]DiffLine, error) {
+	return gd.GetHistoryChanges(revs...)
+}
+
+// HgDiffer reads changes from a Mercurial repository with the hg CLI
+type HgDiffer struct{}
+
+// NewHgDiffer creates a new HgDiffer instance
+func NewHgDiffer() *HgDiffer {
+	return &HgDiffer{}
+}
+
+func (hd *HgDiffer) Name() string {
+	return "hg"
+}
+
+func (hd *HgDiffer) RepoRoot() (string, error) {
+	return vcsRoot("hg", "root")
+}
+
+// PendingChanges returns the lines added in the working directory since
+// its parent revision, including files added with 'hg add'
+func (hd *HgDiffer) PendingChanges() ([]DiffLine, error) {
+	output, err := hg("diff", "--git", "-U0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hg diff: %w", err)
+	}
+	return NewGitDiffer().parseDiff(string(output))
+}
+
+// HistoryChanges returns the lines added by the changesets of revs, each
+// a revset, or by every changeset when revs is empty
+func (hd *HgDiffer) HistoryChanges(revs ...string) ([]DiffLine, error) {
+	args := []string{
+		"log", "--patch", "--git",
+		"--template", `\x00commit {node}\x00{author|person}\x00{author|email}\x00{date|rfc3339date}\x00{desc|firstline}\n`,
+	}
+	for _, rev := range revs {
+		args = append(args, "--rev", rev)
+	}
+	output, err := hg(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hg history: %w", err)
+	}
+	// With --git, hg log prints the same layout as git log -p
+	return NewGitDiffer().parseHistory(string(output))
+}
+
+// hg runs an hg command with HGPLAIN set, so aliases, defaults and other
+// user configuration don't change its output
+func hg(args ...string) ([]byte, error) {
+	cmd := exec.Command("hg", args...)
+	cmd.Env = append(os.Environ(), "HGPLAIN=1")
+	return cmd.Output()
+}
+
+// JJDiffer reads changes from a Jujutsu repository with the jj CLI
+type JJDiffer struct{}
+
+// NewJJDiffer creates a new JJDiffer instance
+func NewJJDiffer() *JJDiffer {
+	return &JJDiffer{}
+}
+
+func (jd *JJDiffer) Name() string {
+	return "jj"
+}
+
+func (jd *JJDiffer) RepoRoot() (string, error) {
+	return vcsRoot("jj", "root")
+}
+
+// PendingChanges returns the lines added by the working-copy commit. jj
+// snapshots the working copy first, so new files count without an add.
+func (jd *JJDiffer) PendingChanges() ([]DiffLine, error) {
+	output, err := exec.Command("jj", "diff", "--git", "--context", "0", "--color", "never").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jj diff: %w", err)
+	}
+	return NewGitDiffer().parseDiff(string(output))
+}
+
+// HistoryChanges returns the lines added by the commits of revs, each a
+// revset, or by every commit when revs is empty
+func (jd *JJDiffer) HistoryChanges(revs ...string) ([]DiffLine, error) {
+	revset := "all()"
+	if len(revs) > 0 {
+		revset = "(" + strings.Join(revs, ") | (") + ")"
+	}
+	args := []string{
+		"log", "--no-graph", "--patch", "--git", "--context", "0", "--color", "never",
+		"--revisions", revset,
+		"--template", `"\0commit " ++ commit_id ++ "\0" ++ author.name() ++ "\0" ++ author.email() ++ "\0" ++ author.timestamp().format("%Y-%m-%dT%H:%M:%S%:z") ++ "\0" ++ description.first_line() ++ "\n"`,
+	}
+	output, err := exec.Command("jj", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jj history: %w", err)
+	}
+	return NewGitDiffer().parseHistory(string(output))
+}
+
+// vcsRoot runs a VCS's root command and returns the directory it prints
+func vcsRoot(command string, args ...string) (string, error) {
+	output, err := exec.Command(command, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s repository root: %w", command, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
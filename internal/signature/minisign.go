@@ -0,0 +1,91 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Minisign signature algorithms: "Ed" signs the message itself, "ED" (the
+// default since minisign 0.10) signs its BLAKE2b-512 hash
+const (
+	algorithmPure      = "Ed"
+	algorithmPrehashed = "ED"
+)
+
+// PublicKey is a minisign public key
+type PublicKey struct {
+	KeyID [8]byte
+	Key   ed25519.PublicKey
+}
+
+// ParsePublicKey reads a minisign public key, either the base64 line alone
+// (as printed by 'minisign -G') or the whole .pub file with its comment
+func ParsePublicKey(text string) (*PublicKey, error) {
+	line := lastLine(text)
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil || len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != algorithmPure {
+		return nil, fmt.Errorf("invalid minisign public key")
+	}
+	key := &PublicKey{Key: ed25519.PublicKey(raw[10:])}
+	copy(key.KeyID[:], raw[2:10])
+	return key, nil
+}
+
+// Verify checks a .minisig signature of message, including the signed
+// trusted comment. It returns the trusted comment on success.
+func (k *PublicKey) Verify(message []byte, minisig []byte) (string, error) {
+	lines := strings.Split(strings.TrimSpace(string(minisig)), "\n")
+	if len(lines) < 4 {
+		return "", fmt.Errorf("invalid minisign signature: expected 4 lines")
+	}
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(raw) != 2+8+ed25519.SignatureSize {
+		return "", fmt.Errorf("invalid minisign signature")
+	}
+	if !bytes.Equal(raw[2:10], k.KeyID[:]) {
+		return "", fmt.Errorf("signature was made with key %X, not the trusted key %X", raw[2:10], k.KeyID[:])
+	}
+	signature := raw[10:]
+
+	signed := message
+	switch string(raw[:2]) {
+	case algorithmPure:
+	case algorithmPrehashed:
+		sum := blake2b.Sum512(message)
+		signed = sum[:]
+	default:
+		return "", fmt.Errorf("unsupported minisign algorithm %q", raw[:2])
+	}
+	if !ed25519.Verify(k.Key, signed, signature) {
+		return "", fmt.Errorf("signature verification failed")
+	}
+
+	const trustedPrefix = "trusted comment: "
+	if !strings.HasPrefix(lines[2], trustedPrefix) {
+		return "", fmt.Errorf("invalid minisign signature: missing trusted comment")
+	}
+	comment := strings.TrimPrefix(lines[2], trustedPrefix)
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return "", fmt.Errorf("invalid minisign signature: bad trusted comment signature")
+	}
+	if !ed25519.Verify(k.Key, append(append([]byte{}, signature...), comment...), global) {
+		return "", fmt.Errorf("trusted comment signature verification failed")
+	}
+	return comment, nil
+}
+
+// lastLine returns the last non-empty line, skipping minisign's comment line
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
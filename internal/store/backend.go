@@ -0,0 +1,58 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Backend persists the findings database. The single-repo CLI keeps it in
+// a local file; SQLite and Postgres backends let fleet scans and several
+// repositories share one database.
+type Backend interface {
+	// Load returns the stored database, or an empty one
+	Load() (*Store, error)
+
+	// Save replaces the stored database with s
+	Save(s *Store) error
+}
+
+// fileBackend keeps the database as JSON under .git/secretlint
+type fileBackend struct {
+	path string
+}
+
+func (b *fileBackend) Load() (*Store, error) {
+	s := &Store{Version: SchemaVersion}
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read findings database: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse findings database %s: %w", b.path, err)
+	}
+	if s.Version > SchemaVersion {
+		return nil, fmt.Errorf("findings database %s uses schema version %d, newer than this secretlint supports (%d)", b.path, s.Version, SchemaVersion)
+	}
+	return s, nil
+}
+
+// Save writes the database, readable only by the current user
+func (b *fileBackend) Save(s *Store) error {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(b.path), err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode findings database: %w", err)
+	}
+	if err := os.WriteFile(b.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write findings database: %w", err)
+	}
+	return nil
+}
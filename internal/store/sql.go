@@ -0,0 +1,240 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// sqlSchema creates the tables of the SQL backends. Rows are keyed by
+// repository, so one database serves a whole fleet, and columns are named
+// after the JSON fields so rows load straight into Scan, Record and Event.
+const sqlSchema = `CREATE TABLE IF NOT EXISTS secretlint_scans (
+	repo TEXT NOT NULL,
+	seq INTEGER NOT NULL,
+	time TEXT NOT NULL,
+	scope TEXT NOT NULL,
+	files INTEGER NOT NULL,
+	fingerprints TEXT NOT NULL,
+	PRIMARY KEY (repo, seq)
+);
+CREATE TABLE IF NOT EXISTS secretlint_findings (
+	repo TEXT NOT NULL,
+	fingerprint TEXT NOT NULL,
+	rule_id TEXT NOT NULL,
+	file TEXT NOT NULL,
+	line INTEGER NOT NULL,
+	status TEXT NOT NULL,
+	first_seen TEXT NOT NULL,
+	last_seen TEXT NOT NULL,
+	resolved_at TEXT,
+	detections INTEGER NOT NULL,
+	recurrences INTEGER NOT NULL,
+	verification TEXT NOT NULL,
+	verified_at TEXT,
+	resolution TEXT NOT NULL,
+	resolution_note TEXT NOT NULL,
+	triaged_at TEXT,
+	PRIMARY KEY (repo, fingerprint)
+);
+CREATE TABLE IF NOT EXISTS secretlint_events (
+	repo TEXT NOT NULL,
+	seq INTEGER NOT NULL,
+	time TEXT NOT NULL,
+	type TEXT NOT NULL,
+	fingerprint TEXT NOT NULL,
+	rule_id TEXT NOT NULL,
+	PRIMARY KEY (repo, seq)
+);
+`
+
+var (
+	scanColumns    = []string{"time", "scope", "files", "fingerprints"}
+	findingColumns = []string{"fingerprint", "rule_id", "file", "line", "status", "first_seen", "last_seen", "resolved_at", "detections", "recurrences", "verification", "verified_at", "resolution", "resolution_note", "triaged_at"}
+	eventColumns   = []string{"time", "type", "fingerprint", "rule_id"}
+)
+
+// dialect is what differs between the SQL backends: the CLI that runs the
+// scripts and how a query builds JSON
+type dialect struct {
+	cli      string
+	args     []string
+	preamble string
+
+	// object builds a JSON object from 'key', value pairs, array
+	// aggregates objects selected from a table, and json parses a column
+	// holding JSON text
+	object string
+	array  func(object, from string) string
+	json   func(column string) string
+}
+
+var sqliteDialect = dialect{
+	cli:    "sqlite3",
+	args:   []string{"-bail"},
+	object: "json_object",
+	array: func(object, from string) string {
+		return fmt.Sprintf("json((SELECT json_group_array(%s) FROM %s))", object, from)
+	},
+	json: func(column string) string { return "json(" + column + ")" },
+}
+
+var postgresDialect = dialect{
+	cli:      "psql",
+	args:     []string{"-X", "-q", "-t", "-A", "-v", "ON_ERROR_STOP=1", "-d"},
+	preamble: "SET client_min_messages = warning;\n",
+	object:   "json_build_object",
+	array: func(object, from string) string {
+		return fmt.Sprintf("(SELECT coalesce(json_agg(%s), '[]'::json) FROM %s)", object, from)
+	},
+	json: func(column string) string { return column + "::json" },
+}
+
+// sqlBackend keeps one repository's findings in a SQL database, run through
+// the database's CLI like the other external tools secretlint drives
+type sqlBackend struct {
+	dialect
+	target string
+	repo   string
+}
+
+// NewSQLite keeps repo's findings in the SQLite database at path, using the
+// sqlite3 CLI
+func NewSQLite(path, repo string) Backend {
+	return &sqlBackend{dialect: sqliteDialect, target: path, repo: repo}
+}
+
+// NewPostgres keeps repo's findings in a Postgres database, using psql. dsn
+// is a connection URI or conninfo string; a password is best left to
+// PGPASSWORD or ~/.pgpass.
+func NewPostgres(dsn, repo string) Backend {
+	return &sqlBackend{dialect: postgresDialect, target: dsn, repo: repo}
+}
+
+func (b *sqlBackend) Load() (*Store, error) {
+	where := "WHERE repo = " + quote(b.repo)
+	query := fmt.Sprintf("SELECT %s('scans', %s, 'findings', %s, 'events', %s);\n",
+		b.object,
+		b.array(b.jsonObject(scanColumns), "(SELECT * FROM secretlint_scans "+where+" ORDER BY seq) scans"),
+		b.array(b.jsonObject(findingColumns), "(SELECT * FROM secretlint_findings "+where+" ORDER BY fingerprint) findings"),
+		b.array(b.jsonObject(eventColumns), "(SELECT * FROM secretlint_events "+where+" ORDER BY seq) events"))
+	output, err := b.run(sqlSchema + query)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows struct {
+		Scans    []Scan    `json:"scans"`
+		Findings []*Record `json:"findings"`
+		Events   []Event   `json:"events"`
+	}
+	if err := json.Unmarshal(output, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse findings database %s: %w", b.cli, err)
+	}
+	s := &Store{
+		Version:  SchemaVersion,
+		Scans:    rows.Scans,
+		Findings: make(map[string]*Record, len(rows.Findings)),
+		Events:   rows.Events,
+	}
+	for _, record := range rows.Findings {
+		s.Findings[record.Fingerprint] = record
+	}
+	return s, nil
+}
+
+// Save replaces the repository's rows in one transaction
+func (b *sqlBackend) Save(s *Store) error {
+	var script strings.Builder
+	script.WriteString("BEGIN;\n" + sqlSchema)
+	repo := quote(b.repo)
+	for _, table := range []string{"secretlint_scans", "secretlint_findings", "secretlint_events"} {
+		fmt.Fprintf(&script, "DELETE FROM %s WHERE repo = %s;\n", table, repo)
+	}
+	for i, scan := range s.Scans {
+		fingerprints, err := json.Marshal(scan.Fingerprints)
+		if err != nil {
+			return fmt.Errorf("failed to encode findings database: %w", err)
+		}
+		insert(&script, "secretlint_scans", append([]string{"repo", "seq"}, scanColumns...), repo, fmt.Sprint(i),
+			quoteTime(&scan.Time), quote(scan.Scope), fmt.Sprint(scan.Files), quote(string(fingerprints)))
+	}
+	for _, fingerprint := range s.sortedFingerprints() {
+		record := s.Findings[fingerprint]
+		insert(&script, "secretlint_findings", append([]string{"repo"}, findingColumns...), repo,
+			quote(record.Fingerprint), quote(record.RuleID), quote(record.File), fmt.Sprint(record.Line),
+			quote(record.Status), quoteTime(&record.FirstSeen), quoteTime(&record.LastSeen), quoteTime(record.ResolvedAt),
+			fmt.Sprint(record.Detections), fmt.Sprint(record.Recurrences),
+			quote(record.Verification), quoteTime(record.VerifiedAt),
+			quote(record.Resolution), quote(record.ResolutionNote), quoteTime(record.TriagedAt))
+	}
+	for i, event := range s.Events {
+		insert(&script, "secretlint_events", append([]string{"repo", "seq"}, eventColumns...), repo, fmt.Sprint(i),
+			quoteTime(&event.Time), quote(event.Type), quote(event.Fingerprint), quote(event.RuleID))
+	}
+	script.WriteString("COMMIT;\n")
+
+	_, err := b.run(script.String())
+	return err
+}
+
+// jsonObject selects columns as a JSON object keyed by column name
+func (b *sqlBackend) jsonObject(columns []string) string {
+	pairs := make([]string, len(columns))
+	for i, column := range columns {
+		value := column
+		if column == "fingerprints" {
+			value = b.json(column)
+		}
+		pairs[i] = fmt.Sprintf("'%s', %s", column, value)
+	}
+	return b.object + "(" + strings.Join(pairs, ", ") + ")"
+}
+
+// run pipes a script to the database CLI and returns its output
+func (b *sqlBackend) run(script string) ([]byte, error) {
+	if _, err := exec.LookPath(b.cli); err != nil {
+		return nil, fmt.Errorf("the findings database needs the %s CLI: %w", b.cli, err)
+	}
+	if b.cli == sqliteDialect.cli {
+		if err := os.MkdirAll(filepath.Dir(b.target), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(b.target), err)
+		}
+	}
+	cmd := exec.Command(b.cli, append(b.args, b.target)...)
+	cmd.Stdin = strings.NewReader(b.preamble + script)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("findings database (%s): %s", b.cli, message)
+	}
+	return output, nil
+}
+
+// insert adds an INSERT of one row to script
+func insert(script *strings.Builder, table string, columns []string, values ...string) {
+	fmt.Fprintf(script, "INSERT INTO %s (%s) VALUES (%s);\n", table, strings.Join(columns, ", "), strings.Join(values, ", "))
+}
+
+// quote renders a SQL string literal
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// quoteTime renders a time as an RFC 3339 literal, or NULL
+func quoteTime(t *time.Time) string {
+	if t == nil {
+		return "NULL"
+	}
+	return quote(t.UTC().Format(time.RFC3339Nano))
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats summarizes the database over a time window
+type Stats struct {
+	Since     time.Time   `json:"since"`
+	Scans     int         `json:"scans"`
+	Blocked   int         `json:"blocked_scans"`
+	Open      int         `json:"open"`
+	Resolved  int         `json:"resolved"`
+	Recurring int         `json:"recurring"`
+	Periods   []Period    `json:"periods"`
+	TopRules  []RuleCount `json:"top_rules"`
+
+	// MeanHoursToResolve is the average time from first detection to resolution
+	MeanHoursToResolve float64 `json:"mean_hours_to_resolve"`
+}
+
+// Period counts status changes within one bucket of time
+type Period struct {
+	Start     time.Time `json:"start"`
+	New       int       `json:"new"`
+	Resolved  int       `json:"resolved"`
+	Recurring int       `json:"recurring"`
+}
+
+// RuleCount is the number of distinct findings for a rule
+type RuleCount struct {
+	RuleID string `json:"rule_id"`
+	Count  int    `json:"count"`
+}
+
+// Stats computes trend metrics for events after since, bucketed by period
+func (s *Store) Stats(since time.Time, period time.Duration) *Stats {
+	stats := &Stats{Since: since}
+
+	for _, scan := range s.Scans {
+		if scan.Time.Before(since) {
+			continue
+		}
+		stats.Scans++
+		if len(scan.Fingerprints) > 0 {
+			stats.Blocked++
+		}
+	}
+
+	buckets := make(map[int64]*Period)
+	ruleCounts := make(map[string]int)
+	for _, event := range s.Events {
+		if event.Time.Before(since) {
+			continue
+		}
+		start := since.Add(event.Time.Sub(since) / period * period)
+		bucket, ok := buckets[start.Unix()]
+		if !ok {
+			bucket = &Period{Start: start}
+			buckets[start.Unix()] = bucket
+		}
+		switch event.Type {
+		case EventNew:
+			bucket.New++
+			ruleCounts[event.RuleID]++
+		case EventResolved:
+			bucket.Resolved++
+		case EventRecurring:
+			bucket.Recurring++
+		}
+	}
+	for _, bucket := range buckets {
+		stats.Periods = append(stats.Periods, *bucket)
+	}
+	sort.Slice(stats.Periods, func(i, j int) bool {
+		return stats.Periods[i].Start.Before(stats.Periods[j].Start)
+	})
+
+	for ruleID, count := range ruleCounts {
+		stats.TopRules = append(stats.TopRules, RuleCount{RuleID: ruleID, Count: count})
+	}
+	sort.Slice(stats.TopRules, func(i, j int) bool {
+		if stats.TopRules[i].Count != stats.TopRules[j].Count {
+			return stats.TopRules[i].Count > stats.TopRules[j].Count
+		}
+		return stats.TopRules[i].RuleID < stats.TopRules[j].RuleID
+	})
+
+	var fixTime time.Duration
+	for _, record := range s.Findings {
+		switch record.Status {
+		case StatusOpen:
+			stats.Open++
+		case StatusResolved:
+			stats.Resolved++
+			if record.ResolvedAt != nil {
+				fixTime += record.ResolvedAt.Sub(record.FirstSeen)
+			}
+		}
+		if record.Recurrences > 0 {
+			stats.Recurring++
+		}
+	}
+	if stats.Resolved > 0 {
+		stats.MeanHoursToResolve = fixTime.Hours() / float64(stats.Resolved)
+	}
+
+	return stats
+}
@@ -0,0 +1,242 @@
+package store
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"secretlint/internal/scanner"
+)
+
+// FileName is the findings database kept under .git/secretlint/
+const FileName = "findings.json"
+
+// SchemaVersion is bumped whenever the database layout changes incompatibly
+const SchemaVersion = 1
+
+// Finding statuses
+const (
+	StatusOpen     = "open"
+	StatusResolved = "resolved"
+)
+
+// Event types recorded for trend reporting
+const (
+	EventNew       = "new"
+	EventResolved  = "resolved"
+	EventRecurring = "recurring"
+)
+
+// Store is the local findings database. Like reports, it never holds
+// plaintext secrets, only fingerprints and locations.
+type Store struct {
+	Version  int                `json:"version"`
+	Scans    []Scan             `json:"scans"`
+	Findings map[string]*Record `json:"findings"`
+	Events   []Event            `json:"events"`
+
+	backend Backend
+}
+
+// Scan is one recorded scan run
+type Scan struct {
+	Time         time.Time `json:"time"`
+	Scope        string    `json:"scope"`
+	Files        int       `json:"files"`
+	Fingerprints []string  `json:"fingerprints"`
+}
+
+// Record tracks a single secret across scans
+type Record struct {
+	Fingerprint string     `json:"fingerprint"`
+	RuleID      string     `json:"rule_id"`
+	File        string     `json:"file"`
+	Line        int        `json:"line"`
+	Status      string     `json:"status"`
+	FirstSeen   time.Time  `json:"first_seen"`
+	LastSeen    time.Time  `json:"last_seen"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	Detections  int        `json:"detections"`
+	Recurrences int        `json:"recurrences"`
+
+	// Verification is the latest 'secretlint recheck' outcome
+	Verification string     `json:"verification,omitempty"`
+	VerifiedAt   *time.Time `json:"verified_at,omitempty"`
+
+	// Resolution is the triage outcome imported by 'secretlint report annotate'
+	Resolution     string     `json:"resolution,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty"`
+	TriagedAt      *time.Time `json:"triaged_at,omitempty"`
+}
+
+// Event is a status change of a finding
+type Event struct {
+	Time        time.Time `json:"time"`
+	Type        string    `json:"type"`
+	Fingerprint string    `json:"fingerprint"`
+	RuleID      string    `json:"rule_id"`
+}
+
+// Path returns the database location inside the given git directory
+func Path(gitDir string) string {
+	return filepath.Join(gitDir, "secretlint", FileName)
+}
+
+// Open loads the database file at path, returning an empty one if it
+// doesn't exist yet
+func Open(path string) (*Store, error) {
+	return OpenBackend(&fileBackend{path: path})
+}
+
+// OpenBackend loads the database from a backend; Save writes it back there
+func OpenBackend(backend Backend) (*Store, error) {
+	s, err := backend.Load()
+	if err != nil {
+		return nil, err
+	}
+	if s.Findings == nil {
+		s.Findings = make(map[string]*Record)
+	}
+	s.backend = backend
+	return s, nil
+}
+
+// Record adds a scan of the lines a diff added, as staged and pre-push
+// scans see them. A finding missing from a diff may still be in the file,
+// so nothing is marked resolved; only scans of whole files resolve findings.
+func (s *Store) Record(scope string, files int, findings []scanner.Finding, now time.Time) {
+	s.record(scope, files, func(string) bool { return false }, detectionsOf(findings), now)
+}
+
+// RecordFiles adds a scan of whole files, such as 'secretlint scan --all':
+// open findings in scannedFiles that weren't detected again are marked
+// resolved, since other files weren't looked at
+func (s *Store) RecordFiles(scope string, scannedFiles []string, findings []scanner.Finding, now time.Time) {
+	covered := make(map[string]bool, len(scannedFiles))
+	for _, filePath := range scannedFiles {
+		covered[filePath] = true
+	}
+	s.record(scope, len(scannedFiles), func(filePath string) bool { return covered[filePath] }, detectionsOf(findings), now)
+}
+
+// detectionsOf converts scanner findings to detections
+func detectionsOf(findings []scanner.Finding) []Detection {
+	detections := make([]Detection, len(findings))
+	for i, finding := range findings {
+		detections[i] = Detection{Fingerprint: finding.Fingerprint(), RuleID: finding.RuleID, File: finding.FilePath, Line: finding.LineNum}
+	}
+	return detections
+}
+
+// Detection is a finding known by its fingerprint, as read from a report
+type Detection struct {
+	Fingerprint string
+	RuleID      string
+	File        string
+	Line        int
+}
+
+// RecordFull adds a scan of every file of the repository, such as one
+// 'secretlint fleet scan' ran, given as a report's findings: any open
+// finding that wasn't detected again is marked resolved
+func (s *Store) RecordFull(scope string, detections []Detection, now time.Time) {
+	s.record(scope, 0, func(string) bool { return true }, detections, now)
+}
+
+func (s *Store) record(scope string, files int, covered func(filePath string) bool, detections []Detection, now time.Time) {
+	scan := Scan{Time: now, Scope: scope, Files: files, Fingerprints: []string{}}
+	detected := make(map[string]bool)
+
+	for _, finding := range detections {
+		fingerprint := finding.Fingerprint
+		if detected[fingerprint] {
+			continue
+		}
+		detected[fingerprint] = true
+		scan.Fingerprints = append(scan.Fingerprints, fingerprint)
+
+		record, ok := s.Findings[fingerprint]
+		if !ok {
+			record = &Record{
+				Fingerprint: fingerprint,
+				RuleID:      finding.RuleID,
+				Status:      StatusOpen,
+				FirstSeen:   now,
+			}
+			s.Findings[fingerprint] = record
+			s.addEvent(now, EventNew, record)
+		} else if record.Status == StatusResolved {
+			record.Status = StatusOpen
+			record.ResolvedAt = nil
+			record.Recurrences++
+			s.addEvent(now, EventRecurring, record)
+		}
+
+		record.File = finding.File
+		record.Line = finding.Line
+		record.LastSeen = now
+		record.Detections++
+	}
+
+	for _, fingerprint := range s.sortedFingerprints() {
+		record := s.Findings[fingerprint]
+		if record.Status == StatusOpen && covered(record.File) && !detected[fingerprint] {
+			resolvedAt := now
+			record.Status = StatusResolved
+			record.ResolvedAt = &resolvedAt
+			s.addEvent(now, EventResolved, record)
+		}
+	}
+
+	s.Scans = append(s.Scans, scan)
+}
+
+// SetVerification records a recheck outcome for a known finding
+func (s *Store) SetVerification(fingerprint, status string, now time.Time) {
+	record, ok := s.Findings[fingerprint]
+	if !ok {
+		return
+	}
+	verifiedAt := now
+	record.Verification = status
+	record.VerifiedAt = &verifiedAt
+}
+
+// SetResolution records the triage outcome of a known finding, reporting
+// whether the finding is known; the open status clears it
+func (s *Store) SetResolution(fingerprint, status, note string, now time.Time) bool {
+	record, ok := s.Findings[fingerprint]
+	if !ok {
+		return false
+	}
+	triagedAt := now
+	record.Resolution, record.ResolutionNote, record.TriagedAt = status, note, &triagedAt
+	if status == "open" {
+		record.Resolution, record.ResolutionNote = "", ""
+	}
+	return true
+}
+
+// Save writes the database back to where it was loaded from
+func (s *Store) Save() error {
+	return s.backend.Save(s)
+}
+
+func (s *Store) addEvent(now time.Time, eventType string, record *Record) {
+	s.Events = append(s.Events, Event{
+		Time:        now,
+		Type:        eventType,
+		Fingerprint: record.Fingerprint,
+		RuleID:      record.RuleID,
+	})
+}
+
+// sortedFingerprints keeps event order deterministic across runs
+func (s *Store) sortedFingerprints() []string {
+	fingerprints := make([]string, 0, len(s.Findings))
+	for fingerprint := range s.Findings {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+	return fingerprints
+}
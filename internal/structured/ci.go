@@ -0,0 +1,32 @@
+package structured
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// CI platforms with a recognizable pipeline file
+const (
+	GitHubActions = "GitHub Actions"
+	GitLabCI      = "GitLab CI"
+	CircleCI      = "CircleCI"
+)
+
+// CIPlatform names the CI system a pipeline file belongs to, or "" if the
+// file isn't a pipeline definition
+func CIPlatform(filePath string) string {
+	path := filepath.ToSlash(filePath)
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yml" && ext != ".yaml" {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(path, ".github/workflows/") || strings.Contains(path, "/.github/workflows/"):
+		return GitHubActions
+	case filepath.Base(path) == ".gitlab-ci.yml" || strings.HasSuffix(path, ".gitlab-ci.yml"):
+		return GitLabCI
+	case path == ".circleci/config.yml" || strings.HasSuffix(path, "/.circleci/config.yml"):
+		return CircleCI
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package structured
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// isDotenv matches .env, .env.* (e.g. .env.production) and *.env files
+func isDotenv(filePath string) bool {
+	base := filepath.Base(filePath)
+	return base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".env")
+}
+
+// parseDotenv reads KEY=value lines as understood by dotenv loaders and
+// docker compose: optional "export", quoted values and trailing comments
+func parseDotenv(content []byte) ([]Entry, error) {
+	var entries []Entry
+	for i, raw := range strings.Split(string(content), "\n") {
+		raw = strings.TrimSuffix(raw, "\r")
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		eq := strings.Index(line, "=")
+		if eq <= 0 {
+			continue
+		}
+		key := strings.TrimSpace(line[:eq])
+		value := strings.TrimSpace(line[eq+1:])
+		if !strings.HasPrefix(value, `"`) && !strings.HasPrefix(value, "'") {
+			// Unquoted values end at an inline comment
+			if j := strings.Index(value, " #"); j >= 0 {
+				value = strings.TrimSpace(value[:j])
+			}
+		} else if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			value = value[1 : end+1]
+		}
+
+		column := 0
+		if value != "" {
+			column = strings.Index(raw, value) + 1
+		}
+		entries = append(entries, Entry{Line: i + 1, Path: key, Key: key, Value: value, Column: column})
+	}
+	return entries, nil
+}
+
+// splitEnvironmentItem splits docker compose's list form ("- NAME=value")
+// into the variable name and its value
+func splitEnvironmentItem(item string) (string, string, bool) {
+	eq := strings.Index(item, "=")
+	if eq <= 0 {
+		return "", "", false
+	}
+	return item[:eq], item[eq+1:], true
+}
@@ -0,0 +1,43 @@
+package structured
+
+import "strings"
+
+// parseINI handles INI-style files ([section] and key = value or key: value)
+// and Java properties, which are the same without sections
+func parseINI(content []byte) ([]Entry, error) {
+	var entries []Entry
+	section := ""
+
+	for i, raw := range strings.Split(string(content), "\n") {
+		raw = strings.TrimSuffix(raw, "\r")
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep <= 0 {
+			continue
+		}
+		key := strings.TrimSpace(line[:sep])
+		value := unquote(strings.TrimSpace(stripComment(line[sep+1:], " ;")))
+		path := key
+		if section != "" {
+			path = joinPath(section, key)
+		}
+
+		entries = append(entries, Entry{
+			Line:   i + 1,
+			Path:   path,
+			Key:    lastSegment(key),
+			Value:  value,
+			Column: strings.Index(raw, value) + 1,
+		})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,107 @@
+package structured
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CellLine is one line of text inside a Jupyter notebook cell, decoded from
+// its JSON string and tied back to the line of the .ipynb file holding it
+type CellLine struct {
+	FileLine int
+	Cell     int // 1-based position of the cell in the notebook
+	Output   bool
+	Line     int // 1-based line within the cell's source or outputs
+	Text     string
+}
+
+// Location describes where the line sits in the notebook, e.g. "cell 3, line 2"
+func (c CellLine) Location() string {
+	if c.Output {
+		return fmt.Sprintf("cell %d output, line %d", c.Cell, c.Line)
+	}
+	return fmt.Sprintf("cell %d, line %d", c.Cell, c.Line)
+}
+
+// IsNotebook reports whether a file is a Jupyter notebook
+func IsNotebook(filePath string) bool {
+	return strings.ToLower(filepath.Ext(filePath)) == ".ipynb"
+}
+
+// ParseNotebook extracts the source and text outputs of every cell. Rich
+// outputs such as images are skipped.
+func ParseNotebook(content []byte) ([]CellLine, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse notebook: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	var lines []CellLine
+	cells := mappingValue(root.Content[0], "cells")
+	if cells == nil {
+		return nil, nil
+	}
+	for i, cell := range cells.Content {
+		number := i + 1
+		lines, _ = appendCellText(lines, mappingValue(cell, "source"), number, false, 0)
+
+		outputs := mappingValue(cell, "outputs")
+		if outputs == nil {
+			continue
+		}
+		outputLine := 0
+		for _, output := range outputs.Content {
+			// Stream outputs carry "text"; execute results carry data["text/plain"]
+			text := mappingValue(output, "text")
+			if text == nil {
+				text = mappingValue(mappingValue(output, "data"), "text/plain")
+			}
+			lines, outputLine = appendCellText(lines, text, number, true, outputLine)
+		}
+	}
+	return lines, nil
+}
+
+// appendCellText splits a notebook text field, which is either a string or a
+// list of strings, into lines numbered after offset. It returns the number
+// of the last line added.
+func appendCellText(lines []CellLine, node *yaml.Node, cell int, output bool, offset int) ([]CellLine, int) {
+	if node == nil {
+		return lines, offset
+	}
+	parts := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		parts = node.Content
+	}
+
+	number := offset
+	for _, part := range parts {
+		if part.Kind != yaml.ScalarNode {
+			continue
+		}
+		for _, text := range strings.Split(strings.TrimSuffix(part.Value, "\n"), "\n") {
+			number++
+			lines = append(lines, CellLine{FileLine: part.Line, Cell: cell, Output: output, Line: number, Text: text})
+		}
+	}
+	return lines, number
+}
+
+// mappingValue returns the value for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
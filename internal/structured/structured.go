@@ -0,0 +1,88 @@
+package structured
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Entry is a scalar value in a config file together with its key path,
+// e.g. "database.password" or "servers[0].token"
+type Entry struct {
+	Line  int
+	Path  string
+	Key   string
+	Value string
+
+	// Column is the 1-based position of the value on its line, or 0 if unknown
+	Column int
+
+	// Sensitive is set when the file format itself marks the value as secret,
+	// e.g. Terraform state's sensitive_attributes
+	Sensitive bool
+}
+
+// parser turns a file's content into entries
+type parser func(content []byte) ([]Entry, error)
+
+// parsers maps file extensions to their format
+var parsers = map[string]parser{
+	".yml":        parseYAML,
+	".yaml":       parseYAML,
+	".json":       parseYAML, // JSON is a subset of YAML and keeps line numbers
+	".toml":       parseTOML,
+	".ini":        parseINI,
+	".cfg":        parseINI,
+	".conf":       parseINI,
+	".properties": parseINI,
+	".tf":         parseHCL,
+	".tfvars":     parseHCL,
+}
+
+// parserFor picks the parser for a file by name, then by extension
+func parserFor(filePath string) (parser, bool) {
+	if isDotenv(filePath) {
+		return parseDotenv, true
+	}
+	if IsTerraformState(filePath) {
+		return parseTerraformState, true
+	}
+	parse, ok := parsers[strings.ToLower(filepath.Ext(filePath))]
+	return parse, ok
+}
+
+// Supported reports whether a file is a recognized config format or a
+// notebook, i.e. whether scanning it benefits from the whole file's content
+func Supported(filePath string) bool {
+	_, ok := parserFor(filePath)
+	return ok || IsNotebook(filePath)
+}
+
+// Parse extracts key paths and scalar values from a config file. Files in
+// unrecognized formats yield no entries.
+func Parse(filePath string, content []byte) ([]Entry, error) {
+	parse, ok := parserFor(filePath)
+	if !ok {
+		return nil, nil
+	}
+	return parse(content)
+}
+
+// ByLine indexes entries by line number
+func ByLine(entries []Entry) map[int][]Entry {
+	index := make(map[int][]Entry)
+	for _, entry := range entries {
+		index[entry.Line] = append(index[entry.Line], entry)
+	}
+	return index
+}
+
+// joinPath appends a key to a dotted path, quoting keys that contain dots
+func joinPath(prefix, key string) string {
+	if strings.ContainsAny(key, ". ") {
+		key = `"` + key + `"`
+	}
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
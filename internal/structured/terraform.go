@@ -0,0 +1,190 @@
+package structured
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IsTerraformState reports whether a file is a Terraform state file or backup
+func IsTerraformState(filePath string) bool {
+	base := strings.ToLower(filepath.Base(filePath))
+	return strings.HasSuffix(base, ".tfstate") || strings.HasSuffix(base, ".tfstate.backup")
+}
+
+// IsTerraform reports whether a file is Terraform configuration or variables
+func IsTerraform(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return ext == ".tf" || ext == ".tfvars"
+}
+
+// parseTerraformState parses state JSON and marks the values Terraform itself
+// records as sensitive: instance attributes listed in sensitive_attributes
+// and outputs declared sensitive
+func parseTerraformState(content []byte) ([]Entry, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse Terraform state: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	var entries []Entry
+	walkYAML(&root, "", "", &entries)
+
+	state := root.Content[0]
+	var sensitive []string
+	if resources := mappingValue(state, "resources"); resources != nil {
+		for i, resource := range resources.Content {
+			instances := mappingValue(resource, "instances")
+			if instances == nil {
+				continue
+			}
+			for j, instance := range instances.Content {
+				prefix := "resources[" + strconv.Itoa(i) + "].instances[" + strconv.Itoa(j) + "].attributes"
+				if attributes := mappingValue(instance, "sensitive_attributes"); attributes != nil {
+					for _, steps := range attributes.Content {
+						sensitive = append(sensitive, attributePath(prefix, steps))
+					}
+				}
+			}
+		}
+	}
+	if outputs := mappingValue(state, "outputs"); outputs != nil {
+		for i := 0; i+1 < len(outputs.Content); i += 2 {
+			if flag := mappingValue(outputs.Content[i+1], "sensitive"); flag != nil && flag.Value == "true" {
+				sensitive = append(sensitive, joinPath(joinPath("outputs", outputs.Content[i].Value), "value"))
+			}
+		}
+	}
+
+	for i := range entries {
+		for _, path := range sensitive {
+			if entries[i].Path == path || strings.HasPrefix(entries[i].Path, path+".") || strings.HasPrefix(entries[i].Path, path+"[") {
+				entries[i].Sensitive = true
+			}
+		}
+	}
+	return entries, nil
+}
+
+// attributePath converts a sensitive_attributes step list, e.g.
+// [{"type": "get_attr", "value": "password"}], into an entry path
+func attributePath(prefix string, steps *yaml.Node) string {
+	path := prefix
+	for _, step := range steps.Content {
+		value := mappingValue(step, "value")
+		if value == nil {
+			continue
+		}
+		if kind := mappingValue(step, "type"); kind != nil && kind.Value == "index" {
+			if value.Tag == "!!int" {
+				path += "[" + value.Value + "]"
+				continue
+			}
+		}
+		path = joinPath(path, value.Value)
+	}
+	return path
+}
+
+// parseHCL handles the subset of HCL found in .tf and .tfvars files: nested
+// blocks with labels, object values and single-line string attributes.
+// Heredocs, lists and expressions are skipped.
+func parseHCL(content []byte) ([]Entry, error) {
+	var entries []Entry
+	var stack []string
+	heredoc := ""
+	inComment := false
+
+	for i, raw := range strings.Split(string(content), "\n") {
+		raw = strings.TrimSuffix(raw, "\r")
+		line := strings.TrimSpace(raw)
+
+		switch {
+		case heredoc != "":
+			if line == heredoc {
+				heredoc = ""
+			}
+			continue
+		case inComment:
+			if strings.Contains(line, "*/") {
+				inComment = false
+			}
+			continue
+		case strings.HasPrefix(line, "/*"):
+			inComment = !strings.Contains(line, "*/")
+			continue
+		}
+		line = strings.TrimSpace(stripComment(stripComment(line, "#"), "//"))
+		if line == "" {
+			continue
+		}
+
+		path := strings.Join(stack, ".")
+		if line == "}" || line == "}," {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		eq := strings.IndexAny(line, "=:")
+		if eq < 0 || (strings.ContainsAny(line[:eq], "\"{") && !strings.HasPrefix(line, `"`)) {
+			// Block header: provider "aws" {
+			if strings.HasSuffix(line, "{") {
+				var labels []string
+				for _, field := range strings.Fields(strings.TrimSuffix(line, "{")) {
+					labels = append(labels, unquote(field))
+				}
+				stack = append(stack, blockPath(labels))
+			}
+			continue
+		}
+
+		key := unquote(strings.TrimSpace(line[:eq]))
+		value := strings.TrimSuffix(strings.TrimSpace(line[eq+1:]), ",")
+		switch {
+		case value == "{":
+			stack = append(stack, joinPath("", key))
+			continue
+		case strings.HasPrefix(value, "<<"):
+			heredoc = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(value, "<<"), "-"))
+			continue
+		case !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`):
+			continue
+		}
+
+		value = unquote(value)
+		valueKey := key
+		// variable "db_password" { default = "..." } names the secret in its label
+		if (key == "default" || key == "value") && len(stack) > 0 {
+			valueKey = stack[len(stack)-1]
+			if j := strings.LastIndex(valueKey, "."); j >= 0 {
+				valueKey = valueKey[j+1:]
+			}
+		}
+		entries = append(entries, Entry{
+			Line:   i + 1,
+			Path:   joinPath(path, key),
+			Key:    unquote(valueKey),
+			Value:  value,
+			Column: strings.Index(raw, value) + 1,
+		})
+	}
+
+	return entries, nil
+}
+
+// blockPath joins a block type and its labels, e.g. provider.aws
+func blockPath(labels []string) string {
+	path := ""
+	for _, label := range labels {
+		path = joinPath(path, label)
+	}
+	return path
+}
@@ -0,0 +1,129 @@
+package structured
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parseTOML handles the common TOML subset: tables, arrays of tables,
+// dotted keys, single-line values and one-level inline tables
+func parseTOML(content []byte) ([]Entry, error) {
+	var entries []Entry
+	table := ""
+	arrayCounts := make(map[string]int)
+
+	for i, raw := range strings.Split(string(content), "\n") {
+		line := strings.TrimSpace(stripComment(strings.TrimSuffix(raw, "\r"), "#"))
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "[[") && strings.HasSuffix(line, "]]"):
+			name := normalizeKey(strings.TrimSpace(line[2 : len(line)-2]))
+			table = name + "[" + strconv.Itoa(arrayCounts[name]) + "]"
+			arrayCounts[name]++
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			table = normalizeKey(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			continue
+		}
+		key := normalizeKey(strings.TrimSpace(line[:eq]))
+		value := strings.TrimSpace(line[eq+1:])
+		path := key
+		if table != "" {
+			path = table + "." + key
+		}
+
+		if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+			for _, pair := range splitOutsideQuotes(value[1:len(value)-1], ',') {
+				if j := strings.Index(pair, "="); j >= 0 {
+					innerKey := normalizeKey(strings.TrimSpace(pair[:j]))
+					innerValue := strings.TrimSpace(pair[j+1:])
+					entries = append(entries, tomlEntry(raw, i+1, path+"."+innerKey, lastSegment(innerKey), innerValue))
+				}
+			}
+			continue
+		}
+		entries = append(entries, tomlEntry(raw, i+1, path, lastSegment(key), value))
+	}
+
+	return entries, nil
+}
+
+func tomlEntry(raw string, lineNum int, path, key, value string) Entry {
+	unquoted := unquote(value)
+	return Entry{Line: lineNum, Path: path, Key: key, Value: unquoted, Column: strings.Index(raw, unquoted) + 1}
+}
+
+// normalizeKey removes quotes and spaces around dotted key segments
+func normalizeKey(key string) string {
+	parts := splitOutsideQuotes(key, '.')
+	for i, part := range parts {
+		parts[i] = unquote(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ".")
+}
+
+func lastSegment(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[i+1:]
+	}
+	return key
+}
+
+// unquote strips TOML/INI string quotes
+func unquote(value string) string {
+	if len(value) >= 2 {
+		if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// stripComment removes a trailing comment that isn't inside quotes
+func stripComment(line, marker string) string {
+	inQuote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inQuote != 0:
+			if c == inQuote {
+				inQuote = 0
+			}
+		case c == '"' || c == '\'':
+			inQuote = c
+		case strings.HasPrefix(line[i:], marker):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitOutsideQuotes splits on sep, ignoring separators inside quotes
+func splitOutsideQuotes(value string, sep byte) []string {
+	var parts []string
+	inQuote := byte(0)
+	start := 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case inQuote != 0:
+			if c == inQuote {
+				inQuote = 0
+			}
+		case c == '"' || c == '\'':
+			inQuote = c
+		case c == sep:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
@@ -0,0 +1,61 @@
+package structured
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML walks YAML (and JSON) documents, which carry line numbers per node
+func parseYAML(content []byte) ([]Entry, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	var entries []Entry
+	walkYAML(&root, "", "", &entries)
+	return entries, nil
+}
+
+func walkYAML(node *yaml.Node, path, key string, entries *[]Entry) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			walkYAML(child, path, key, entries)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childKey := node.Content[i].Value
+			walkYAML(node.Content[i+1], joinPath(path, childKey), childKey, entries)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			// docker compose allows "environment: [NAME=value, ...]"
+			if key == "environment" && child.Kind == yaml.ScalarNode {
+				if name, value, ok := splitEnvironmentItem(child.Value); ok {
+					*entries = append(*entries, Entry{
+						Line:   child.Line,
+						Path:   joinPath(path, name),
+						Key:    name,
+						Value:  value,
+						Column: child.Column + len(name) + 1,
+					})
+					continue
+				}
+			}
+			walkYAML(child, path+"["+strconv.Itoa(i)+"]", key, entries)
+		}
+	case yaml.ScalarNode:
+		*entries = append(*entries, Entry{
+			Line:   node.Line,
+			Path:   path,
+			Key:    key,
+			Value:  node.Value,
+			Column: node.Column,
+		})
+	case yaml.AliasNode:
+		// Aliases repeat values defined elsewhere; the anchor is reported instead
+	}
+}
@@ -0,0 +1,12 @@
+//go:build notelemetry
+// +build notelemetry
+
+package telemetry
+
+// CompiledIn is false in builds made with -tags notelemetry
+const CompiledIn = false
+
+// Send does nothing: telemetry was compiled out
+func Send(endpoint string, report Report) error {
+	return nil
+}
@@ -0,0 +1,24 @@
+package telemetry
+
+import "time"
+
+// Timeout keeps a slow collector from delaying commits and pushes
+const Timeout = 2 * time.Second
+
+// Report is the opt-in, anonymous summary of one scan run. It carries no
+// repository, path, user, fingerprint or timestamp, only the counts a
+// platform team needs to measure a rollout.
+type Report struct {
+	Tool       string `json:"tool"`
+	Version    string `json:"version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	Mode       string `json:"mode"`
+	Hook       bool   `json:"hook"`
+	DurationMS int64  `json:"duration_ms"`
+	Files      int    `json:"files"`
+	Findings   int    `json:"findings"`
+
+	// Rules counts findings per rule ID
+	Rules map[string]int `json:"rules,omitempty"`
+}
@@ -0,0 +1,102 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GitHub files issues through the GitHub REST API
+type GitHub struct {
+	client   *apiClient
+	baseURL  string
+	repo     string
+	existing map[string]githubIssue
+}
+
+type githubIssue struct {
+	Number  int    `json:"number"`
+	State   string `json:"state"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// NewGitHub creates a GitHub tracker for owner/name; baseURL is the API root
+// (https://api.github.com, or https://HOST/api/v3 for GitHub Enterprise)
+func NewGitHub(baseURL, repo, token string) *GitHub {
+	return &GitHub{
+		client: newAPIClient(func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Accept", "application/vnd.github+json")
+		}),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		repo:    repo,
+	}
+}
+
+// Upsert creates an issue for the fingerprint or updates (and reopens) the existing one
+func (g *GitHub) Upsert(issue Issue) (Result, error) {
+	if err := g.loadExisting(); err != nil {
+		return Result{}, err
+	}
+
+	if current, ok := g.existing[issue.Fingerprint]; ok {
+		update := map[string]interface{}{"title": issue.Title, "body": issue.Body}
+		action := "updated"
+		if current.State == "closed" {
+			update["state"] = "open"
+			action = "reopened"
+		}
+		url := fmt.Sprintf("%s/repos/%s/issues/%d", g.baseURL, g.repo, current.Number)
+		if err := g.client.do("PATCH", url, update, nil); err != nil {
+			return Result{}, err
+		}
+		return Result{Action: action, URL: current.HTMLURL}, nil
+	}
+
+	var created githubIssue
+	create := map[string]interface{}{"title": issue.Title, "body": issue.Body, "labels": []string{Label}}
+	if err := g.client.do("POST", fmt.Sprintf("%s/repos/%s/issues", g.baseURL, g.repo), create, &created); err != nil {
+		return Result{}, err
+	}
+	g.existing[issue.Fingerprint] = created
+	return Result{Action: "created", URL: created.HTMLURL}, nil
+}
+
+// loadExisting indexes all secretlint-labelled issues by fingerprint
+func (g *GitHub) loadExisting() error {
+	if g.existing != nil {
+		return nil
+	}
+	g.existing = make(map[string]githubIssue)
+
+	for page := 1; ; page++ {
+		var issues []githubIssue
+		url := fmt.Sprintf("%s/repos/%s/issues?labels=%s&state=all&per_page=100&page=%d", g.baseURL, g.repo, Label, page)
+		if err := g.client.do("GET", url, nil, &issues); err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			if fingerprint := extractFingerprint(issue.Body); fingerprint != "" {
+				g.existing[fingerprint] = issue
+			}
+		}
+		if len(issues) < 100 {
+			return nil
+		}
+	}
+}
+
+// extractFingerprint finds the fingerprint marker in an issue body
+func extractFingerprint(body string) string {
+	marker := fingerprintMarker("")
+	i := strings.Index(body, marker)
+	if i < 0 {
+		return ""
+	}
+	fields := strings.Fields(body[i+len(marker):])
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
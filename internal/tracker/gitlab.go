@@ -0,0 +1,87 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitLab files issues through the GitLab REST API (v4)
+type GitLab struct {
+	client   *apiClient
+	baseURL  string
+	project  string
+	existing map[string]gitlabIssue
+}
+
+type gitlabIssue struct {
+	IID         int    `json:"iid"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	WebURL      string `json:"web_url"`
+}
+
+// NewGitLab creates a GitLab tracker; project is a numeric ID or "group/name" path
+func NewGitLab(baseURL, project, token string) *GitLab {
+	return &GitLab{
+		client: newAPIClient(func(req *http.Request) {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}),
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/api/v4",
+		project: url.PathEscape(project),
+	}
+}
+
+// Upsert creates an issue for the fingerprint or updates (and reopens) the existing one
+func (g *GitLab) Upsert(issue Issue) (Result, error) {
+	if err := g.loadExisting(); err != nil {
+		return Result{}, err
+	}
+
+	if current, ok := g.existing[issue.Fingerprint]; ok {
+		update := map[string]interface{}{"title": issue.Title, "description": issue.Body}
+		action := "updated"
+		if current.State == "closed" {
+			update["state_event"] = "reopen"
+			action = "reopened"
+		}
+		endpoint := fmt.Sprintf("%s/projects/%s/issues/%d", g.baseURL, g.project, current.IID)
+		if err := g.client.do("PUT", endpoint, update, nil); err != nil {
+			return Result{}, err
+		}
+		return Result{Action: action, URL: current.WebURL}, nil
+	}
+
+	var created gitlabIssue
+	create := map[string]interface{}{"title": issue.Title, "description": issue.Body, "labels": Label}
+	if err := g.client.do("POST", fmt.Sprintf("%s/projects/%s/issues", g.baseURL, g.project), create, &created); err != nil {
+		return Result{}, err
+	}
+	g.existing[issue.Fingerprint] = created
+	return Result{Action: "created", URL: created.WebURL}, nil
+}
+
+// loadExisting indexes all secretlint-labelled issues by fingerprint
+func (g *GitLab) loadExisting() error {
+	if g.existing != nil {
+		return nil
+	}
+	g.existing = make(map[string]gitlabIssue)
+
+	for page := 1; ; page++ {
+		var issues []gitlabIssue
+		endpoint := fmt.Sprintf("%s/projects/%s/issues?labels=%s&per_page=100&page=%d", g.baseURL, g.project, Label, page)
+		if err := g.client.do("GET", endpoint, nil, &issues); err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			if fingerprint := extractFingerprint(issue.Description); fingerprint != "" {
+				g.existing[fingerprint] = issue
+			}
+		}
+		if len(issues) < 100 {
+			return nil
+		}
+	}
+}
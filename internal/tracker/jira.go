@@ -0,0 +1,77 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Jira files issues through the Jira REST API (v2). The fingerprint is stored
+// as a label so existing issues can be found with a JQL query.
+type Jira struct {
+	client    *apiClient
+	baseURL   string
+	project   string
+	issueType string
+}
+
+type jiraSearchResult struct {
+	Issues []struct {
+		Key string `json:"key"`
+	} `json:"issues"`
+}
+
+// NewJira creates a Jira tracker authenticating with an email and API token
+func NewJira(baseURL, project, issueType, user, token string) *Jira {
+	return &Jira{
+		client: newAPIClient(func(req *http.Request) {
+			req.SetBasicAuth(user, token)
+		}),
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		project:   project,
+		issueType: issueType,
+	}
+}
+
+// Upsert creates an issue for the fingerprint or updates the existing one
+func (j *Jira) Upsert(issue Issue) (Result, error) {
+	fingerprintLabel := Label + "-" + issue.Fingerprint
+
+	var search jiraSearchResult
+	query := map[string]interface{}{
+		"jql":        fmt.Sprintf(`project = "%s" AND labels = "%s"`, j.project, fingerprintLabel),
+		"fields":     []string{"key"},
+		"maxResults": 1,
+	}
+	if err := j.client.do("POST", j.baseURL+"/rest/api/2/search", query, &search); err != nil {
+		return Result{}, err
+	}
+
+	if len(search.Issues) > 0 {
+		key := search.Issues[0].Key
+		update := map[string]interface{}{
+			"fields": map[string]interface{}{"summary": issue.Title, "description": issue.Body},
+		}
+		if err := j.client.do("PUT", j.baseURL+"/rest/api/2/issue/"+key, update, nil); err != nil {
+			return Result{}, err
+		}
+		return Result{Action: "updated", URL: j.baseURL + "/browse/" + key}, nil
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	create := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": j.project},
+			"issuetype":   map[string]string{"name": j.issueType},
+			"summary":     issue.Title,
+			"description": issue.Body,
+			"labels":      []string{Label, fingerprintLabel},
+		},
+	}
+	if err := j.client.do("POST", j.baseURL+"/rest/api/2/issue", create, &created); err != nil {
+		return Result{}, err
+	}
+	return Result{Action: "created", URL: j.baseURL + "/browse/" + created.Key}, nil
+}
@@ -0,0 +1,183 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"secretlint/internal/report"
+)
+
+// Label marks issues created by secretlint
+const Label = "secretlint"
+
+// Issue is a tracking issue for one secret, identified by its fingerprint
+type Issue struct {
+	Fingerprint string
+	Title       string
+	Body        string
+}
+
+// Result describes what happened to an issue during sync
+type Result struct {
+	Action string // "created", "updated" or "reopened"
+	URL    string
+}
+
+// Tracker creates or updates issues keyed by fingerprint
+type Tracker interface {
+	Upsert(issue Issue) (Result, error)
+}
+
+// fingerprintMarker embeds the fingerprint in issue bodies so reruns find the
+// existing issue instead of filing duplicates
+func fingerprintMarker(fingerprint string) string {
+	return "secretlint-fingerprint: " + fingerprint
+}
+
+// BuildIssues groups report findings by fingerprint, one issue per secret
+func BuildIssues(r *report.Report) []Issue {
+	var order []string
+	groups := make(map[string][]report.Finding)
+	for _, finding := range r.Findings {
+		if _, ok := groups[finding.Fingerprint]; !ok {
+			order = append(order, finding.Fingerprint)
+		}
+		groups[finding.Fingerprint] = append(groups[finding.Fingerprint], finding)
+	}
+	sort.Strings(order)
+
+	var issues []Issue
+	for _, fingerprint := range order {
+		findings := groups[fingerprint]
+		first := findings[0]
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "secretlint detected a **%s** (`%s`).\n\n", first.RuleName, first.RuleID)
+		fmt.Fprintf(&b, "Snippet (masked): `%s`\n\n", first.Snippet)
+		if owners := findingOwners(findings); len(owners) > 0 {
+			// Mentions notify the owning teams on GitHub and GitLab
+			fmt.Fprintf(&b, "Owners: %s\n\n", strings.Join(owners, " "))
+		}
+		b.WriteString("### Locations\n\n")
+		for _, finding := range findings {
+			location := fmt.Sprintf("`%s:%d`", finding.File, finding.Line)
+			if finding.Commit != nil {
+				location += fmt.Sprintf(" (commit %.12s by %s)", finding.Commit.SHA, finding.Commit.Author)
+			}
+			b.WriteString("- " + location + "\n")
+		}
+		b.WriteString("\n### Remediation\n\n")
+		fmt.Fprintf(&b, "%s\n\n", first.Advice)
+		if remediation := first.Remediation; remediation != nil {
+			if remediation.RevokeURL != "" {
+				fmt.Fprintf(&b, "- Revoke: %s\n", remediation.RevokeURL)
+			}
+			if remediation.RotateCommand != "" {
+				fmt.Fprintf(&b, "- Rotate: `%s`\n", remediation.RotateCommand)
+			}
+			for _, link := range remediation.DocLinks {
+				fmt.Fprintf(&b, "- Docs: %s\n", link)
+			}
+		}
+		fmt.Fprintf(&b, "\n<!-- %s -->\n", fingerprintMarker(fingerprint))
+
+		issues = append(issues, Issue{
+			Fingerprint: fingerprint,
+			Title:       fmt.Sprintf("[secretlint] %s in %s", first.RuleID, first.File),
+			Body:        b.String(),
+		})
+	}
+
+	return issues
+}
+
+// findingOwners collects the distinct CODEOWNERS owners of a group of findings
+func findingOwners(findings []report.Finding) []string {
+	seen := make(map[string]bool)
+	var owners []string
+	for _, finding := range findings {
+		for _, owner := range finding.Owners {
+			if !seen[owner] {
+				seen[owner] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// FilterByOwner keeps the findings owned by the given CODEOWNERS owner, so
+// each team's findings can be routed to its own tracker or project
+func FilterByOwner(r *report.Report, owner string) *report.Report {
+	filtered := *r
+	filtered.Findings = nil
+	for _, finding := range r.Findings {
+		for _, findingOwner := range finding.Owners {
+			if strings.EqualFold(findingOwner, owner) {
+				filtered.Findings = append(filtered.Findings, finding)
+				break
+			}
+		}
+	}
+	return &filtered
+}
+
+// apiClient is a minimal JSON-over-HTTP client shared by the tracker backends
+type apiClient struct {
+	http      *http.Client
+	authorize func(req *http.Request)
+}
+
+func newAPIClient(authorize func(req *http.Request)) *apiClient {
+	return &apiClient{
+		http:      &http.Client{Timeout: 30 * time.Second},
+		authorize: authorize,
+	}
+}
+
+// do sends a JSON request and decodes the JSON response into out (if non-nil)
+func (c *apiClient) do(method, url string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.authorize(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s returned %s: %s", method, url, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", url, err)
+	}
+	return nil
+}
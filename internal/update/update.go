@@ -0,0 +1,252 @@
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL and DefaultRepo locate the GitHub releases secretlint updates from
+const (
+	DefaultAPIURL = "https://api.github.com"
+	DefaultRepo   = "ZichenYuan/secretlint"
+)
+
+// ChecksumsAsset lists the SHA-256 of every release asset, one "hash  name" per line
+const ChecksumsAsset = "checksums.txt"
+
+// CheckInterval is how often the opt-in version check runs
+const CheckInterval = 7 * 24 * time.Hour
+
+// Release is a published secretlint release
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// AssetName is the binary built for a platform, e.g. secretlint_linux_amd64
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("secretlint_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Latest looks up the newest release of repo
+func Latest(apiURL, repo string, timeout time.Duration) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(apiURL, "/"), repo)
+	data, err := get(url, timeout)
+	if err != nil {
+		return nil, err
+	}
+	release := &Release{}
+	if err := json.Unmarshal(data, release); err != nil {
+		return nil, fmt.Errorf("failed to parse release from %s: %w", url, err)
+	}
+	if release.Tag == "" {
+		return nil, fmt.Errorf("no release found at %s", url)
+	}
+	return release, nil
+}
+
+// Download fetches the release binary for a platform and verifies it against
+// the release's checksums
+func (r *Release) Download(goos, goarch string) ([]byte, error) {
+	name := AssetName(goos, goarch)
+	binary, checksums := r.asset(name), r.asset(ChecksumsAsset)
+	if binary == nil {
+		return nil, fmt.Errorf("release %s has no build for %s/%s (%s)", r.Tag, goos, goarch, name)
+	}
+	if checksums == nil {
+		return nil, fmt.Errorf("release %s has no %s, so its binaries can't be verified", r.Tag, ChecksumsAsset)
+	}
+
+	sums, err := get(checksums.URL, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	want, err := checksumFor(sums, name)
+	if err != nil {
+		return nil, fmt.Errorf("release %s: %w", r.Tag, err)
+	}
+	data, err := get(binary.URL, 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, want, got)
+	}
+	return data, nil
+}
+
+func (r *Release) asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// checksumFor finds a file's hash in sha256sum output
+func checksumFor(sums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s is not listed in %s", name, ChecksumsAsset)
+}
+
+// Replace swaps the binary at path for data. The new file is written next to
+// it and renamed over it, so an interrupted update leaves the old binary.
+func Replace(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".secretlint-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to write next to %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0111); err != nil {
+		return fmt.Errorf("failed to make update executable: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// Newer reports whether version latest is newer than current. Versions are
+// vMAJOR.MINOR.PATCH; development builds are never compared.
+func Newer(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+func parseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	version = strings.TrimPrefix(version, "v")
+	// Pre-release and build suffixes rank like the release itself
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
+
+// CheckState remembers the last version check, so it runs once per interval
+type CheckState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// StatePath returns where the version check state is kept in the user cache
+func StatePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "secretlint", "update-check.json"), nil
+}
+
+// LoadState reads the check state; a missing or unreadable file means never checked
+func LoadState(path string) CheckState {
+	var state CheckState
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// Save writes the check state
+func (s CheckState) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Due reports whether the next check should run
+func (s CheckState) Due(now time.Time) bool {
+	return now.Sub(s.CheckedAt) >= CheckInterval
+}
+
+func get(url string, timeout time.Duration) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", url, err)
+	}
+	req.Header.Set("User-Agent", "secretlint")
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,129 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Defaults of a Batch: at most five calls a second to each provider, and
+// up to four retries starting one second apart, doubling each time
+const (
+	DefaultInterval = 200 * time.Millisecond
+	DefaultRetries  = 4
+	DefaultBackoff  = time.Second
+
+	// maxBackoff caps a wait between retries; a provider asking to wait
+	// longer gets an unknown result instead of stalling the run
+	maxBackoff = 30 * time.Second
+)
+
+// Request is a secret to verify with the verifier of its rule
+type Request struct {
+	RuleID string
+	Secret string
+}
+
+// Batch verifies many secrets without hammering provider APIs. Providers
+// are checked in parallel, but each is called at most once per Interval.
+// Rate-limited and failed calls are retried with exponential backoff, and
+// a secret is verified once per Batch however often it was found.
+type Batch struct {
+	Interval time.Duration
+	Retries  int
+	Backoff  time.Duration
+
+	mu    sync.Mutex
+	cache map[string]Result
+}
+
+// NewBatch returns a Batch with the default limits
+func NewBatch() *Batch {
+	return &Batch{Interval: DefaultInterval, Retries: DefaultRetries, Backoff: DefaultBackoff}
+}
+
+// CheckAll verifies every request and returns the results in order
+func (b *Batch) CheckAll(requests []Request) []Result {
+	results := make([]Result, len(requests))
+	byRule := make(map[string][]int)
+	var rules []string
+	for i, request := range requests {
+		if _, ok := For(request.RuleID); !ok {
+			results[i] = Result{Status: StatusUnsupported, Detail: "no verifier for rule " + request.RuleID}
+			continue
+		}
+		if _, ok := byRule[request.RuleID]; !ok {
+			rules = append(rules, request.RuleID)
+		}
+		byRule[request.RuleID] = append(byRule[request.RuleID], i)
+	}
+
+	var wg sync.WaitGroup
+	for _, ruleID := range rules {
+		wg.Add(1)
+		go func(verifier Verifier, indexes []int) {
+			defer wg.Done()
+			var last time.Time
+			for _, i := range indexes {
+				key := cacheKey(requests[i])
+				if result, ok := b.cached(key); ok {
+					results[i] = result
+					continue
+				}
+				results[i] = b.check(verifier, requests[i].Secret, &last)
+				b.store(key, results[i])
+			}
+		}(verifiers[ruleID], byRule[ruleID])
+	}
+	wg.Wait()
+	return results
+}
+
+// check calls one provider, keeping to its rate limit and retrying
+// transient failures; last is when the provider was last called
+func (b *Batch) check(verifier Verifier, secret string, last *time.Time) Result {
+	backoff := b.Backoff
+	for attempt := 0; ; attempt++ {
+		if wait := b.Interval - time.Since(*last); wait > 0 {
+			time.Sleep(wait)
+		}
+		*last = time.Now()
+		result := verifier.Verify(secret)
+		if !result.transient || attempt >= b.Retries {
+			return result
+		}
+		if result.retryAfter > backoff {
+			backoff = result.retryAfter
+		}
+		if backoff > maxBackoff {
+			result.Detail = fmt.Sprintf("%s; provider asked to retry after %s", result.Detail, backoff)
+			return result
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (b *Batch) cached(key string) (Result, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	result, ok := b.cache[key]
+	return result, ok
+}
+
+func (b *Batch) store(key string, result Result) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cache == nil {
+		b.cache = make(map[string]Result)
+	}
+	b.cache[key] = result
+}
+
+// cacheKey identifies a secret by its hash, so the cache holds no secrets
+func cacheKey(request Request) string {
+	sum := sha256.Sum256([]byte(request.RuleID + "\x00" + request.Secret))
+	return hex.EncodeToString(sum[:])
+}
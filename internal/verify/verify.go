@@ -0,0 +1,177 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Status is the outcome of checking whether a credential still works
+type Status string
+
+const (
+	// StatusLive means the provider accepted the credential
+	StatusLive Status = "live"
+	// StatusRevoked means the provider rejected the credential
+	StatusRevoked Status = "revoked"
+	// StatusUnknown means the check couldn't be completed
+	StatusUnknown Status = "unknown"
+	// StatusUnsupported means no verifier exists for the rule
+	StatusUnsupported Status = "unsupported"
+)
+
+// Result describes a verification attempt
+type Result struct {
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+
+	// transient is set when trying again later may succeed: the request
+	// failed, was rate limited or hit a server error. retryAfter is the
+	// provider's Retry-After, if it sent one.
+	transient  bool
+	retryAfter time.Duration
+}
+
+// Verifier checks a credential against its provider without changing anything
+type Verifier interface {
+	Verify(secret string) Result
+}
+
+// client is shared by all HTTP verifiers
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// httpVerifier calls a read-only provider endpoint with the credential
+type httpVerifier struct {
+	method  string
+	url     string
+	headers func(secret string) map[string]string
+
+	// interpret maps the response to a result; nil uses the status code only
+	interpret func(resp *http.Response, body []byte) Result
+}
+
+func (v *httpVerifier) Verify(secret string) Result {
+	req, err := http.NewRequest(v.method, v.url, nil)
+	if err != nil {
+		return Result{Status: StatusUnknown, Detail: err.Error()}
+	}
+	for name, value := range v.headers(secret) {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("User-Agent", "secretlint")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{Status: StatusUnknown, Detail: fmt.Sprintf("request failed: %v", err), transient: true}
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		result := statusFromCode(resp.StatusCode)
+		result.transient = true
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			result.retryAfter = time.Duration(seconds) * time.Second
+		}
+		return result
+	}
+	if v.interpret != nil {
+		return v.interpret(resp, body)
+	}
+	return statusFromCode(resp.StatusCode)
+}
+
+// statusFromCode treats 2xx as live and 401/403 as revoked
+func statusFromCode(code int) Result {
+	switch {
+	case code >= 200 && code < 300:
+		return Result{Status: StatusLive, Detail: fmt.Sprintf("HTTP %d", code)}
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return Result{Status: StatusRevoked, Detail: fmt.Sprintf("HTTP %d", code)}
+	default:
+		return Result{Status: StatusUnknown, Detail: fmt.Sprintf("unexpected HTTP %d", code)}
+	}
+}
+
+func bearer(secret string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + secret}
+}
+
+// verifiers maps rule IDs to their provider checks
+var verifiers = map[string]Verifier{
+	"OPENAI_API_KEY": &httpVerifier{
+		method:  http.MethodGet,
+		url:     "https://api.openai.com/v1/models",
+		headers: bearer,
+	},
+	"GITHUB_PAT": &httpVerifier{
+		method: http.MethodGet,
+		url:    "https://api.github.com/user",
+		headers: func(secret string) map[string]string {
+			return map[string]string{"Authorization": "token " + secret, "Accept": "application/vnd.github+json"}
+		},
+	},
+	"STRIPE_LIVE_SK": &httpVerifier{
+		method:  http.MethodGet,
+		url:     "https://api.stripe.com/v1/balance",
+		headers: bearer,
+	},
+	"SLACK_TOKEN": &httpVerifier{
+		method:    http.MethodPost,
+		url:       "https://slack.com/api/auth.test",
+		headers:   bearer,
+		interpret: interpretSlack,
+	},
+}
+
+// interpretSlack reads auth.test's JSON, since Slack answers 200 for bad tokens
+func interpretSlack(resp *http.Response, body []byte) Result {
+	if resp.StatusCode != http.StatusOK {
+		return statusFromCode(resp.StatusCode)
+	}
+	var payload struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		Team  string `json:"team"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Result{Status: StatusUnknown, Detail: "unexpected response from Slack"}
+	}
+	if payload.OK {
+		return Result{Status: StatusLive, Detail: "team " + payload.Team}
+	}
+	switch payload.Error {
+	case "invalid_auth", "token_revoked", "account_inactive", "token_expired", "not_authed":
+		return Result{Status: StatusRevoked, Detail: payload.Error}
+	}
+	return Result{Status: StatusUnknown, Detail: payload.Error}
+}
+
+// For returns the verifier registered for a rule
+func For(ruleID string) (Verifier, bool) {
+	verifier, ok := verifiers[ruleID]
+	return verifier, ok
+}
+
+// Supported lists the rule IDs that can be verified
+func Supported() []string {
+	var ruleIDs []string
+	for ruleID := range verifiers {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	sort.Strings(ruleIDs)
+	return ruleIDs
+}
+
+// Check verifies a secret using the verifier for its rule
+func Check(ruleID, secret string) Result {
+	verifier, ok := For(ruleID)
+	if !ok {
+		return Result{Status: StatusUnsupported, Detail: "no verifier for rule " + ruleID}
+	}
+	return verifier.Verify(secret)
+}
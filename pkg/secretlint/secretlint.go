@@ -0,0 +1,65 @@
+// Package secretlint runs secretlint's scanning pipeline on content from
+// outside git, such as S3 objects, zip uploads or database rows, for
+// programs embedding secretlint as a library.
+//
+//	scanner, err := secretlint.New()
+//	if err != nil {
+//		return err
+//	}
+//	findings, err := scanner.Scan(secretlint.Files(secretlint.File{
+//		Path:     "uploads/42/settings.yml",
+//		Reader:   body,
+//		Metadata: map[string]string{"upload": "42"},
+//	}))
+package secretlint
+
+import (
+	"io"
+
+	"secretlint/internal/report"
+	"secretlint/internal/scanner"
+)
+
+// File is one piece of content to scan, with metadata copied onto its findings
+type File = scanner.File
+
+// FileSource yields files until Next returns io.EOF
+type FileSource = scanner.FileSource
+
+// Finding is a detected secret
+type Finding = scanner.Finding
+
+// Files returns a FileSource yielding the given files in order
+func Files(files ...File) FileSource {
+	return scanner.Files(files...)
+}
+
+// Scanner scans file sources with the rules, .secretignore patterns and
+// baseline of the working directory, like the secretlint CLI
+type Scanner struct {
+	scanner *scanner.SecretScanner
+}
+
+// New loads .secretlintrc.yml, .secretignore and the baseline from the
+// working directory. It fails when .secretlintrc.yml doesn't load; rules
+// that fail to load are warned about on stderr. A Scanner is safe for
+// concurrent use.
+func New() (*Scanner, error) {
+	secretScanner, err := scanner.NewSecretScanner()
+	if err != nil {
+		return nil, err
+	}
+	return &Scanner{scanner: secretScanner}, nil
+}
+
+// Scan scans every file of source. On error it returns the findings of the
+// files scanned before.
+func (s *Scanner) Scan(source FileSource) ([]Finding, error) {
+	return s.scanner.ScanSource(source)
+}
+
+// WriteReport writes findings as the JSON report of 'secretlint scan
+// --format json', with each file's metadata under "metadata"
+func WriteReport(w io.Writer, scope string, findings []Finding) error {
+	return report.New(scope, findings).Write(w)
+}